@@ -2,154 +2,227 @@
 package main
 
 import (
-	"bufio"
-	"crypto/tls"
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
-	"io"
-	"net"
+	"log"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/edward-ap/miniradio/internal/metadata"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("usage: icypeek <stream-url>")
+	jsonOut := flag.Bool("json", false, "print newline-delimited JSON: the chosen strategy, the ICY response headers, then one object per metadata update")
+	blocks := flag.Int("blocks", 0, "stop after this many metadata blocks, empty ones included; without ICY, after this many title updates (0 = no limit)")
+	timeout := flag.Duration("timeout", 0, "stop after this long, e.g. 30s (0 = no limit)")
+	maxRedirects := flag.Int("maxredirects", 5, "number of HTTP redirects to follow before giving up")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: icypeek [-json] [-blocks N] [-timeout D] [-maxredirects N] <stream-url>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
 		return
 	}
-	url := os.Args[1]
+	out := &reporter{json: *jsonOut, enc: json.NewEncoder(os.Stdout)}
 
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("Icy-MetaData", "1")
-	req.Header.Set("User-Agent", "icypeek/1.0")
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	err := peek(ctx, flag.Arg(0), *blocks, *maxRedirects, out)
+	if err != nil && ctx.Err() != nil {
+		// the -timeout limit is a normal way to stop
+		out.note("timeout reached")
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "icypeek:", err)
+		os.Exit(1)
+	}
+}
 
-	tr := &http.Transport{
-		ForceAttemptHTTP2: false,
-		DialContext: (&net.Dialer{
-			Timeout:   7 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 7 * time.Second,
-		TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS10},
+// peek follows url's redirects (up to maxRedirects, printing each hop), then
+// runs the app's metadata provider against the final URL and reports what it
+// finds until the provider gives up, ctx is done, or limit metadata blocks (0 =
+// no limit) have been read. An ICY stream counts every block, including the
+// empty ones sent while the title is unchanged; other sources have no blocks,
+// so there each title update counts as one. It uses the same strategies as MiniRadio itself:
+// HLS ID3, direct ICY, sibling mounts, Shoutcast stats and status-json.
+func peek(ctx context.Context, url string, limit, maxRedirects int, out *reporter) error {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	client := metadata.NewHTTPClient(metadata.ProviderOptions{})
+	final, err := metadata.FollowRedirects(ctx, client, url, maxRedirects, out.hop)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", url, err)
 	}
-	client := &http.Client{Transport: tr}
+	url = final
 
-	// follow redirects manually (up to 2 hops) so icy-metaint header is preserved
-	for i := 0; i <= 2; i++ {
-		resp, err := client.Do(req)
-		if err != nil {
-			panic(err)
+	var (
+		mu              sync.Mutex
+		blocks, updates int
+		icy             bool
+		reachedLimit    bool
+	)
+	// count is called with mu held
+	count := func() {
+		if blocks++; limit > 0 && blocks >= limit {
+			reachedLimit = true
+			stop()
 		}
-		if resp.StatusCode >= 300 && resp.StatusCode < 400 {
-			loc := resp.Header.Get("Location")
-			resp.Body.Close()
-			if loc == "" {
-				panic("redirect without Location")
+	}
+	provider := metadata.NewProvider(nil, log.New(os.Stderr, "", 0), metadata.ProviderOptions{
+		OnICYHeaders: func(_ string, h http.Header) { out.headers(h) },
+		OnICYBlock: func(string, string) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !reachedLimit {
+				icy = true
+				count()
 			}
-			req, _ = http.NewRequest("GET", loc, nil)
-			req.Header.Set("Icy-MetaData", "1")
-			req.Header.Set("User-Agent", "icypeek/1.0")
-			continue
+		},
+	})
+	provider.Run(ctx, url, metadata.StrategyHint{}, func(info metadata.Info) {
+		mu.Lock()
+		defer mu.Unlock()
+		if reachedLimit || info == (metadata.Info{}) {
+			return
 		}
-
-		fmt.Println("=== Response Headers ===")
-		for k, v := range resp.Header {
-			fmt.Printf("%s: %s\n", k, strings.Join(v, ", "))
+		updates++
+		out.update(updates, info)
+		if !icy && info.Title != "" {
+			count()
 		}
-		fmt.Println("========================")
+	}, out.strategy)
 
-		metaInt := 0
-		fmt.Sscanf(resp.Header.Get("Icy-Metaint"), "%d", &metaInt)
-		if metaInt <= 0 {
-			fmt.Println("No icy-metaint => server does not send ICY metadata")
-			return
-		}
+	mu.Lock()
+	defer mu.Unlock()
+	switch {
+	case reachedLimit:
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case updates == 0:
+		return fmt.Errorf("no metadata found for %s", url)
+	}
+	return fmt.Errorf("metadata stream ended")
+}
 
-		defer resp.Body.Close()
-		r := bufio.NewReader(resp.Body)
-		audioBuf := make([]byte, 4096)
-		metaBuf := make([]byte, 16*255)
-
-		for block := 1; ; block++ {
-			// skip audio bytes
-			left := metaInt
-			for left > 0 {
-				chunk := left
-				if chunk > len(audioBuf) {
-					chunk = len(audioBuf)
-				}
-				n, err := io.ReadFull(r, audioBuf[:chunk])
-				left -= n
-				if err != nil {
-					fmt.Println("audio read:", err)
-					return
-				}
-			}
-			// metadata block length
-			lenByte, err := r.ReadByte()
-			if err != nil {
-				fmt.Println("len read:", err)
-				return
-			}
-			if lenByte == 0 {
-				continue
-			}
+// updateRecord is one -json metadata update.
+type updateRecord struct {
+	Block       int    `json:"block"`
+	Raw         string `json:"raw,omitempty"`
+	StreamTitle string `json:"streamTitle"`
+	Station     string `json:"station,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+	Bitrate     int    `json:"bitrate,omitempty"`
+	ArtworkURL  string `json:"artworkUrl,omitempty"`
+	Timestamp   string `json:"timestamp"`
+}
 
-			mlen := int(lenByte) * 16
-			if _, err := io.ReadFull(r, metaBuf[:mlen]); err != nil {
-				fmt.Println("meta read:", err)
-				return
-			}
-			raw := string(metaBuf[:mlen])
-			// trim at NUL
-			if i := strings.IndexByte(raw, 0x00); i >= 0 {
-				raw = raw[:i]
-			}
+// strategyRecord is the -json line announcing where metadata comes from.
+type strategyRecord struct {
+	Strategy string `json:"strategy"`
+	URL      string `json:"url"`
+}
+
+// reporter prints results either for people or, with json set, as one JSON
+// object per line on stdout; diagnostics then go to stderr so the stream stays
+// machine-readable.
+type reporter struct {
+	json bool
+	enc  *json.Encoder
+}
 
-			fmt.Printf("\n[Block %d] RAW: %q\n", block, raw)
+// headersRecord is the -json line with the response headers of an ICY
+// connection.
+type headersRecord struct {
+	Headers map[string]string `json:"headers"`
+}
 
-			title := parseStreamTitle(raw)
-			if title != "" {
-				fmt.Printf("[Block %d] StreamTitle: %s\n", block, title)
-			}
+func (r *reporter) headers(h http.Header) {
+	if r.json {
+		flat := make(map[string]string, len(h))
+		for k, v := range h {
+			flat[k] = strings.Join(v, ", ")
 		}
+		_ = r.enc.Encode(headersRecord{Headers: flat})
+		return
+	}
+	fmt.Println("=== Response Headers ===")
+	for k, v := range h {
+		fmt.Printf("%s: %s\n", k, strings.Join(v, ", "))
 	}
+	fmt.Println("========================")
 }
 
-func parseStreamTitle(s string) string {
-	meta := strings.TrimSpace(s)
-	if meta == "" {
-		return ""
-	}
-	lower := strings.ToLower(meta)
-	pos := strings.Index(lower, "streamtitle='")
-	delim := byte('\'')
-	if pos < 0 {
-		pos = strings.Index(lower, "streamtitle=\"")
-		delim = '"'
-	}
-	if pos < 0 {
-		return ""
-	}
-	start := pos + len("streamtitle=") + 1
-	if start >= len(meta) {
-		return ""
-	}
-	segment := meta[start:]
-	end := len(segment)
-	for i := 0; i < len(segment); i++ {
-		if segment[i] != delim {
-			continue
-		}
-		next := byte(0)
-		if i+1 < len(segment) {
-			next = segment[i+1]
-		}
-		if next == ';' || next == 0 || next == delim {
-			end = i
-			break
-		}
+// hopRecord is the -json line for one followed redirect.
+type hopRecord struct {
+	Redirect string `json:"redirect"`
+}
+
+func (r *reporter) hop(url string) {
+	if r.json {
+		_ = r.enc.Encode(hopRecord{Redirect: url})
+		return
+	}
+	fmt.Printf("-> %s\n", url)
+}
+
+func (r *reporter) strategy(h metadata.StrategyHint) {
+	if r.json {
+		_ = r.enc.Encode(strategyRecord{Strategy: h.Type, URL: h.URL})
+		return
+	}
+	fmt.Printf("=== Strategy: %s %s ===\n", h.Type, h.URL)
+}
+
+func (r *reporter) update(n int, info metadata.Info) {
+	if r.json {
+		_ = r.enc.Encode(updateRecord{
+			Block:       n,
+			Raw:         info.Raw,
+			StreamTitle: info.Title,
+			Station:     info.Station,
+			Genre:       info.Genre,
+			Bitrate:     info.Bitrate,
+			ArtworkURL:  info.ArtworkURL,
+			Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+	fmt.Println()
+	if info.Raw != "" {
+		fmt.Printf("[Block %d] RAW: %q\n", n, info.Raw)
+	}
+	if info.Station != "" {
+		fmt.Printf("[Block %d] Station: %s\n", n, info.Station)
+	}
+	if info.Genre != "" || info.Bitrate > 0 {
+		fmt.Printf("[Block %d] Genre: %s, %d kbps\n", n, info.Genre, info.Bitrate)
+	}
+	if info.Title != "" {
+		fmt.Printf("[Block %d] StreamTitle: %s\n", n, info.Title)
+	}
+	if info.ArtworkURL != "" {
+		fmt.Printf("[Block %d] Artwork: %s\n", n, info.ArtworkURL)
+	}
+}
+
+func (r *reporter) note(a ...any) {
+	if r.json {
+		fmt.Fprintln(os.Stderr, a...)
+		return
 	}
-	return strings.TrimSpace(segment[:end])
+	fmt.Println(a...)
 }
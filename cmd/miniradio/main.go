@@ -2,15 +2,26 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"os"
 
+	config "github.com/edward-ap/miniradio/internal/config"
 	radioapp "github.com/edward-ap/miniradio/internal/radioapp"
 )
 
 func main() {
 	trace := flag.Bool("traceLog", false, "enable verbose libVLC logging to vlc.log")
+	url := flag.String("url", "", "start playing this stream URL once VLC is ready")
+	preset := flag.Int("preset", 0, "start playing preset `N` (1-based) once VLC is ready")
+	configPath := flag.String("config", "", "keep settings in `file` instead of the user config directory (overrides "+config.EnvConfigPath+")")
 	flag.Parse()
 	radioapp.SetTraceLogEnabled(*trace)
+	config.SetPath(*configPath)
 
-	app := radioapp.NewApp()
+	app, err := radioapp.NewApp(radioapp.StartOptions{URL: *url, Preset: *preset})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "miniradio:", err)
+		os.Exit(2)
+	}
 	app.Run()
 }
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"runtime"
 	"syscall"
 	"unsafe"
 )
@@ -55,27 +56,89 @@ type grpIconEntry struct {
 func main() {
 	exePath := flag.String("exe", "", "path to target exe")
 	iconPath := flag.String("icon", "", "path to .ico file")
+	pngPath := flag.String("png", "", "path to a square .png (256 px or larger) to build the icon from, instead of -icon")
+	clean := flag.Bool("clean", false, "delete all existing resources except version info and the manifest (default: keep them and replace only the icons)")
+	var ver versionInfo
+	flag.StringVar(&ver.FileVersion, "fileversion", "", "file version to stamp, e.g. 1.4.0.0")
+	flag.StringVar(&ver.ProductVersion, "productversion", "", "product version (default: -fileversion)")
+	flag.StringVar(&ver.ProductName, "productname", "", "product name shown in Properties→Details")
+	flag.StringVar(&ver.CompanyName, "companyname", "", "company name")
+	flag.StringVar(&ver.Copyright, "copyright", "", "legal copyright line")
+	flag.StringVar(&ver.Description, "description", "", "file description")
+	manifestPath := flag.String("manifest", "", "path to an application manifest (.xml) to embed, e.g. for DPI awareness")
+	arch := flag.String("arch", "", "fail unless the exe is built for this architecture: x86, x64 or arm64")
 	flag.Parse()
 
-	if *exePath == "" || *iconPath == "" {
+	if *exePath == "" || (*iconPath == "") == (*pngPath == "") {
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	data, err := os.ReadFile(*iconPath)
+	found, err := checkPE(*exePath, *arch)
 	if err != nil {
-		exitErr(fmt.Errorf("read icon: %w", err))
+		exitErr(err)
+	}
+	fmt.Printf("%s: %s executable\n", *exePath, found)
+
+	entries, err := loadEntries(*iconPath, *pngPath)
+	if err != nil {
+		exitErr(err)
+	}
+	var version []byte
+	if !ver.empty() {
+		if version, err = buildVersionInfo(ver, *exePath); err != nil {
+			exitErr(err)
+		}
+	}
+	var manifest []byte
+	if *manifestPath != "" {
+		if manifest, err = readManifest(*manifestPath); err != nil {
+			exitErr(err)
+		}
+	}
+	// Clean mode wipes the resource table, so version info and the manifest
+	// are read first and written back; the default mode instead removes only
+	// the old icons, so a previous icon with more sizes leaves nothing behind.
+	// A stamped version or manifest replaces the existing one in either mode.
+	var keep, replace []uint16
+	if version == nil {
+		keep = append(keep, rtVersion)
+	} else {
+		replace = append(replace, rtVersion)
+	}
+	if manifest == nil {
+		keep = append(keep, rtManifest)
+	} else {
+		replace = append(replace, rtManifest)
+	}
+	var restore, stale []resource
+	if *clean {
+		restore, err = readResources(*exePath, keep, true)
+	} else {
+		stale, err = readResources(*exePath, append(replace, rtIcon, rtGroupIcon), false)
 	}
-	entries, err := parseICO(data)
 	if err != nil {
-		exitErr(fmt.Errorf("parse icon: %w", err))
+		exitErr(err)
 	}
-	handle, err := beginUpdate(*exePath)
+	handle, err := beginUpdate(*exePath, *clean)
 	if err != nil {
 		exitErr(err)
 	}
 	defer endUpdate(handle)
 
+	for _, r := range restore {
+		if err := updateNamedResource(handle, r.typ, r.name, r.lang, r.data); err != nil {
+			exitErr(fmt.Errorf("restore resource %d/%s: %w", r.typ, r.name, err))
+		}
+	}
+	for _, r := range stale {
+		if r.rewritten(len(entries)) {
+			continue
+		}
+		if err := updateNamedResource(handle, r.typ, r.name, r.lang, nil); err != nil {
+			exitErr(fmt.Errorf("remove old resource %d/%s: %w", r.typ, r.name, err))
+		}
+	}
 	for i, entry := range entries {
 		if err := updateIcon(handle, uint16(i+1), entry.Data); err != nil {
 			exitErr(err)
@@ -88,6 +151,41 @@ func main() {
 	if err := updateGroup(handle, defaultGroup, group); err != nil {
 		exitErr(err)
 	}
+	if version != nil {
+		if err := updateResource(handle, rtVersion, versionID, version); err != nil {
+			exitErr(fmt.Errorf("write version info: %w", err))
+		}
+	}
+	if manifest != nil {
+		if err := updateResource(handle, rtManifest, manifestID, manifest); err != nil {
+			exitErr(fmt.Errorf("write manifest: %w", err))
+		}
+	}
+}
+
+// loadEntries reads the icon images from the .ico at iconPath or, when
+// pngPath is set, synthesizes them from that PNG.
+func loadEntries(iconPath, pngPath string) ([]icoEntry, error) {
+	if pngPath != "" {
+		data, err := os.ReadFile(pngPath)
+		if err != nil {
+			return nil, fmt.Errorf("read png: %w", err)
+		}
+		entries, err := entriesFromPNG(data)
+		if err != nil {
+			return nil, fmt.Errorf("convert png: %w", err)
+		}
+		return entries, nil
+	}
+	data, err := os.ReadFile(iconPath)
+	if err != nil {
+		return nil, fmt.Errorf("read icon: %w", err)
+	}
+	entries, err := parseICO(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse icon: %w", err)
+	}
+	return entries, nil
 }
 
 type icoEntry struct {
@@ -120,12 +218,18 @@ func parseICO(data []byte) ([]icoEntry, error) {
 	return entries, nil
 }
 
-func beginUpdate(exe string) (syscall.Handle, error) {
+// beginUpdate opens exe for resource updates. With deleteExisting the
+// resource table starts out empty; otherwise existing resources are kept.
+func beginUpdate(exe string, deleteExisting bool) (syscall.Handle, error) {
 	ptr, err := syscall.UTF16PtrFromString(exe)
 	if err != nil {
 		return 0, err
 	}
-	handle, _, callErr := procBeginUpdateResource.Call(uintptr(unsafe.Pointer(ptr)), uintptr(0))
+	var del uintptr
+	if deleteExisting {
+		del = 1
+	}
+	handle, _, callErr := procBeginUpdateResource.Call(uintptr(unsafe.Pointer(ptr)), del)
 	if handle == 0 {
 		if callErr != nil && callErr != syscall.Errno(0) {
 			return 0, callErr
@@ -174,14 +278,27 @@ func updateResource(handle syscall.Handle, resType uint16, id uint16, data []byt
 	if len(data) == 0 {
 		return fmt.Errorf("resource data empty")
 	}
+	return updateNamedResource(handle, resType, resName{id: id}, langNeutral, data)
+}
+
+// updateNamedResource writes data as resource name/lang; empty data deletes
+// the resource.
+func updateNamedResource(handle syscall.Handle, resType uint16, name resName, lang uint16, data []byte) error {
+	np, keep := name.ptr()
+	var dp uintptr
+	if len(data) > 0 {
+		dp = uintptr(unsafe.Pointer(&data[0]))
+	}
 	ret, _, err := procUpdateResource.Call(
 		uintptr(handle),
 		uintptr(resType),
-		uintptr(id),
-		uintptr(langNeutral),
-		uintptr(unsafe.Pointer(&data[0])),
+		np,
+		uintptr(lang),
+		dp,
 		uintptr(len(data)),
 	)
+	runtime.KeepAlive(keep)
+	runtime.KeepAlive(data)
 	if ret == 0 {
 		if err != nil && err != syscall.Errno(0) {
 			return err
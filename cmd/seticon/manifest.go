@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// manifestID is the RT_MANIFEST name Windows loads for an executable
+// (CREATEPROCESS_MANIFEST_RESOURCE_ID).
+const manifestID = 1
+
+// readManifest loads an application manifest and checks that it is
+// well-formed UTF-8 XML with a root element, so a typo cannot produce an exe
+// that Windows refuses to start. A UTF-8 byte order mark is dropped.
+func readManifest(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("manifest %s is not valid UTF-8", path)
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("manifest %s: %w", path, err)
+		}
+		if _, ok := tok.(xml.StartElement); ok {
+			root = true
+		}
+	}
+	if !root {
+		return nil, fmt.Errorf("manifest %s has no root element", path)
+	}
+	return data, nil
+}
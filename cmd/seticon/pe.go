@@ -0,0 +1,41 @@
+package main
+
+import (
+	"debug/pe"
+	"fmt"
+	"strings"
+)
+
+// peArch names the IMAGE_FILE_HEADER.Machine values seticon expects to see.
+func peArch(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "x86"
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "x64"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		return "arm"
+	}
+	return fmt.Sprintf("unknown machine 0x%04x", machine)
+}
+
+// checkPE reads the PE headers of exe before any resource is touched and
+// returns its architecture. It fails with a plain message when the file is
+// not a PE image at all, is a DLL, or does not match want ("" = any).
+func checkPE(exe, want string) (string, error) {
+	f, err := pe.Open(exe)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a Windows executable (PE): %w", exe, err)
+	}
+	defer f.Close()
+	if f.FileHeader.Characteristics&pe.IMAGE_FILE_DLL != 0 {
+		return "", fmt.Errorf("%s is a DLL, not an executable", exe)
+	}
+	arch := peArch(f.FileHeader.Machine)
+	if want != "" && !strings.EqualFold(want, arch) {
+		return arch, fmt.Errorf("%s is a %s executable, expected %s", exe, arch, want)
+	}
+	return arch, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// icoSizes are the icon resolutions synthesized from a -png source.
+var icoSizes = []int{16, 32, 48, 256}
+
+// bitmapInfoHeader is the BITMAPINFOHEADER that starts a BMP icon image.
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// entriesFromPNG decodes a PNG and builds one icon entry per icoSizes size:
+// PNG-compressed for 256 px, as Windows Vista+ expects, and 32-bit BMP for
+// the smaller sizes older shell code still reads.
+func entriesFromPNG(data []byte) ([]icoEntry, error) {
+	src, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if b := src.Bounds(); b.Dx() == 0 || b.Dy() == 0 {
+		return nil, fmt.Errorf("empty image")
+	}
+	entries := make([]icoEntry, 0, len(icoSizes))
+	for _, size := range icoSizes {
+		img := resize(src, size)
+		var chunk []byte
+		if size >= 256 {
+			buf := &bytes.Buffer{}
+			if err := png.Encode(buf, img); err != nil {
+				return nil, err
+			}
+			chunk = buf.Bytes()
+		} else {
+			chunk, err = encodeIconBMP(img)
+			if err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, icoEntry{
+			Meta: iconDirEntry{
+				// 256 is stored as 0 in the one-byte fields
+				Width:      uint8(size % 256),
+				Height:     uint8(size % 256),
+				Planes:     1,
+				BitCount:   32,
+				BytesInRes: uint32(len(chunk)),
+			},
+			Data: chunk,
+		})
+	}
+	return entries, nil
+}
+
+// resize scales src to a size×size square by averaging the source pixels
+// covered by each target pixel, falling back to the nearest pixel when
+// enlarging. Non-square images are centred on a transparent canvas first.
+func resize(src image.Image, size int) *image.NRGBA {
+	sq := squareCanvas(src)
+	n := sq.Bounds().Dx()
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, size, n)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, size, n)
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// RGBA returns alpha-premultiplied values, so transparent
+					// pixels do not bleed colour into the edges
+					pr, pg, pb, pa := sq.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+			c := color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			}
+			dst.Set(x, y, c)
+		}
+	}
+	return dst
+}
+
+// span returns the source pixel range [lo, hi) covered by target pixel i when
+// scaling n pixels to size; it always covers at least one pixel.
+func span(i, size, n int) (int, int) {
+	lo := i * n / size
+	hi := (i + 1) * n / size
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+// squareCanvas returns src as an NRGBA square, padding the shorter side.
+func squareCanvas(src image.Image) *image.NRGBA {
+	b := src.Bounds()
+	n := b.Dx()
+	if b.Dy() > n {
+		n = b.Dy()
+	}
+	sq := image.NewNRGBA(image.Rect(0, 0, n, n))
+	off := image.Pt((n-b.Dx())/2, (n-b.Dy())/2)
+	draw.Draw(sq, image.Rectangle{Min: off, Max: off.Add(b.Size())}, src, b.Min, draw.Src)
+	return sq
+}
+
+// encodeIconBMP writes img as an icon BMP: a BITMAPINFOHEADER with doubled
+// height, bottom-up 32-bit BGRA pixels, then the 1-bit AND mask (all zero,
+// since the alpha channel carries transparency).
+func encodeIconBMP(img *image.NRGBA) ([]byte, error) {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	maskStride := ((w + 31) / 32) * 4
+	hdr := bitmapInfoHeader{
+		Size:      40,
+		Width:     int32(w),
+		Height:    int32(h * 2),
+		Planes:    1,
+		BitCount:  32,
+		SizeImage: uint32(w*h*4 + maskStride*h),
+	}
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, hdr); err != nil {
+		return nil, err
+	}
+	for y := h - 1; y >= 0; y-- {
+		for x := 0; x < w; x++ {
+			c := img.NRGBAAt(x, y)
+			buf.Write([]byte{c.B, c.G, c.R, c.A})
+		}
+	}
+	buf.Write(make([]byte, maskStride*h))
+	return buf.Bytes(), nil
+}
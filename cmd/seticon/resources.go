@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	rtVersion  = 16
+	rtManifest = 24
+
+	loadLibraryAsDatafile      = 0x2
+	loadLibraryAsImageResource = 0x20
+	errResourceDataNotFound    = 1812
+	errResourceTypeNotFound    = 1813
+)
+
+var (
+	procLoadLibraryEx         = kernel.NewProc("LoadLibraryExW")
+	procFreeLibrary           = kernel.NewProc("FreeLibrary")
+	procEnumResourceNames     = kernel.NewProc("EnumResourceNamesW")
+	procEnumResourceLanguages = kernel.NewProc("EnumResourceLanguagesW")
+	procFindResourceEx        = kernel.NewProc("FindResourceExW")
+	procSizeofResource        = kernel.NewProc("SizeofResource")
+	procLoadResource          = kernel.NewProc("LoadResource")
+	procLockResource          = kernel.NewProc("LockResource")
+)
+
+// resName is a resource name: an integer ID or, when str is set, a string.
+type resName struct {
+	id  uint16
+	str string
+}
+
+func (n resName) String() string {
+	if n.str != "" {
+		return n.str
+	}
+	return fmt.Sprint(n.id)
+}
+
+// resource is one language variant of a named resource in an executable.
+type resource struct {
+	typ  uint16
+	name resName
+	lang uint16
+	data []byte
+}
+
+// rewritten reports whether r is one of the resources this run writes itself,
+// given the number of icon images.
+func (r resource) rewritten(icons int) bool {
+	if r.name.str != "" || r.lang != langNeutral {
+		return false
+	}
+	switch r.typ {
+	case rtIcon:
+		return r.name.id >= 1 && int(r.name.id) <= icons
+	case rtGroupIcon:
+		return r.name.id == defaultGroup
+	case rtVersion:
+		return r.name.id == versionID
+	case rtManifest:
+		return r.name.id == manifestID
+	}
+	return false
+}
+
+// readResources lists the resources of the given types in exe, copying their
+// data when withData is set. The file is loaded as a data file and released
+// before returning, so it can be opened for update afterwards.
+func readResources(exe string, types []uint16, withData bool) ([]resource, error) {
+	ptr, err := syscall.UTF16PtrFromString(exe)
+	if err != nil {
+		return nil, err
+	}
+	mod, _, callErr := procLoadLibraryEx.Call(uintptr(unsafe.Pointer(ptr)), 0,
+		loadLibraryAsDatafile|loadLibraryAsImageResource)
+	if mod == 0 {
+		return nil, fmt.Errorf("load %s: %v", exe, callErr)
+	}
+	defer procFreeLibrary.Call(mod)
+
+	// callbacks are created once: Go keeps every NewCallback for the life of
+	// the process and allows only a limited number of them
+	var (
+		names []resName
+		langs []uint16
+	)
+	nameCb := syscall.NewCallback(func(_, _, name, _ uintptr) uintptr {
+		names = append(names, readResName(name))
+		return 1
+	})
+	langCb := syscall.NewCallback(func(_, _, _, lang, _ uintptr) uintptr {
+		langs = append(langs, uint16(lang))
+		return 1
+	})
+	var out []resource
+	for _, typ := range types {
+		names = names[:0]
+		ret, _, callErr := procEnumResourceNames.Call(mod, uintptr(typ), nameCb, 0)
+		if ret == 0 {
+			// 1812 means the exe has no resource section at all, as a
+			// fresh Go binary does; 1813 that it has none of this type
+			if callErr == syscall.Errno(errResourceTypeNotFound) || callErr == syscall.Errno(errResourceDataNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("enumerate resources of type %d: %v", typ, callErr)
+		}
+		for _, name := range names {
+			np, keep := name.ptr()
+			langs = langs[:0]
+			procEnumResourceLanguages.Call(mod, uintptr(typ), np, langCb, 0)
+			for _, lang := range langs {
+				r := resource{typ: typ, name: name, lang: lang}
+				if withData {
+					if r.data, err = loadResourceData(mod, typ, np, lang); err != nil {
+						return nil, fmt.Errorf("read resource %d/%s: %w", typ, name, err)
+					}
+				}
+				out = append(out, r)
+			}
+			runtime.KeepAlive(keep)
+		}
+	}
+	return out, nil
+}
+
+// loadResourceData copies the bytes of one resource out of the loaded module.
+func loadResourceData(mod uintptr, typ uint16, name uintptr, lang uint16) ([]byte, error) {
+	info, _, err := procFindResourceEx.Call(mod, uintptr(typ), name, uintptr(lang))
+	if info == 0 {
+		return nil, err
+	}
+	size, _, _ := procSizeofResource.Call(mod, info)
+	global, _, err := procLoadResource.Call(mod, info)
+	if global == 0 {
+		return nil, err
+	}
+	addr, _, err := procLockResource.Call(global)
+	if addr == 0 || size == 0 {
+		return nil, fmt.Errorf("lock resource: %v", err)
+	}
+	data := make([]byte, size)
+	copy(data, unsafe.Slice((*byte)(uintptrToPointer(addr)), size))
+	return data, nil
+}
+
+// readResName decodes the name argument of a resource enumeration callback,
+// which is either an integer ID (MAKEINTRESOURCE) or a UTF-16 string.
+func readResName(p uintptr) resName {
+	if p < 0x10000 {
+		return resName{id: uint16(p)}
+	}
+	var chars []uint16
+	for s := (*uint16)(uintptrToPointer(p)); *s != 0; s = (*uint16)(unsafe.Add(unsafe.Pointer(s), 2)) {
+		chars = append(chars, *s)
+	}
+	return resName{str: syscall.UTF16ToString(chars)}
+}
+
+// ptr returns n as a resource-name argument; the second value keeps a string
+// name's buffer alive and must stay referenced until the call completes.
+func (n resName) ptr() (uintptr, *uint16) {
+	if n.str == "" {
+		return uintptr(n.id), nil
+	}
+	p, err := syscall.UTF16PtrFromString(n.str)
+	if err != nil {
+		return 0, nil
+	}
+	return uintptr(unsafe.Pointer(p)), p
+}
+
+// uintptrToPointer turns an address returned by a WinAPI call into a pointer
+// without the uintptr-to-Pointer conversion go vet rejects.
+func uintptrToPointer(p uintptr) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&p))
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// versionID is the RT_VERSION resource name Windows reads.
+	versionID = 1
+	// langCodepage is the StringTable key for U.S. English, Unicode (1200).
+	langCodepage = "040904B0"
+)
+
+// versionInfo holds the Properties→Details fields stamped by -fileversion and
+// friends. Empty strings are left out of the resource.
+type versionInfo struct {
+	FileVersion    string
+	ProductVersion string
+	ProductName    string
+	CompanyName    string
+	Copyright      string
+	Description    string
+}
+
+// empty reports whether no version flag was given.
+func (v versionInfo) empty() bool {
+	return v == versionInfo{}
+}
+
+// vsFixedFileInfo is VS_FIXEDFILEINFO.
+type vsFixedFileInfo struct {
+	Signature        uint32
+	StrucVersion     uint32
+	FileVersionMS    uint32
+	FileVersionLS    uint32
+	ProductVersionMS uint32
+	ProductVersionLS uint32
+	FileFlagsMask    uint32
+	FileFlags        uint32
+	FileOS           uint32
+	FileType         uint32
+	FileSubtype      uint32
+	FileDateMS       uint32
+	FileDateLS       uint32
+}
+
+// versionNode is one block of the VS_VERSIONINFO tree: a key, an optional
+// value, and child blocks. text marks values stored as UTF-16 strings, whose
+// length is counted in characters rather than bytes.
+type versionNode struct {
+	key      string
+	value    []byte
+	text     bool
+	children []versionNode
+}
+
+// buildVersionInfo encodes v as a VS_VERSIONINFO resource for exe.
+func buildVersionInfo(v versionInfo, exe string) ([]byte, error) {
+	fileMS, fileLS, err := parseVersion(v.FileVersion)
+	if err != nil {
+		return nil, fmt.Errorf("-fileversion: %w", err)
+	}
+	if v.ProductVersion == "" {
+		v.ProductVersion = v.FileVersion
+	}
+	prodMS, prodLS, err := parseVersion(v.ProductVersion)
+	if err != nil {
+		return nil, fmt.Errorf("-productversion: %w", err)
+	}
+	fixed := &bytes.Buffer{}
+	if err := binary.Write(fixed, binary.LittleEndian, vsFixedFileInfo{
+		Signature:        0xFEEF04BD,
+		StrucVersion:     0x00010000,
+		FileVersionMS:    fileMS,
+		FileVersionLS:    fileLS,
+		ProductVersionMS: prodMS,
+		ProductVersionLS: prodLS,
+		FileFlagsMask:    0x3F,
+		FileOS:           0x00040004, // VOS_NT_WINDOWS32
+		FileType:         1,          // VFT_APP
+	}); err != nil {
+		return nil, err
+	}
+
+	base := filepath.Base(exe)
+	var strs []versionNode
+	for _, kv := range [][2]string{
+		{"CompanyName", v.CompanyName},
+		{"FileDescription", v.Description},
+		{"FileVersion", v.FileVersion},
+		{"InternalName", strings.TrimSuffix(base, filepath.Ext(base))},
+		{"LegalCopyright", v.Copyright},
+		{"OriginalFilename", base},
+		{"ProductName", v.ProductName},
+		{"ProductVersion", v.ProductVersion},
+	} {
+		if kv[1] == "" {
+			continue
+		}
+		strs = append(strs, versionNode{key: kv[0], value: utf16z(kv[1]), text: true})
+	}
+	translation := make([]byte, 4)
+	binary.LittleEndian.PutUint16(translation[0:], 0x0409)
+	binary.LittleEndian.PutUint16(translation[2:], 1200)
+
+	root := versionNode{
+		key:   "VS_VERSION_INFO",
+		value: fixed.Bytes(),
+		children: []versionNode{
+			{key: "StringFileInfo", text: true, children: []versionNode{
+				{key: langCodepage, text: true, children: strs},
+			}},
+			{key: "VarFileInfo", text: true, children: []versionNode{
+				{key: "Translation", value: translation},
+			}},
+		},
+	}
+	return root.encode(), nil
+}
+
+// encode writes the node as wLength, wValueLength, wType, the key, the value
+// and the children, each of the last three starting on a 32-bit boundary.
+func (n versionNode) encode() []byte {
+	buf := &bytes.Buffer{}
+	valueLen := len(n.value)
+	var typ uint16
+	if n.text {
+		typ = 1
+		valueLen /= 2
+	}
+	hdr := [3]uint16{0, uint16(valueLen), typ}
+	_ = binary.Write(buf, binary.LittleEndian, hdr)
+	buf.Write(utf16z(n.key))
+	pad32(buf)
+	buf.Write(n.value)
+	for _, c := range n.children {
+		pad32(buf)
+		buf.Write(c.encode())
+	}
+	out := buf.Bytes()
+	binary.LittleEndian.PutUint16(out, uint16(len(out)))
+	return out
+}
+
+// pad32 zero-fills buf to a multiple of four bytes.
+func pad32(buf *bytes.Buffer) {
+	for buf.Len()%4 != 0 {
+		buf.WriteByte(0)
+	}
+}
+
+// utf16z encodes s as NUL-terminated little-endian UTF-16.
+func utf16z(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 0, 2*len(units)+2)
+	for _, u := range units {
+		out = binary.LittleEndian.AppendUint16(out, u)
+	}
+	return append(out, 0, 0)
+}
+
+// parseVersion splits "major.minor.patch.build" (missing parts are 0) into
+// the two DWORDs of VS_FIXEDFILEINFO. An empty string is version 0.0.0.0.
+func parseVersion(s string) (ms, ls uint32, err error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return 0, 0, nil
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 4 {
+		return 0, 0, fmt.Errorf("%q has more than four parts", s)
+	}
+	var n [4]uint32
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%q is not a version like 1.2.3.4", s)
+		}
+		n[i] = uint32(v)
+	}
+	return n[0]<<16 | n[1], n[2]<<16 | n[3], nil
+}
@@ -18,6 +18,12 @@ const (
 	AppConfigSubdir = "MiniRadio"
 	// AppConfigName is the JSON file stored on disk.
 	AppConfigName = "config.json"
+	// AppHistoryName is the optional now-playing history kept beside the config.
+	AppHistoryName = "history.json"
+	// EnvConfigPath names the environment variable that points MiniRadio at
+	// a config file outside the user config directory, e.g. for a portable
+	// install; SetPath takes precedence over it.
+	EnvConfigPath = "MINIRADIO_CONFIG"
 
 	// DefaultWidth is the preferred window width when no persisted value exists.
 	DefaultWidth = 680
@@ -31,46 +37,315 @@ const (
 	DefaultAPIEndpoint = "https://de1.api.radio-browser.info"
 	// MinWindowWidth keeps right-aligned controls visible even on first launch.
 	MinWindowWidth = 860
+	// MinPresets is the number of preset slots always available; it matches
+	// the 1…9, 0 keyboard shortcuts.
+	MinPresets = 10
+	// MaxPresets bounds PresetCount so a typo cannot build a huge drawer.
+	MaxPresets = 100
+	// TickerLeft and TickerRight are the accepted TickerDirection values.
+	TickerLeft  = "left"
+	TickerRight = "right"
+	// SizeCompact, SizeNormal and SizeLarge are the accepted SizeMode values.
+	SizeCompact = "compact"
+	SizeNormal  = "normal"
+	SizeLarge   = "large"
+	// ThemeLight and ThemeSystem are the accepted Theme values besides the
+	// default dark theme.
+	ThemeLight  = "light"
+	ThemeSystem = "system"
+	// TLS12 and TLS13 are the accepted MetadataMinTLS values.
+	TLS12 = "1.2"
+	TLS13 = "1.3"
+	// IPFamilyAuto, IPFamilyV4 and IPFamilyV6 are the accepted IPFamily
+	// values; auto is stored as "".
+	IPFamilyAuto = "auto"
+	IPFamilyV4   = "ipv4"
+	IPFamilyV6   = "ipv6"
+	// SiblingHighest, SiblingLowest and SiblingMatch are the accepted
+	// SiblingBitrate values; highest is stored as "".
+	SiblingHighest = "highest"
+	SiblingLowest  = "lowest"
+	SiblingMatch   = "match"
+	// DefaultRemoteControlPort is where the optional HTTP control API listens.
+	DefaultRemoteControlPort = 8765
+	// ConfigVersion is the schema version Save writes; see migrations.
+	ConfigVersion = 2
 
 	// EQNameAuto is a deprecated alias for the built-in "Flat" EQ preset.
 	EQNameAuto = "Auto"
 	// EQNameOff disables EQ for a preset; it matches the option shown in UI.
 	EQNameOff = "Off"
+
+	// PresetVolumeGlobal marks a preset that follows Config.Volume instead of
+	// remembering its own level.
+	PresetVolumeGlobal = -1
+
+	// MaxStableWindowMs bounds StableWindowMs so titles still show up.
+	MaxStableWindowMs = 30000
 )
 
+// MetadataFields are dot-separated paths into a station's now-playing JSON,
+// such as "data.current.title"; numeric parts index arrays and "" falls back
+// to the usual names.
+type MetadataFields struct {
+	Title   string `json:"title,omitempty"`
+	Artist  string `json:"artist,omitempty"`
+	Station string `json:"station,omitempty"`
+	Artwork string `json:"artwork,omitempty"`
+}
+
 // EQPresetData represents a user-created EQ preset stored in the config file.
 type EQPresetData struct {
 	Name   string    `json:"name"`
 	Preamp float32   `json:"preamp"`
 	Bands  []float32 `json:"bands"`
+	// Freqs are the band centres in Hz the gains were saved for, so the curve
+	// can be remapped onto a libVLC build with different bands. Older presets
+	// leave it empty.
+	Freqs []float32 `json:"freqs,omitempty"`
+	// Tone marks a preset made with the tone controls and keeps their
+	// settings; Bands still hold the curve they produce, so graphic mode
+	// plays it unchanged.
+	Tone *EQToneData `json:"tone,omitempty"`
+}
+
+// EQToneData is a bass/mid/treble tone control setting in dB.
+type EQToneData struct {
+	Bass   float32 `json:"bass"`
+	Mid    float32 `json:"mid"`
+	Treble float32 `json:"treble"`
 }
 
 // Preset describes a single radio station entry available in the UI grid.
 type Preset struct {
-	Name         string `json:"name"`
-	URL          string `json:"url"`
-	EQ           string `json:"eq,omitempty"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	EQ   string `json:"eq,omitempty"`
+	// ManualEQ is the slider curve the station was edited to in the EQ
+	// drawer, applied while EQ is "Manual".
+	ManualEQ *EQPresetData `json:"manualEq,omitempty"`
+	// MetadataType and MetadataURL remember where the station's metadata was
+	// found; ICY or HLS with no URL means the stream itself.
 	MetadataType string `json:"metadataType,omitempty"`
 	MetadataURL  string `json:"metadataUrl,omitempty"`
+	// MetadataManual marks MetadataType/MetadataURL as chosen in Settings;
+	// auto-discovery then leaves them alone.
+	MetadataManual bool `json:"metadataManual,omitempty"`
+	// MetadataFor is the stream URL a discovered MetadataType/MetadataURL
+	// was found for, such as the "/rock-flac" whose titles come from the
+	// sibling "/rock-320"; once URL differs the source is stale.
+	MetadataFor string `json:"metadataFor,omitempty"`
+	// MetadataFields locate the now-playing fields in the JSON of an "API",
+	// "WS" or "SSE" metadata source (nil = the common layouts).
+	MetadataFields *MetadataFields `json:"metadataFields,omitempty"`
+	// Volume is the level remembered for this station (0–100), or
+	// PresetVolumeGlobal to use the global volume.
+	Volume int `json:"volume"`
+	// UserAgent and Referer override Config.UserAgent / Config.Referer for
+	// this station ("" = use the global value).
+	UserAgent string `json:"userAgent,omitempty"`
+	Referer   string `json:"referer,omitempty"`
+	// TitleFilters clean up this station's now-playing titles: a plain text
+	// or /regexp/ pattern hides matching titles (ad breaks), and the same
+	// prefixed with "strip:" removes the match (a repeated station name).
+	TitleFilters []string `json:"titleFilters,omitempty"`
+	// Favorite stars the station; Shift+←/→ cycles through starred
+	// stations only.
+	Favorite bool `json:"favorite,omitempty"`
+	// StableWindowMs overrides Config.StableWindowMs for this station
+	// (nil = use the global value).
+	StableWindowMs *int `json:"stableWindowMs,omitempty"`
+	// RequireTLS refuses plain-HTTP metadata and playlist requests for this
+	// station, including redirects off https. CertPins, when set, accept only
+	// servers whose certificate public key has one of these hashes, written
+	// "sha256//<base64>" as curl's --pinnedpubkey takes them.
+	RequireTLS bool     `json:"requireTLS,omitempty"`
+	CertPins   []string `json:"certPins,omitempty"`
+}
+
+// UnmarshalJSON defaults Volume to PresetVolumeGlobal so configs written
+// before per-preset volume keep following the global level.
+func (p *Preset) UnmarshalJSON(b []byte) error {
+	type plain Preset
+	v := plain{Volume: PresetVolumeGlobal}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*p = Preset(v)
+	return nil
+}
+
+// RememberMetadata stores a discovered metadata source as belonging to the
+// preset's current stream URL.
+func (p *Preset) RememberMetadata(typ, url string) {
+	p.MetadataType, p.MetadataURL = typ, url
+	p.MetadataFor = strings.TrimSpace(p.URL)
+}
+
+// ForgetMetadata drops the stored metadata source, discovered or manual.
+func (p *Preset) ForgetMetadata() {
+	p.MetadataType, p.MetadataURL, p.MetadataFor, p.MetadataManual = "", "", "", false
+}
+
+// MetadataHint returns the stored metadata source to try first, or "" for
+// both when there is none or it was discovered for another stream URL.
+func (p Preset) MetadataHint() (typ, url string) {
+	if p.metadataStale() {
+		return "", ""
+	}
+	return strings.TrimSpace(p.MetadataType), strings.TrimSpace(p.MetadataURL)
+}
+
+// metadataStale reports whether a discovered source belongs to a stream URL
+// the preset no longer plays. Manual sources are never stale.
+func (p Preset) metadataStale() bool {
+	return !p.MetadataManual && p.MetadataFor != "" && p.MetadataFor != strings.TrimSpace(p.URL)
+}
+
+// isEmpty reports whether the slot holds no station.
+func (p Preset) isEmpty() bool {
+	return strings.TrimSpace(p.URL) == "" && strings.TrimSpace(p.Name) == ""
 }
 
 // Config aggregates every user-facing preference persisted between sessions.
 type Config struct {
-	CurrentURL      string         `json:"currentUrl"`
-	Volume          int            `json:"volume"`
-	Muted           bool           `json:"muted"`
-	LastPreset      int            `json:"lastPreset"`
-	Presets         [10]Preset     `json:"presets"`
-	WindowW         int            `json:"windowW"`
-	WindowH         int            `json:"windowH"`
-	WindowX         int            `json:"windowX,omitempty"`
-	WindowY         int            `json:"windowY,omitempty"`
-	WindowPosValid  bool           `json:"windowPosValid,omitempty"`
+	// Version is the schema version the file was written with; files from
+	// before versioning read as 0 and are upgraded by Load.
+	Version        int      `json:"version"`
+	CurrentURL     string   `json:"currentUrl"`
+	Volume         int      `json:"volume"`
+	Muted          bool     `json:"muted"`
+	LastPreset     int      `json:"lastPreset"`
+	Presets        []Preset `json:"presets"`
+	WindowW        int      `json:"windowW"`
+	WindowH        int      `json:"windowH"`
+	WindowX        int      `json:"windowX,omitempty"`
+	WindowY        int      `json:"windowY,omitempty"`
+	WindowPosValid bool     `json:"windowPosValid,omitempty"`
+	// SizeMode scales the strip height and its controls: "compact",
+	// "normal" (or "") or "large" for high-DPI screens.
+	SizeMode string `json:"sizeMode,omitempty"`
+	// Theme is "dark" (or ""), "light" or "system" to follow the OS.
+	Theme           string         `json:"theme,omitempty"`
 	ApiEndpoint     string         `json:"apiEndpoint"`
 	CustomEQPresets []EQPresetData `json:"customEqPresets,omitempty"`
+	// EQMode is how the EQ drawer edits the curve: "graphic" (or "") with a
+	// slider per band, or "tone" with bass, mid and treble controls.
+	EQMode string `json:"eqMode,omitempty"`
+	// LastManualEQ is the unsaved "Manual" slider curve captured when the EQ
+	// drawer closes, restored the next time it opens on a stream that is not
+	// a preset (presets keep their own ManualEQ). Nil when the drawer was left
+	// on a named preset.
+	LastManualEQ *EQPresetData `json:"lastManualEq,omitempty"`
+	// EntryShortcuts keeps Space/digit shortcuts active while a text entry has
+	// focus; by default those keys are left to the entry.
+	EntryShortcuts bool `json:"entryShortcuts,omitempty"`
+	// MetadataMaxConnections caps simultaneous metadata connections
+	// (0 = metadata package default).
+	MetadataMaxConnections int `json:"metadataMaxConnections,omitempty"`
+	// MetadataPollIntervalMs, MetadataRequestTimeoutMs and
+	// MetadataDialTimeoutMs tune metadata polling and network timeouts
+	// (0 = metadata package defaults).
+	MetadataPollIntervalMs   int `json:"metadataPollIntervalMs,omitempty"`
+	MetadataRequestTimeoutMs int `json:"metadataRequestTimeoutMs,omitempty"`
+	MetadataDialTimeoutMs    int `json:"metadataDialTimeoutMs,omitempty"`
+	// MetadataDiscoveryMs bounds the search for a station's metadata source
+	// before the ticker says it has no track info (0 = metadata package
+	// default of 15s, negative = keep looking).
+	MetadataDiscoveryMs int `json:"metadataDiscoveryMs,omitempty"`
+	// MetadataProxy routes metadata and playlist requests: "system" (or "")
+	// uses the HTTP_PROXY/HTTPS_PROXY environment, "none" connects directly,
+	// anything else is a proxy URL like "http://proxy.corp:3128".
+	MetadataProxy string `json:"metadataProxy,omitempty"`
+	// MetadataMinTLS is the lowest TLS version metadata connections accept:
+	// "1.2" or "1.3" ("" = 1.0, which some old stations need).
+	MetadataMinTLS string `json:"metadataMinTLS,omitempty"`
+	// IPFamily holds stream and metadata connections to "ipv4" or "ipv6" for
+	// dual-stack networks where a station misbehaves over the other; ""
+	// (auto) lets the system choose.
+	IPFamily string `json:"ipFamily,omitempty"`
+	// SiblingBitrate orders the sibling mounts probed for metadata a stream
+	// lacks: "lowest" first, for metered data, or "match" the stream's own
+	// bitrate and never above it ("" = highest first).
+	SiblingBitrate string `json:"siblingBitrate,omitempty"`
+	// UserAgent and Referer are sent to stream and metadata servers
+	// ("" = built-in defaults); presets may override them.
+	UserAgent string `json:"userAgent,omitempty"`
+	Referer   string `json:"referer,omitempty"`
+	// PresetCount is the number of preset slots shown in Settings
+	// (0 = keep as many as are stored, at least MinPresets).
+	PresetCount int `json:"presetCount,omitempty"`
+	// FadeInMs is the fade-in applied when playback starts (0 = player
+	// default of 600ms, negative = off). FadeOutMs fades out before stopping
+	// (0 = off).
+	FadeInMs  int `json:"fadeInMs,omitempty"`
+	FadeOutMs int `json:"fadeOutMs,omitempty"`
+	// CrossfadeMs overlaps the old and new station when switching presets
+	// during playback (0 = instant switch).
+	CrossfadeMs int `json:"crossfadeMs,omitempty"`
+	// SilenceTimeoutMs reports dead air once a playing stream has been
+	// silent, or delivered no audio, for this long (0 = off);
+	// SilenceReconnect then restarts it.
+	SilenceTimeoutMs int  `json:"silenceTimeoutMs,omitempty"`
+	SilenceReconnect bool `json:"silenceReconnect,omitempty"`
+	// HistorySize caps the now-playing history (0 = player default of 50).
+	// SaveHistory keeps it across restarts in history.json.
+	HistorySize int  `json:"historySize,omitempty"`
+	SaveHistory bool `json:"saveHistory,omitempty"`
+	// AudioDevice is the libVLC output device ID ("" = system default).
+	AudioDevice string `json:"audioDevice,omitempty"`
+	// Balance is the stereo balance from -1 (left) to +1 (right); 0 = centre.
+	Balance float64 `json:"balance,omitempty"`
+	// Normalize evens out loudness with libVLC's normvol filter;
+	// NormalizeLevel is its maximum gain (0 = libVLC default of 2.0, max 10).
+	Normalize      bool    `json:"normalize,omitempty"`
+	NormalizeLevel float64 `json:"normalizeLevel,omitempty"`
+	// Mono downmixes playback to a single channel.
+	Mono bool `json:"mono,omitempty"`
+	// CoverArtLookup asks MusicBrainz and the Cover Art Archive for the album
+	// and artwork of tracks whose stream sends none.
+	CoverArtLookup bool `json:"coverArtLookup,omitempty"`
+	// StableWindowMs is how long a new title must hold before the ticker
+	// shows it, up to MaxStableWindowMs (nil = player default of 6s, 0 =
+	// immediately). Presets may override it.
+	StableWindowMs *int `json:"stableWindowMs,omitempty"`
+	// StaleTitleMs clears the title once the station's metadata source has
+	// not answered for this long, showing the station name instead (0 = player default of 10
+	// minutes, negative = never).
+	StaleTitleMs int `json:"staleTitleMs,omitempty"`
+	// HideVolumeReadout removes the "NN%" label beside the volume slider.
+	HideVolumeReadout bool `json:"hideVolumeReadout,omitempty"`
+	// TickerSpeedMs is the interval between marquee steps; larger is slower
+	// (0 = 120ms). TickerDirection is "left" (default) or "right".
+	TickerSpeedMs   int    `json:"tickerSpeedMs,omitempty"`
+	TickerDirection string `json:"tickerDirection,omitempty"`
+	// RemoteControl enables the local HTTP control API on RemoteControlPort
+	// (0 = DefaultRemoteControlPort, 8765). RemoteControlHost is the bind address
+	// ("" = 127.0.0.1); set it to 0.0.0.0 to accept other devices.
+	RemoteControl     bool   `json:"remoteControl,omitempty"`
+	RemoteControlHost string `json:"remoteControlHost,omitempty"`
+	RemoteControlPort int    `json:"remoteControlPort,omitempty"`
+	// WatchConfigFile reloads stations, EQ presets and preferences when
+	// config.json is edited by another program while MiniRadio runs.
+	WatchConfigFile bool `json:"watchConfigFile,omitempty"`
+	// ShowOnboarding is set on the config created at first launch and
+	// cleared once the hint pointing at Settings is dismissed.
+	ShowOnboarding bool `json:"showOnboarding,omitempty"`
+}
+
+// SizeScale is the factor SizeMode applies to DefaultHeight and the strip's
+// controls.
+func SizeScale(mode string) float32 {
+	switch mode {
+	case SizeCompact:
+		return 0.875
+	case SizeLarge:
+		return 1.5
+	}
+	return 1
 }
 
-// ConfigDir resolves the writable directory that should contain the config file.
+// ConfigDir resolves the default writable directory for the config file.
 func ConfigDir() (string, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil {
@@ -79,8 +354,25 @@ func ConfigDir() (string, error) {
 	return filepath.Join(dir, AppConfigSubdir), nil
 }
 
-// ConfigPath is a helper that returns the full path to config.json.
+// pathOverride is the config file chosen with SetPath; "" when unset.
+var pathOverride string
+
+// SetPath makes Load and Save use the config file at path, and keep
+// history.json beside it, instead of the OS default location; "" goes back to
+// EnvConfigPath or the default. The -config flag calls it before Load.
+func SetPath(path string) {
+	pathOverride = strings.TrimSpace(path)
+}
+
+// ConfigPath returns the full path to config.json: the SetPath file, else
+// the EnvConfigPath file, else config.json in ConfigDir. Relative paths are
+// resolved against the working directory.
 func ConfigPath() (string, error) {
+	for _, p := range []string{pathOverride, strings.TrimSpace(os.Getenv(EnvConfigPath))} {
+		if p != "" {
+			return filepath.Abs(p)
+		}
+	}
 	d, err := ConfigDir()
 	if err != nil {
 		return "", err
@@ -88,6 +380,15 @@ func ConfigPath() (string, error) {
 	return filepath.Join(d, AppConfigName), nil
 }
 
+// HistoryPath returns the location of history.json, next to config.json.
+func HistoryPath() (string, error) {
+	p, err := ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(p), AppHistoryName), nil
+}
+
 // Load reads the config from disk, applying defaults or gently migrating legacy
 // values when necessary.
 func Load() (*Config, error) {
@@ -98,18 +399,20 @@ func Load() (*Config, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			cfg := newDefaultConfig()
+			cfg := newFirstRunConfig()
 			// Try saving an initial config, but still return defaults even if it fails.
 			_ = cfg.Save()
 			return cfg, nil
 		}
 		return nil, err
 	}
+	remember(path, b)
 
 	cfg := &Config{}
 	if err := json.Unmarshal(b, cfg); err != nil {
 		return nil, fmt.Errorf("config parse error: %w", err)
 	}
+	cfg.migrate()
 	cfg.applyRuntimeDefaults()
 	return cfg, nil
 }
@@ -120,23 +423,123 @@ func (c *Config) Save() error {
 	if err != nil {
 		return err
 	}
+	return c.writeJSON(path)
+}
+
+// ExportTo writes the configuration to path in the same JSON shape as
+// config.json so it can be shared or moved to another machine.
+func (c *Config) ExportTo(path string) error {
+	return c.writeJSON(path)
+}
+
+// ImportFrom replaces c with the configuration stored at path. The file is
+// normalized through applyRuntimeDefaults before anything is replaced. The
+// local window size and placement are kept, as are the audio device and the
+// network and remote-control settings: a shared file must not open the control
+// API or reroute traffic on this machine. c is left untouched on error.
+func (c *Config) ImportFrom(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	in := &Config{}
+	if err := json.Unmarshal(b, in); err != nil {
+		return fmt.Errorf("config import error: %w", err)
+	}
+	in.migrate()
+	in.applyRuntimeDefaults()
+	in.WindowX, in.WindowY = c.WindowX, c.WindowY
+	in.WindowW, in.WindowH = c.WindowW, c.WindowH
+	in.WindowPosValid = c.WindowPosValid
+	in.SizeMode = c.SizeMode
+	// device IDs only mean something on the machine that saved them
+	in.AudioDevice = c.AudioDevice
+	in.RemoteControl, in.RemoteControlHost, in.RemoteControlPort = c.RemoteControl, c.RemoteControlHost, c.RemoteControlPort
+	in.WatchConfigFile = c.WatchConfigFile
+	in.MetadataProxy, in.MetadataMinTLS, in.IPFamily = c.MetadataProxy, c.MetadataMinTLS, c.IPFamily
+	in.UserAgent, in.Referer = c.UserAgent, c.Referer
+	*c = *in
+	return nil
+}
+
+// ResetDefaults replaces c with a fresh default config, dropping every preset,
+// custom EQ preset and preference. With keepWindow the window size and
+// placement survive, so the window neither jumps nor resizes.
+func (c *Config) ResetDefaults(keepWindow bool) {
+	d := newDefaultConfig()
+	if keepWindow {
+		d.WindowX, d.WindowY = c.WindowX, c.WindowY
+		d.WindowW, d.WindowH = c.WindowW, c.WindowH
+		d.WindowPosValid = c.WindowPosValid
+	}
+	*c = *d
+}
+
+// ExportPreset writes the custom EQ preset called name to path as a small
+// JSON file that ImportPreset reads back.
+func (c *Config) ExportPreset(name, path string) error {
+	name = strings.TrimSpace(name)
+	for _, p := range c.CustomEQPresets {
+		if strings.TrimSpace(p.Name) != name {
+			continue
+		}
+		b, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, b, 0o644)
+	}
+	return fmt.Errorf("no custom EQ preset named %q", name)
+}
+
+// ImportPreset reads a single EQ preset written by ExportPreset. The band
+// count is returned as stored; callers fit it to the running equalizer.
+func ImportPreset(path string) (EQPresetData, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return EQPresetData{}, err
+	}
+	var p EQPresetData
+	if err := json.Unmarshal(b, &p); err != nil {
+		return EQPresetData{}, fmt.Errorf("EQ preset import error: %w", err)
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" || len(p.Bands) == 0 {
+		return EQPresetData{}, fmt.Errorf("EQ preset import error: %s has no name or bands", filepath.Base(path))
+	}
+	return p, nil
+}
+
+func (c *Config) writeJSON(path string) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
+	c.Version = ConfigVersion
 	b, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, b, 0o644)
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return err
+	}
+	remember(path, b)
+	return nil
 }
 
 // AppID returns the stable identifier used by the GUI framework.
 func (c *Config) AppID() string { return AppID }
 
+// NewDefault returns a config populated with safe defaults without touching
+// the file on disk.
+func NewDefault() *Config {
+	return newDefaultConfig()
+}
+
 // newDefaultConfig builds an in-memory config populated with safe defaults.
 func newDefaultConfig() *Config {
 	cfg := &Config{
+		Version:         ConfigVersion,
 		CurrentURL:      DefaultTestURL,
 		Volume:          DefaultVolume,
 		Muted:           false,
@@ -153,6 +556,71 @@ func newDefaultConfig() *Config {
 	return cfg
 }
 
+// starterPresets fill the first slots of a brand-new config so the first
+// launch has something to play: listener-supported public streams whose
+// operators welcome direct playback in third-party players.
+var starterPresets = []Preset{
+	{Name: "SomaFM Groove Salad", URL: "https://ice1.somafm.com/groovesalad-128-mp3"},
+	{Name: "SomaFM Drone Zone", URL: "https://ice1.somafm.com/dronezone-128-mp3"},
+	{Name: "KEXP 90.3 FM", URL: "https://kexp-mp3-128.streamguys1.com/kexp128.mp3"},
+}
+
+// newFirstRunConfig is the config written when none exists yet: the defaults
+// plus starterPresets and the onboarding hint. Only Load calls it, and only
+// when there is no config file, so existing presets are never replaced.
+func newFirstRunConfig() *Config {
+	cfg := newDefaultConfig()
+	for i, p := range starterPresets {
+		if i >= len(cfg.Presets) {
+			break
+		}
+		p.EQ = EQNameOff
+		p.Volume = PresetVolumeGlobal
+		cfg.Presets[i] = p
+	}
+	cfg.ShowOnboarding = true
+	return cfg
+}
+
+// normalizePresets sizes Presets to PresetCount (or to what is stored), never
+// below MinPresets. Legacy configs with exactly ten entries load unchanged, and
+// trimming only drops trailing empty slots so no station is ever lost.
+func (c *Config) normalizePresets() {
+	if c.PresetCount < 0 {
+		c.PresetCount = 0
+	}
+	if c.PresetCount > MaxPresets {
+		c.PresetCount = MaxPresets
+	}
+	want := c.PresetCount
+	if want == 0 {
+		want = len(c.Presets)
+	}
+	if want < MinPresets {
+		want = MinPresets
+	}
+	for len(c.Presets) > want && c.Presets[len(c.Presets)-1].isEmpty() {
+		c.Presets = c.Presets[:len(c.Presets)-1]
+	}
+	for len(c.Presets) < want {
+		c.Presets = append(c.Presets, Preset{Volume: PresetVolumeGlobal})
+	}
+	if c.LastPreset >= len(c.Presets) {
+		c.LastPreset = -1
+	}
+}
+
+// clampStableWindow keeps a StableWindowMs value within 0…MaxStableWindowMs.
+func clampStableWindow(ms *int) {
+	switch {
+	case ms == nil:
+	case *ms < 0:
+		*ms = 0
+	case *ms > MaxStableWindowMs:
+		*ms = MaxStableWindowMs
+	}
+}
+
 // applyRuntimeDefaults normalizes config values after a load or when defaults
 // are constructed, ensuring the UI always receives sane inputs.
 func (c *Config) applyRuntimeDefaults() {
@@ -171,19 +639,96 @@ func (c *Config) applyRuntimeDefaults() {
 	if c.Volume < 0 || c.Volume > 100 {
 		c.Volume = DefaultVolume
 	}
-	if !c.WindowPosValid && (c.WindowX != 0 || c.WindowY != 0) {
-		c.WindowPosValid = true
-	}
 	if strings.TrimSpace(c.ApiEndpoint) == "" {
 		c.ApiEndpoint = DefaultAPIEndpoint
 	}
+	c.normalizePresets()
 	for i := range c.Presets {
 		v := strings.TrimSpace(c.Presets[i].EQ)
-		if v == "" || strings.EqualFold(v, EQNameAuto) {
+		if v == "" {
 			c.Presets[i].EQ = EQNameOff
 		}
+		if vol := c.Presets[i].Volume; vol < PresetVolumeGlobal || vol > 100 {
+			c.Presets[i].Volume = PresetVolumeGlobal
+		}
+		clampStableWindow(c.Presets[i].StableWindowMs)
+		// e.g. the URL was changed in a text editor
+		if c.Presets[i].metadataStale() {
+			c.Presets[i].ForgetMetadata()
+		}
 	}
+	clampStableWindow(c.StableWindowMs)
 	if c.CustomEQPresets == nil {
 		c.CustomEQPresets = []EQPresetData{}
 	}
+	if c.MetadataMaxConnections < 0 {
+		c.MetadataMaxConnections = 0
+	}
+	if c.FadeOutMs < 0 {
+		c.FadeOutMs = 0
+	}
+	if c.CrossfadeMs < 0 {
+		c.CrossfadeMs = 0
+	}
+	if c.SilenceTimeoutMs < 0 {
+		c.SilenceTimeoutMs = 0
+	}
+	if c.HistorySize < 0 {
+		c.HistorySize = 0
+	}
+	if c.Balance < -1 {
+		c.Balance = -1
+	} else if c.Balance > 1 {
+		c.Balance = 1
+	}
+	if c.NormalizeLevel < 0 || c.NormalizeLevel > 10 {
+		c.NormalizeLevel = 0
+	}
+	if c.TickerSpeedMs < 0 {
+		c.TickerSpeedMs = 0
+	}
+	switch t := strings.ToLower(strings.TrimSpace(c.Theme)); t {
+	case ThemeLight, ThemeSystem:
+		c.Theme = t
+	default:
+		c.Theme = ""
+	}
+	switch m := strings.ToLower(strings.TrimSpace(c.SizeMode)); m {
+	case SizeCompact, SizeLarge:
+		c.SizeMode = m
+	default:
+		c.SizeMode = ""
+	}
+	// only "right" differs from the default
+	if !strings.EqualFold(strings.TrimSpace(c.TickerDirection), TickerRight) {
+		c.TickerDirection = ""
+	} else {
+		c.TickerDirection = TickerRight
+	}
+	if c.RemoteControlPort < 0 || c.RemoteControlPort > 65535 {
+		c.RemoteControlPort = 0
+	}
+	switch f := strings.ToLower(strings.TrimSpace(c.IPFamily)); f {
+	case IPFamilyV4, IPFamilyV6:
+		c.IPFamily = f
+	default:
+		c.IPFamily = ""
+	}
+	switch v := strings.ToLower(strings.TrimSpace(c.SiblingBitrate)); v {
+	case SiblingLowest, SiblingMatch:
+		c.SiblingBitrate = v
+	default:
+		c.SiblingBitrate = ""
+	}
+	switch v := strings.TrimSpace(c.MetadataMinTLS); v {
+	case TLS12, TLS13:
+		c.MetadataMinTLS = v
+	default:
+		c.MetadataMinTLS = ""
+	}
+	for _, ms := range []*int{&c.MetadataPollIntervalMs, &c.MetadataRequestTimeoutMs, &c.MetadataDialTimeoutMs} {
+		if *ms < 0 {
+			*ms = 0
+		}
+	}
 }
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 )
 
 func TestLoadDefaultConfig(t *testing.T) {
@@ -54,6 +56,28 @@ func TestLoadDefaultConfig(t *testing.T) {
 	if _, err := os.Stat(path); err != nil {
 		t.Fatalf("expected config file at %s, got error: %v", path, err)
 	}
+	for i, want := range starterPresets {
+		if got := cfg.Presets[i]; got.Name != want.Name || got.URL != want.URL || got.Volume != PresetVolumeGlobal {
+			t.Errorf("preset %d = %+v, want starter %q", i, got, want.Name)
+		}
+	}
+	if !cfg.ShowOnboarding {
+		t.Error("a fresh config should show the onboarding hint")
+	}
+
+	// an existing config, even one with no stations, gets neither
+	cfg.Presets = nil
+	cfg.ShowOnboarding = false
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	again, err := Load()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if again.Presets[0].URL != "" || again.ShowOnboarding {
+		t.Fatalf("existing config got starter content: %+v onboarding=%v", again.Presets[0], again.ShowOnboarding)
+	}
 }
 
 func overrideConfigEnv(tempDir string) func() {
@@ -86,3 +110,409 @@ func overrideConfigEnv(tempDir string) func() {
 		}
 	}
 }
+
+func TestNormalizePresets(t *testing.T) {
+	legacy := &Config{Presets: make([]Preset, 10), LastPreset: 9}
+	legacy.Presets[9] = Preset{Name: "Ten", URL: "http://ten"}
+	legacy.applyRuntimeDefaults()
+	if len(legacy.Presets) != MinPresets || legacy.Presets[9].URL != "http://ten" || legacy.LastPreset != 9 {
+		t.Fatalf("legacy ten-preset config changed: len=%d last=%d", len(legacy.Presets), legacy.LastPreset)
+	}
+
+	short := &Config{Presets: []Preset{{URL: "http://one"}}}
+	short.applyRuntimeDefaults()
+	if len(short.Presets) != MinPresets {
+		t.Fatalf("short config padded to %d, want %d", len(short.Presets), MinPresets)
+	}
+
+	grown := &Config{PresetCount: 24}
+	grown.applyRuntimeDefaults()
+	if len(grown.Presets) != 24 {
+		t.Fatalf("PresetCount=24 gave %d presets", len(grown.Presets))
+	}
+
+	shrunk := &Config{PresetCount: 12, Presets: make([]Preset, 20), LastPreset: 15}
+	shrunk.Presets[13] = Preset{URL: "http://keep"}
+	shrunk.applyRuntimeDefaults()
+	if len(shrunk.Presets) != 14 || shrunk.Presets[13].URL != "http://keep" {
+		t.Fatalf("trim should stop at the last configured station, got len=%d", len(shrunk.Presets))
+	}
+	if shrunk.LastPreset != -1 {
+		t.Fatalf("out-of-range LastPreset should reset, got %d", shrunk.LastPreset)
+	}
+}
+
+func TestExportImportKeepsWindowPlacement(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shared.json")
+
+	src := newDefaultConfig()
+	src.Presets[0] = Preset{Name: "Rock", URL: "http://rock", EQ: "Rock"}
+	src.CustomEQPresets = []EQPresetData{{Name: "Mine", Bands: make([]float32, 10)}}
+	src.WindowX, src.WindowY, src.WindowW, src.WindowPosValid = 5000, 5000, 1200, true
+	src.AudioDevice = "{other-machine}"
+	src.RemoteControl, src.RemoteControlHost = true, "0.0.0.0"
+	src.MetadataProxy, src.UserAgent, src.WatchConfigFile = "http://evil:3128", "Other/1.0", true
+	if err := src.ExportTo(path); err != nil {
+		t.Fatalf("ExportTo: %v", err)
+	}
+
+	local := newDefaultConfig()
+	local.WindowX, local.WindowY, local.WindowW, local.WindowPosValid = 10, 20, 900, true
+	local.AudioDevice = "{speakers}"
+	if err := local.ImportFrom(path); err != nil {
+		t.Fatalf("ImportFrom: %v", err)
+	}
+	if local.Presets[0].URL != "http://rock" || len(local.CustomEQPresets) != 1 {
+		t.Fatalf("presets not imported: %+v", local.Presets[0])
+	}
+	if local.WindowX != 10 || local.WindowY != 20 || local.WindowW != 900 || !local.WindowPosValid {
+		t.Fatalf("window placement overwritten: x=%d y=%d w=%d", local.WindowX, local.WindowY, local.WindowW)
+	}
+	if local.AudioDevice != "{speakers}" {
+		t.Fatalf("audio device overwritten: %q", local.AudioDevice)
+	}
+	if local.RemoteControl || local.RemoteControlHost != "" || local.MetadataProxy != "" || local.UserAgent != "" || local.WatchConfigFile {
+		t.Fatalf("machine-local settings imported: remote=%v host=%q proxy=%q ua=%q watch=%v",
+			local.RemoteControl, local.RemoteControlHost, local.MetadataProxy, local.UserAgent, local.WatchConfigFile)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := local.ImportFrom(path); err == nil {
+		t.Fatal("expected an error for malformed input")
+	}
+	if local.Presets[0].URL != "http://rock" {
+		t.Fatal("failed import must leave the config untouched")
+	}
+}
+
+func TestPresetVolumeDefaultsToGlobal(t *testing.T) {
+	legacy := `{"volume":55,"presets":[{"name":"A","url":"http://a"},{"name":"B","url":"http://b","volume":30},{"url":"http://c","volume":250}]}`
+	cfg := &Config{}
+	if err := json.Unmarshal([]byte(legacy), cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.applyRuntimeDefaults()
+	if got := cfg.Presets[0].Volume; got != PresetVolumeGlobal {
+		t.Errorf("missing volume = %d, want %d", got, PresetVolumeGlobal)
+	}
+	if got := cfg.Presets[1].Volume; got != 30 {
+		t.Errorf("stored volume = %d, want 30", got)
+	}
+	if got := cfg.Presets[2].Volume; got != PresetVolumeGlobal {
+		t.Errorf("out-of-range volume = %d, want %d", got, PresetVolumeGlobal)
+	}
+	for i := 3; i < len(cfg.Presets); i++ {
+		if cfg.Presets[i].Volume != PresetVolumeGlobal {
+			t.Fatalf("padded preset %d volume = %d", i, cfg.Presets[i].Volume)
+		}
+	}
+}
+
+func TestStableWindowClamped(t *testing.T) {
+	in := `{"stableWindowMs":90000,"presets":[{"url":"http://a","stableWindowMs":0},{"url":"http://b","stableWindowMs":-5},{"url":"http://c"}]}`
+	cfg := &Config{}
+	if err := json.Unmarshal([]byte(in), cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.applyRuntimeDefaults()
+	if cfg.StableWindowMs == nil || *cfg.StableWindowMs != MaxStableWindowMs {
+		t.Errorf("global window = %v, want %d", cfg.StableWindowMs, MaxStableWindowMs)
+	}
+	for i, want := range []int{0, 0} {
+		if got := cfg.Presets[i].StableWindowMs; got == nil || *got != want {
+			t.Errorf("preset %d window = %v, want %d", i, got, want)
+		}
+	}
+	if cfg.Presets[2].StableWindowMs != nil {
+		t.Errorf("missing preset window = %d, want nil", *cfg.Presets[2].StableWindowMs)
+	}
+}
+
+func TestMetadataTLSSettings(t *testing.T) {
+	in := `{"metadataMinTLS":" 1.3 ","presets":[{"url":"https://a","requireTLS":true,"certPins":["sha256//abc="]}]}`
+	cfg := &Config{}
+	if err := json.Unmarshal([]byte(in), cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.applyRuntimeDefaults()
+	if cfg.MetadataMinTLS != TLS13 {
+		t.Errorf("min TLS = %q, want %q", cfg.MetadataMinTLS, TLS13)
+	}
+	if p := cfg.Presets[0]; !p.RequireTLS || len(p.CertPins) != 1 {
+		t.Errorf("preset TLS policy = %v %v", p.RequireTLS, p.CertPins)
+	}
+	for _, bad := range []string{"1.0", "1.1", "tls12", "2"} {
+		cfg.MetadataMinTLS = bad
+		cfg.applyRuntimeDefaults()
+		if cfg.MetadataMinTLS != "" {
+			t.Errorf("min TLS %q kept as %q", bad, cfg.MetadataMinTLS)
+		}
+	}
+}
+
+func TestSiblingBitrateNormalized(t *testing.T) {
+	for in, want := range map[string]string{"": "", "highest": "", " Lowest ": SiblingLowest, "match": SiblingMatch, "low": ""} {
+		cfg := &Config{SiblingBitrate: in}
+		cfg.applyRuntimeDefaults()
+		if cfg.SiblingBitrate != want {
+			t.Errorf("SiblingBitrate %q = %q, want %q", in, cfg.SiblingBitrate, want)
+		}
+	}
+}
+
+func TestIPFamilyNormalized(t *testing.T) {
+	for in, want := range map[string]string{"": "", "auto": "", " IPv4 ": IPFamilyV4, "ipv6": IPFamilyV6, "v6": ""} {
+		cfg := &Config{IPFamily: in}
+		cfg.applyRuntimeDefaults()
+		if cfg.IPFamily != want {
+			t.Errorf("IPFamily %q = %q, want %q", in, cfg.IPFamily, want)
+		}
+	}
+}
+
+func TestBalanceClamped(t *testing.T) {
+	for in, want := range map[float64]float64{0: 0, -0.4: -0.4, 1: 1, -3: -1, 2: 1} {
+		cfg := &Config{Balance: in}
+		cfg.applyRuntimeDefaults()
+		if cfg.Balance != want {
+			t.Errorf("Balance %v = %v, want %v", in, cfg.Balance, want)
+		}
+	}
+}
+
+func TestExportImportPreset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mine.json")
+
+	cfg := newDefaultConfig()
+	cfg.CustomEQPresets = []EQPresetData{{Name: "Mine", Preamp: -2, Bands: []float32{1, 2, 3}}}
+	if err := cfg.ExportPreset("Mine", path); err != nil {
+		t.Fatalf("ExportPreset: %v", err)
+	}
+	got, err := ImportPreset(path)
+	if err != nil {
+		t.Fatalf("ImportPreset: %v", err)
+	}
+	if got.Name != "Mine" || got.Preamp != -2 || len(got.Bands) != 3 || got.Bands[2] != 3 {
+		t.Fatalf("round trip = %+v", got)
+	}
+	if err := cfg.ExportPreset("Missing", path); err == nil {
+		t.Fatal("expected an error for an unknown preset")
+	}
+
+	for _, body := range []string{"{not json", `{"name":"","bands":[1]}`, `{"name":"x","bands":[]}`} {
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ImportPreset(path); err == nil {
+			t.Fatalf("expected an error for %s", body)
+		}
+	}
+}
+
+func TestLoadMigratesVersionlessConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	restore := overrideConfigEnv(tempDir)
+	defer restore()
+
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("ConfigPath error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	v0 := `{
+  "currentUrl": "http://jazz",
+  "volume": 40,
+  "lastPreset": 1,
+  "windowX": 120,
+  "windowY": 80,
+  "presets": [
+    {"name": "Rock", "url": "http://rock", "eq": "Auto"},
+    {"name": "Jazz", "url": "http://jazz", "eq": "Mine", "volume": 55}
+  ],
+  "customEqPresets": [{"name": "Mine", "preamp": -2, "bands": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10]}]
+}`
+	if err := os.WriteFile(path, []byte(v0), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Version != ConfigVersion {
+		t.Fatalf("Version = %d, want %d", cfg.Version, ConfigVersion)
+	}
+	if p := cfg.Presets[0]; p.Name != "Rock" || p.URL != "http://rock" || p.EQ != EQNameOff || p.Volume != PresetVolumeGlobal {
+		t.Fatalf("preset 0 = %+v", p)
+	}
+	if p := cfg.Presets[1]; p.Name != "Jazz" || p.EQ != "Mine" || p.Volume != 55 || cfg.LastPreset != 1 {
+		t.Fatalf("preset 1 = %+v, lastPreset %d", p, cfg.LastPreset)
+	}
+	if len(cfg.CustomEQPresets) != 1 || cfg.CustomEQPresets[0].Preamp != -2 || len(cfg.CustomEQPresets[0].Bands) != 10 {
+		t.Fatalf("custom EQ presets = %+v", cfg.CustomEQPresets)
+	}
+	if !cfg.WindowPosValid || cfg.WindowX != 120 {
+		t.Fatalf("window position: valid=%v x=%d", cfg.WindowPosValid, cfg.WindowX)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(b, &saved); err != nil || saved.Version != ConfigVersion {
+		t.Fatalf("saved version = %d (%v), want %d", saved.Version, err, ConfigVersion)
+	}
+}
+
+func TestMetadataSourceTiedToURL(t *testing.T) {
+	p := Preset{URL: " http://host/rock-flac "}
+	p.RememberMetadata("ICY", "http://host/rock-320")
+	if typ, u := p.MetadataHint(); typ != "ICY" || u != "http://host/rock-320" || p.MetadataFor != "http://host/rock-flac" {
+		t.Fatalf("hint %q %q for %q", typ, u, p.MetadataFor)
+	}
+	p.URL = "http://host/pop-flac"
+	if typ, u := p.MetadataHint(); typ != "" || u != "" {
+		t.Fatalf("stale sibling still hinted: %q %q", typ, u)
+	}
+	cfg := &Config{Presets: []Preset{p, {URL: "http://b", MetadataType: "JSON", MetadataURL: "http://b/api", MetadataFor: "http://a", MetadataManual: true}}}
+	cfg.applyRuntimeDefaults()
+	if got := cfg.Presets[0]; got.MetadataType != "" || got.MetadataURL != "" || got.MetadataFor != "" {
+		t.Fatalf("stale source kept: %+v", got)
+	}
+	if got := cfg.Presets[1]; got.MetadataType != "JSON" {
+		t.Fatalf("manual source dropped: %+v", got)
+	}
+
+	// version 1 files did not record the URL
+	old := &Config{Version: 1, Presets: []Preset{{URL: "http://host/rock-flac", MetadataType: "ICY", MetadataURL: "http://host/rock-320"}}}
+	old.migrate()
+	if old.Version != ConfigVersion || old.Presets[0].MetadataFor != "http://host/rock-flac" {
+		t.Fatalf("migrated to %d with MetadataFor %q", old.Version, old.Presets[0].MetadataFor)
+	}
+}
+
+func TestResetDefaults(t *testing.T) {
+	messy := func() *Config {
+		c := newDefaultConfig()
+		c.Presets[0] = Preset{Name: "Rock", URL: "http://rock", EQ: "Mine"}
+		c.CustomEQPresets = []EQPresetData{{Name: "Mine", Bands: make([]float32, 10)}}
+		c.CurrentURL, c.LastPreset, c.Volume = "http://rock", 0, 15
+		c.WindowX, c.WindowY, c.WindowW, c.WindowH, c.WindowPosValid = 300, 200, 1200, 40, true
+		return c
+	}
+
+	c := messy()
+	c.ResetDefaults(true)
+	if c.Presets[0].URL != "" || len(c.CustomEQPresets) != 0 || c.CurrentURL != DefaultTestURL || c.LastPreset != -1 || c.Volume != DefaultVolume {
+		t.Fatalf("reset kept old settings: preset=%+v url=%q last=%d", c.Presets[0], c.CurrentURL, c.LastPreset)
+	}
+	if c.WindowX != 300 || c.WindowY != 200 || c.WindowW != 1200 || c.WindowH != 40 || !c.WindowPosValid {
+		t.Fatalf("window placement lost: %d,%d %dx%d valid=%v", c.WindowX, c.WindowY, c.WindowW, c.WindowH, c.WindowPosValid)
+	}
+
+	c = messy()
+	c.ResetDefaults(false)
+	if c.WindowPosValid || c.WindowX != 0 || c.WindowW != MinWindowWidth {
+		t.Fatalf("window placement kept: %d %d valid=%v", c.WindowX, c.WindowW, c.WindowPosValid)
+	}
+}
+
+func TestConfigPathOverride(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, "env", "settings.json")
+	flagPath := filepath.Join(dir, "portable", "nested", "miniradio.json")
+	t.Setenv(EnvConfigPath, envPath)
+	t.Cleanup(func() { SetPath("") })
+
+	if got, err := ConfigPath(); err != nil || got != envPath {
+		t.Fatalf("ConfigPath with %s = %q, %v", EnvConfigPath, got, err)
+	}
+	SetPath(flagPath)
+	if got, _ := ConfigPath(); got != flagPath {
+		t.Fatalf("SetPath should win over the environment: got %q", got)
+	}
+	if got, _ := HistoryPath(); got != filepath.Join(filepath.Dir(flagPath), AppHistoryName) {
+		t.Fatalf("HistoryPath = %q, want it beside the config", got)
+	}
+
+	// a missing file is created along with its parent directories
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, err := os.Stat(flagPath); err != nil {
+		t.Fatalf("config not written at the override: %v", err)
+	}
+	cfg.Volume = 33
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if again, err := Load(); err != nil || again.Volume != 33 {
+		t.Fatalf("reload from override: volume %d, %v", again.Volume, err)
+	}
+	if _, err := os.Stat(envPath); !os.IsNotExist(err) {
+		t.Fatalf("environment path touched while SetPath was in effect: %v", err)
+	}
+}
+
+func TestWatchIgnoresOwnSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	SetPath(path)
+	t.Cleanup(func() { SetPath("") })
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	w, err := Watch()
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer w.Close()
+
+	cfg.Volume = 12
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	select {
+	case c := <-w.Changes():
+		t.Fatalf("own save reported as an outside edit: volume %d", c.Volume)
+	case <-time.After(3 * watchDebounce):
+	}
+
+	// an editor writing in two steps is reported once, as the final file
+	edited := *cfg
+	edited.Presets = append([]Preset(nil), cfg.Presets...)
+	edited.Presets[4] = Preset{Name: "Hand-added", URL: "http://added"}
+	b, _ := json.MarshalIndent(&edited, "", "  ")
+	if err := os.WriteFile(path, b[:len(b)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-w.Changes():
+		if c.Presets[4].URL != "http://added" || c.Volume != 12 {
+			t.Fatalf("reloaded preset 5 = %+v, volume %d", c.Presets[4], c.Volume)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("outside edit not reported")
+	}
+	select {
+	case c := <-w.Changes():
+		t.Fatalf("edit reported twice: %+v", c.Presets[4])
+	case <-time.After(3 * watchDebounce):
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// migrations upgrade a config one schema version at a time: migrations[i]
+// takes a version i file to version i+1 and describes what it changed. Add an
+// entry and bump ConfigVersion together whenever the file format changes.
+var migrations = []func(c *Config) []string{
+	migrateV0,
+	migrateV1,
+}
+
+// migrate brings a config read from disk up to ConfigVersion step by step,
+// logging each upgrade. A file from a newer build is left as read.
+func (c *Config) migrate() {
+	if c.Version > ConfigVersion {
+		log.Printf("config: version %d is newer than this build understands (%d); unknown settings are dropped on save", c.Version, ConfigVersion)
+		return
+	}
+	if c.Version < 0 {
+		c.Version = 0
+	}
+	for c.Version < ConfigVersion {
+		notes := migrations[c.Version](c)
+		msg := fmt.Sprintf("config: migrated version %d to %d", c.Version, c.Version+1)
+		if len(notes) > 0 {
+			msg += ": " + strings.Join(notes, "; ")
+		}
+		log.Print(msg)
+		c.Version++
+	}
+}
+
+// migrateV0 upgrades files written before versioning: presets stored "Auto"
+// for no EQ, and a saved window position did not come with WindowPosValid.
+func migrateV0(c *Config) []string {
+	var notes []string
+	auto := 0
+	for i := range c.Presets {
+		if strings.EqualFold(strings.TrimSpace(c.Presets[i].EQ), EQNameAuto) {
+			c.Presets[i].EQ = EQNameOff
+			auto++
+		}
+	}
+	if auto > 0 {
+		notes = append(notes, fmt.Sprintf("%d preset EQ %q → %q", auto, EQNameAuto, EQNameOff))
+	}
+	if !c.WindowPosValid && (c.WindowX != 0 || c.WindowY != 0) {
+		c.WindowPosValid = true
+		notes = append(notes, "window position marked valid")
+	}
+	return notes
+}
+
+// migrateV1 ties discovered metadata sources to the stream URL they were
+// found for, which version 1 did not record. Settings cleared them whenever a
+// URL was edited, so the current URL is the right one.
+func migrateV1(c *Config) []string {
+	n := 0
+	for i := range c.Presets {
+		p := &c.Presets[i]
+		if !p.MetadataManual && strings.TrimSpace(p.MetadataType) != "" && p.MetadataFor == "" {
+			p.MetadataFor = strings.TrimSpace(p.URL)
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%d metadata source(s) tied to their stream URL", n)}
+}
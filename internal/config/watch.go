@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the config file must stay quiet after a change
+// before it is read; editors often write a file in several steps.
+const watchDebounce = 300 * time.Millisecond
+
+// known remembers the bytes last read from or written to each config file,
+// so a Watcher can tell the app's own saves from edits made elsewhere.
+var known = struct {
+	sync.Mutex
+	m map[string][]byte
+}{m: map[string][]byte{}}
+
+// remember records b as the current contents of the config file at path.
+func remember(path string, b []byte) {
+	known.Lock()
+	known.m[path] = append([]byte(nil), b...)
+	known.Unlock()
+}
+
+// isKnown reports whether b is what was last read from or written to path.
+func isKnown(path string, b []byte) bool {
+	known.Lock()
+	defer known.Unlock()
+	prev, ok := known.m[path]
+	return ok && bytes.Equal(prev, b)
+}
+
+// Watcher reports edits made to the config file by other programs, such as a
+// text editor used to add stations in bulk.
+type Watcher struct {
+	fw      *fsnotify.Watcher
+	path    string
+	changes chan *Config
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Watch starts watching the file ConfigPath names. Each time it changes on
+// disk to something other than what Load read or Save wrote, the new contents
+// are parsed and normalized as Load would and sent on Changes. A file that
+// does not parse, e.g. half-way through a save, is logged and skipped.
+func Watch() (*Watcher, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// the directory is watched, as editors often replace the file instead of
+	// writing it in place
+	if err := fw.Add(filepath.Dir(path)); err != nil {
+		fw.Close()
+		return nil, err
+	}
+	w := &Watcher{fw: fw, path: path, changes: make(chan *Config, 1), done: make(chan struct{})}
+	go w.run()
+	return w, nil
+}
+
+// Changes delivers configs edited outside the app. Only the latest pending
+// one is kept when the receiver falls behind. It is closed by Close.
+func (w *Watcher) Changes() <-chan *Config { return w.changes }
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.fw.Close()
+	})
+	return err
+}
+
+func (w *Watcher) run() {
+	defer close(w.changes)
+	var fire <-chan time.Time
+	var timer *time.Timer
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.fw.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(ev.Name) != w.path || !ev.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+			} else {
+				timer.Reset(watchDebounce)
+			}
+			fire = timer.C
+		case err, ok := <-w.fw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("config watch: %v", err)
+		case <-fire:
+			fire = nil
+			if c := w.read(); c != nil {
+				w.send(c)
+			}
+		}
+	}
+}
+
+// read loads the file if it holds an outside edit, or returns nil.
+func (w *Watcher) read() *Config {
+	b, err := os.ReadFile(w.path)
+	if err != nil || isKnown(w.path, b) {
+		return nil
+	}
+	c := &Config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		log.Printf("config watch: ignoring %s until it parses: %v", filepath.Base(w.path), err)
+		return nil
+	}
+	remember(w.path, b)
+	c.migrate()
+	c.applyRuntimeDefaults()
+	return c
+}
+
+// send hands c to the receiver, replacing a change it has not taken yet.
+func (w *Watcher) send(c *Config) {
+	for {
+		select {
+		case w.changes <- c:
+			return
+		default:
+		}
+		select {
+		case <-w.changes:
+		default:
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package equalizer
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestDefaultPresetsCount(t *testing.T) {
 	presets := DefaultPresets()
-	const expected = 4
+	const expected = 10
 	if len(presets) != expected {
 		t.Fatalf("expected %d presets, got %d", expected, len(presets))
 	}
@@ -26,11 +29,30 @@ func TestFindPresetByName(t *testing.T) {
 	if p.Preamp == 0 || p.Gains[0] == 0 {
 		t.Fatalf("expected non-zero values for Bass Boost preset")
 	}
+	for _, name := range []string{"Studio Rock", "studio pop", "STUDIO JAZZ", "Studio Classical", "Studio Dance", "Loudness"} {
+		if p, ok := FindPresetByName(name); !ok || len(p.Gains) != 10 {
+			t.Fatalf("expected to find %s preset with 10 gains", name)
+		}
+	}
 	if _, ok := FindPresetByName("non-existent"); ok {
 		t.Fatalf("unexpected preset found")
 	}
 }
 
+func TestPresetNamesAvoidVLCPresets(t *testing.T) {
+	// libVLC 3's equalizer presets; a bundled preset of the same name would
+	// take over every station assigned to the VLC curve
+	vlc := []string{"Classical", "Club", "Dance", "Full bass", "Full bass and treble", "Full treble",
+		"Headphones", "Large Hall", "Live", "Party", "Pop", "Reggae", "Rock", "Ska", "Soft", "Soft rock", "Techno"}
+	for _, name := range PresetNames() {
+		for _, v := range vlc {
+			if strings.EqualFold(name, v) {
+				t.Errorf("bundled preset %q shadows the VLC preset", name)
+			}
+		}
+	}
+}
+
 func TestApplyPresetToSliders(t *testing.T) {
 	p := EQPreset{
 		Name:   "Test",
@@ -64,3 +86,49 @@ func TestCustomPresetFromSliders(t *testing.T) {
 		}
 	}
 }
+
+func TestRemapGains(t *testing.T) {
+	near := func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 }
+	// same bands copy through untouched
+	same := RemapGains([]float64{1, 2, 3}, []float64{100, 200, 400}, []float64{100, 200, 400})
+	if !near(same[0], 1) || !near(same[1], 2) || !near(same[2], 3) {
+		t.Fatalf("identity remap = %v", same)
+	}
+	// a centre halfway (in octaves) between two bands gets the mean gain,
+	// targets outside the stored range hold the edge values
+	got := RemapGains([]float64{0, 6, -6}, []float64{100, 400, 1600}, []float64{50, 200, 400, 3200})
+	want := []float64{0, 3, 6, -6}
+	for i := range want {
+		if !near(got[i], want[i]) {
+			t.Fatalf("remap = %v, want %v", got, want)
+		}
+	}
+	// legacy presets without frequencies: equal counts copy by index
+	legacy := RemapGains([]float64{4, 5}, nil, []float64{60, 14000})
+	if !near(legacy[0], 4) || !near(legacy[1], 5) {
+		t.Fatalf("legacy remap = %v", legacy)
+	}
+	// other counts spread across the target range
+	spread := RemapGains([]float64{0, 8}, nil, []float64{100, 200, 400})
+	if !near(spread[0], 0) || !near(spread[1], 4) || !near(spread[2], 8) {
+		t.Fatalf("spread remap = %v", spread)
+	}
+}
+
+func TestToneGainsRoundTrip(t *testing.T) {
+	near := func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 }
+	tone := Tone{Bass: 6, Mid: -3, Treble: 4}
+	gains := ToneGains(tone, ReferenceBandsHz)
+	want := []float64{6, 6, 6, 3, -1.5, -3, -1.5, 2, 4, 4}
+	for i := range want {
+		if !near(gains[i], want[i]) {
+			t.Fatalf("tone gains = %v, want %v", gains, want)
+		}
+	}
+	if got := ToneFromGains(gains, ReferenceBandsHz); !near(got.Bass, 6) || !near(got.Mid, -3) || !near(got.Treble, 4) {
+		t.Fatalf("fitted tone = %+v, want %+v", got, tone)
+	}
+	if ParseMode("Tone") != ModeTone || ParseMode("") != ModeGraphic || ParseMode("knobs") != ModeGraphic {
+		t.Fatal("ParseMode should accept tone and default to graphic")
+	}
+}
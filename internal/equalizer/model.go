@@ -18,8 +18,11 @@ type EQModel struct {
 	Current EQPreset
 }
 
-// Internal copy of the default presets. The values are intentionally simple:
-// they serve as well-known shapes for tests (flat, bass, treble and vocal tilt).
+// Internal copy of the default presets. Flat and the three tilts are simple
+// shapes used by tests; the genre curves are the familiar 10-band starting
+// points from hardware and media-player EQs, with a negative preamp where the
+// boost would otherwise clip. They are named "Studio …" so they never shadow
+// libVLC's own Rock, Pop, Classical and Dance presets.
 var defaultPresets = []EQPreset{
 	{
 		Name:   "Flat",
@@ -41,6 +44,36 @@ var defaultPresets = []EQPreset{
 		Preamp: 0,
 		Gains:  []float64{-2, -1, 1, 3, 4, 3, 1, -1, -2, -3},
 	},
+	{
+		Name:   "Studio Rock",
+		Preamp: -3,
+		Gains:  []float64{5, 4, 3, 1, -1, -1, 1, 3, 4, 5},
+	},
+	{
+		Name:   "Studio Pop",
+		Preamp: -2,
+		Gains:  []float64{-1, 1, 3, 4, 4, 2, 0, -1, -1, -1},
+	},
+	{
+		Name:   "Studio Jazz",
+		Preamp: -2,
+		Gains:  []float64{4, 3, 1, 2, -2, -2, 0, 1, 3, 4},
+	},
+	{
+		Name:   "Studio Classical",
+		Preamp: -2,
+		Gains:  []float64{5, 4, 3, 2, -1, -1, 0, 2, 3, 4},
+	},
+	{
+		Name:   "Studio Dance",
+		Preamp: -4,
+		Gains:  []float64{6, 5, 2, 0, 0, -2, -2, -2, 0, 0},
+	},
+	{
+		Name:   "Loudness",
+		Preamp: -4,
+		Gains:  []float64{6, 4, 1, 0, -1, -1, 0, 1, 4, 5},
+	},
 }
 
 // DefaultPresets returns a deep copy of bundled EQ presets so callers can safely
@@ -53,6 +86,15 @@ func DefaultPresets() []EQPreset {
 	return out
 }
 
+// PresetNames lists the default preset names in their bundled order.
+func PresetNames() []string {
+	out := make([]string, len(defaultPresets))
+	for i, p := range defaultPresets {
+		out[i] = p.Name
+	}
+	return out
+}
+
 // FindPresetByName performs a case-insensitive lookup across default presets.
 func FindPresetByName(name string) (EQPreset, bool) {
 	for _, p := range defaultPresets {
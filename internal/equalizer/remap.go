@@ -0,0 +1,86 @@
+package equalizer
+
+import "math"
+
+// ReferenceBandsHz are the ISO centre frequencies the bundled presets are
+// written for; libVLC's default 10-band equalizer uses the same set.
+var ReferenceBandsHz = []float64{31.25, 62.5, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+
+// RemapGains moves gains measured at fromHz onto the toHz band centres by
+// linear interpolation on a logarithmic frequency axis, holding the outermost
+// values beyond either end. When fromHz does not describe gains (legacy
+// presets saved without frequencies), equal counts are copied index for index
+// and other counts are assumed to span the target range evenly in octaves.
+func RemapGains(gains, fromHz, toHz []float64) []float64 {
+	out := make([]float64, len(toHz))
+	if len(toHz) == 0 || len(gains) == 0 {
+		return out
+	}
+	if len(fromHz) != len(gains) {
+		if len(gains) == len(toHz) {
+			copy(out, gains)
+			return out
+		}
+		fromHz = spreadBands(len(gains), toHz[0], toHz[len(toHz)-1])
+	}
+	if sameBands(fromHz, toHz) {
+		copy(out, gains)
+		return out
+	}
+	for i, f := range toHz {
+		out[i] = gainAt(gains, fromHz, f)
+	}
+	return out
+}
+
+// gainAt interpolates the curve (hz, gains) at f. hz must be ascending.
+func gainAt(gains, hz []float64, f float64) float64 {
+	last := len(hz) - 1
+	if f <= hz[0] || last == 0 {
+		return gains[0]
+	}
+	if f >= hz[last] {
+		return gains[last]
+	}
+	for i := 1; i <= last; i++ {
+		if f > hz[i] {
+			continue
+		}
+		lo, hi := math.Log2(hz[i-1]), math.Log2(hz[i])
+		if hi <= lo {
+			return gains[i]
+		}
+		t := (math.Log2(f) - lo) / (hi - lo)
+		return gains[i-1] + t*(gains[i]-gains[i-1])
+	}
+	return gains[last]
+}
+
+// spreadBands returns n frequencies spaced evenly in octaves from lo to hi.
+func spreadBands(n int, lo, hi float64) []float64 {
+	out := make([]float64, n)
+	if n == 1 || lo <= 0 || hi <= lo {
+		for i := range out {
+			out[i] = lo
+		}
+		return out
+	}
+	step := math.Log2(hi/lo) / float64(n-1)
+	for i := range out {
+		out[i] = lo * math.Exp2(step*float64(i))
+	}
+	return out
+}
+
+// sameBands reports whether a and b name the same centres within 1%.
+func sameBands(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 0.01*b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package equalizer
+
+import (
+	"math"
+	"strings"
+)
+
+// Mode is how the EQ drawer edits the curve.
+type Mode string
+
+const (
+	// ModeGraphic shows one slider per equalizer band; it is the default.
+	ModeGraphic Mode = "graphic"
+	// ModeTone shows bass, mid and treble controls that shape every band at
+	// once.
+	ModeTone Mode = "tone"
+)
+
+// ParseMode reads a stored mode, treating anything unknown as ModeGraphic.
+func ParseMode(s string) Mode {
+	if strings.EqualFold(strings.TrimSpace(s), string(ModeTone)) {
+		return ModeTone
+	}
+	return ModeGraphic
+}
+
+// Tone is a three-band tone control setting in dB.
+type Tone struct {
+	Bass, Mid, Treble float64
+}
+
+// ToneGains spreads t over bands centred at hz: a bass shelf that is full
+// below 125 Hz and gone by 500 Hz, a mid bell two octaves either side of
+// 1 kHz, and a treble shelf that starts at 2 kHz and is full from 8 kHz.
+// libVLC has no separate tone filter, so tone mode plays these gains through
+// the same band equalizer as graphic mode.
+func ToneGains(t Tone, hz []float64) []float64 {
+	out := make([]float64, len(hz))
+	for i, f := range hz {
+		b, m, tr := toneWeights(f)
+		out[i] = t.Bass*b + t.Mid*m + t.Treble*tr
+	}
+	return out
+}
+
+// ToneFromGains estimates the tone setting nearest to gains at hz, so the
+// tone controls can start from any curve. Each control gets its own
+// least-squares fit over the bands it shapes, which recovers a ToneGains
+// curve exactly when the three regions do not overlap, as with the standard
+// ten bands.
+func ToneFromGains(gains, hz []float64) Tone {
+	var num, den [3]float64
+	for i, f := range hz {
+		if i >= len(gains) {
+			break
+		}
+		b, m, tr := toneWeights(f)
+		for k, w := range [3]float64{b, m, tr} {
+			num[k] += w * gains[i]
+			den[k] += w * w
+		}
+	}
+	var fit [3]float64
+	for k := range fit {
+		if den[k] > 0 {
+			fit[k] = num[k] / den[k]
+		}
+	}
+	return Tone{Bass: fit[0], Mid: fit[1], Treble: fit[2]}
+}
+
+// toneWeights is how strongly each control moves a band centred at f.
+func toneWeights(f float64) (bass, mid, treble float64) {
+	if f <= 0 {
+		return 1, 0, 0
+	}
+	oct := math.Log2(f / 1000)
+	bass = clamp01(math.Log2(500/f) / 2)
+	mid = clamp01(1 - math.Abs(oct)/2)
+	treble = clamp01(math.Log2(f/2000) / 2)
+	return bass, mid, treble
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -3,116 +3,222 @@ package metadata
 import (
 	"bufio"
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"html"
-	"net"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// ICY reconnect defaults: a dropped or failed connection is retried up to
+// four times, waiting 500ms, 1s, 2s and 4s, before the stream is given up as
+// without ICY. The wait never grows past 8s.
+const (
+	defaultICYRetries    = 4
+	defaultICYRetryDelay = 500 * time.Millisecond
+	maxICYRetryDelay     = 8 * time.Second
+)
+
+// icyKeepAlive is how often a stream whose metadata blocks are all empty
+// (the title is unchanged) is reported alive with the zero Info.
+const icyKeepAlive = 5 * time.Second
+
+// maxICYRedirects bounds the redirects followed to reach an ICY stream, so a
+// redirect loop ends instead of recursing forever.
+const maxICYRedirects = 5
+
 // directStrategy connects directly to the stream URL and reads ICY metadata
 // blocks interleaved in the response body.
 type directStrategy struct {
 	client *http.Client
 	logger Logger
+	limit  connLimiter
+	// dialTimeout and proxy apply to the fallback client built when client
+	// is nil.
+	dialTimeout time.Duration
+	proxy       proxyFn
+	// retries and retryDelay bound reconnects, see Watch
+	retries    int
+	retryDelay time.Duration
+	// keepAlive is icyKeepAlive, shortened by tests
+	keepAlive time.Duration
+	// onHeaders and onBlock are ProviderOptions.OnICYHeaders and OnICYBlock
+	onHeaders func(streamURL string, h http.Header)
+	onBlock   func(streamURL, raw string)
 }
 
-func newDirectStrategy(client *http.Client, log Logger) *directStrategy {
-	return &directStrategy{client: client, logger: log}
+func newDirectStrategy(client *http.Client, log Logger, limit connLimiter) *directStrategy {
+	return &directStrategy{
+		client:     client,
+		logger:     log,
+		limit:      limit,
+		proxy:      http.ProxyFromEnvironment,
+		retries:    defaultICYRetries,
+		retryDelay: defaultICYRetryDelay,
+		keepAlive:  icyKeepAlive,
+	}
 }
 
-// Watch probes the provided stream for ICY metadata. It runs synchronously and
-// stops when the context is cancelled or an error occurs.
+// Watch reads ICY metadata from the provided stream. It runs synchronously
+// until the context is cancelled. A connection that fails or drops is
+// re-established after a backoff that doubles each time, up to retries times
+// in a row, after which errNoICY is returned so the caller can try other
+// sources; a connection that got as far as reading metadata starts the count
+// afresh. A stream that answers without ICY metadata returns errNoICY at once,
+// and one refused by the TLS settings returns that error at once.
+// onReady fires only for the first connection that serves metadata.
 func (s *directStrategy) Watch(ctx context.Context, streamURL string, onReady func(), onUpdate func(Info)) error {
+	delay := s.retryDelay
+	for failures := 0; ; failures++ {
+		ready, err := s.watchOnce(ctx, streamURL, 0, onReady, onUpdate)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, errNoICY) || isSecurityError(err) {
+			return err
+		}
+		if ready {
+			onReady = nil
+			failures, delay = 0, s.retryDelay
+		}
+		if failures >= s.retries {
+			return fmt.Errorf("%w: %v", errNoICY, err)
+		}
+		if s.logger != nil {
+			s.logger.Printf("icy %s: %v; reconnecting in %s", streamURL, err, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxICYRetryDelay)
+	}
+}
+
+// watchOnce makes one ICY connection and reads it until it fails, reporting
+// whether it got as far as reading metadata. Redirects are followed here, one
+// request per hop, so every hop asks for ICY metadata; hops counts those
+// already followed.
+func (s *directStrategy) watchOnce(ctx context.Context, streamURL string, hops int, onReady func(), onUpdate func(Info)) (bool, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	req.Header.Set("Icy-MetaData", "1")
 	req.Header.Set("User-Agent", defaultUA)
 
-	cli := s.client
-	if cli == nil {
-		cli = &http.Client{
-			Transport: &http.Transport{
-				ForceAttemptHTTP2: false,
-				Proxy:             http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   7 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
-				TLSHandshakeTimeout: 7 * time.Second,
-				TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS10},
-			},
-			CheckRedirect: func(*http.Request, []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-			Timeout: 0,
-		}
+	var cli http.Client
+	if s.client != nil {
+		cli = *s.client
+	} else {
+		cli = *newHTTPClient(s.dialTimeout, s.proxy)
+	}
+	cli.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
 	}
 
+	release, err := s.limit.hold(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer release()
 	resp, err := cli.Do(req)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
-		loc := resp.Header.Get("Location")
-		if loc == "" {
-			return fmt.Errorf("redirect without location")
+		loc, err := resp.Location()
+		if err != nil {
+			return false, fmt.Errorf("redirect without location")
 		}
-		return s.Watch(ctx, loc, onReady, onUpdate)
+		if hops >= maxICYRedirects {
+			return false, fmt.Errorf("%w: stopped after %d redirects", errNoICY, hops)
+		}
+		// free the slot before following so a limit of one cannot deadlock
+		resp.Body.Close()
+		release()
+		return s.watchOnce(ctx, loc.String(), hops+1, onReady, onUpdate)
 	}
 
 	metaIntStr := resp.Header.Get("icy-metaint")
 	if metaIntStr == "" {
-		return errNoICY
+		return false, errNoICY
 	}
 
 	metaInt, err := strconv.Atoi(metaIntStr)
 	if err != nil || metaInt <= 0 {
-		return errNoICY
+		return false, errNoICY
 	}
+	// the slot bounds connection attempts; a stream that is being read for
+	// as long as it plays must not starve the other strategies
+	release()
 
-	station := strings.TrimSpace(resp.Header.Get("icy-name"))
-	if station != "" {
-		onUpdate(Info{Station: html.UnescapeString(station)})
+	if s.onHeaders != nil {
+		s.onHeaders(streamURL, resp.Header)
+	}
+	station := html.UnescapeString(strings.TrimSpace(resp.Header.Get("icy-name")))
+	genre := html.UnescapeString(strings.TrimSpace(resp.Header.Get("icy-genre")))
+	bitrate := parseICYBitrate(resp.Header.Get("icy-br"))
+	if station != "" || genre != "" || bitrate > 0 {
+		onUpdate(Info{Station: station, Genre: genre, Bitrate: bitrate})
 	}
 	if onReady != nil {
 		onReady()
-		onReady = nil
 	}
 
 	reader := bufio.NewReader(resp.Body)
 	buf := make([]byte, metaInt)
+	reported := time.Now()
 	for {
 		if _, err := ioReadFull(ctx, reader, buf); err != nil {
-			return err
+			return true, err
 		}
 
 		length, err := reader.ReadByte()
 		if err != nil {
-			return err
+			return true, err
 		}
 
 		metaLen := int(length) * 16
 		if metaLen == 0 {
+			// servers send empty blocks while the title is unchanged
+			if time.Since(reported) >= s.keepAlive {
+				reported = time.Now()
+				onUpdate(Info{})
+			}
+			if s.onBlock != nil {
+				s.onBlock(streamURL, "")
+			}
 			continue
 		}
 
 		metaBuf := make([]byte, metaLen)
 		if _, err := ioReadFull(ctx, reader, metaBuf); err != nil {
-			return err
+			return true, err
 		}
 
+		raw := strings.TrimRight(string(metaBuf), "\x00")
 		text := extractStreamTitle(string(metaBuf))
 		if text != "" {
-			onUpdate(Info{Title: fixMojibake(text), Station: html.UnescapeString(station)})
+			reported = time.Now()
+			onUpdate(Info{
+				Title:      fixMojibake(text),
+				Station:    station,
+				ArtworkURL: extractStreamURL(string(metaBuf)),
+				Genre:      genre,
+				Bitrate:    bitrate,
+				Raw:        raw,
+			})
+		}
+		// after the update, so a caller stopping at this block has seen it
+		if s.onBlock != nil {
+			s.onBlock(streamURL, raw)
 		}
 	}
 }
@@ -0,0 +1,241 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	musicBrainzSearchURL = "https://musicbrainz.org/ws/2/recording"
+	coverArtReleaseURL   = "https://coverartarchive.org/release"
+	// musicBrainzUA identifies the app as MusicBrainz asks every client to;
+	// generic agents are throttled harder.
+	musicBrainzUA = "MiniRadio/1.0 ( https://github.com/edward-ap/miniradio )"
+	// musicBrainzInterval keeps to MusicBrainz's limit of one request per
+	// second.
+	musicBrainzInterval = time.Second
+	// enrichCacheSize bounds the remembered lookups, found or not.
+	enrichCacheSize = 512
+	// minRecordingScore is the MusicBrainz search score (0–100) a recording
+	// needs before its release is trusted to match the stream title.
+	minRecordingScore = 90
+	// maxCoverReleases is how many matching releases are checked for art.
+	maxCoverReleases = 3
+)
+
+// Track is what an Enricher found for a stream title: the canonical artist,
+// title and album from MusicBrainz, and a Cover Art Archive image ("" when
+// none of the matching releases has one).
+type Track struct {
+	Artist     string
+	Title      string
+	Album      string
+	ArtworkURL string
+}
+
+// Enricher looks up "Artist - Title" stream titles on MusicBrainz and the
+// Cover Art Archive. It is meant for titles whose source sends no artwork and
+// runs beside the metadata strategies, never in their path. Results, including
+// misses, are cached by normalised title and requests are spaced to respect
+// MusicBrainz's rate limit. It is safe for concurrent use.
+type Enricher struct {
+	client    *http.Client
+	searchURL string
+	coverURL  string
+	interval  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]enrichResult
+
+	// reqMu serialises MusicBrainz requests; last is when the previous one
+	// was sent
+	reqMu sync.Mutex
+	last  time.Time
+}
+
+type enrichResult struct {
+	track Track
+	found bool
+}
+
+// NewEnricher builds an Enricher that connects with the proxy and dial timeout
+// of opts; its User-Agent is the app's own, as MusicBrainz requires.
+func NewEnricher(opts ProviderOptions) *Enricher {
+	opts = opts.withDefaults()
+	proxy, _ := parseProxy(opts.Proxy)
+	return &Enricher{
+		client:    newHTTPClient(opts.DialTimeout, proxy),
+		searchURL: musicBrainzSearchURL,
+		coverURL:  coverArtReleaseURL,
+		interval:  musicBrainzInterval,
+		cache:     map[string]enrichResult{},
+	}
+}
+
+// SplitArtistTitle splits a "Artist - Title" stream title at the first
+// " - ". ok is false when either side is empty.
+func SplitArtistTitle(s string) (artist, title string, ok bool) {
+	artist, title, ok = strings.Cut(s, " - ")
+	artist, title = strings.TrimSpace(artist), strings.TrimSpace(title)
+	return artist, title, ok && artist != "" && title != ""
+}
+
+// Lookup returns what MusicBrainz knows about streamTitle. ok is false when
+// the title is not "Artist - Title", nothing matched, or the lookup failed;
+// failures other than cancellation are cached like misses so a broken network
+// is not retried on every title change.
+func (e *Enricher) Lookup(ctx context.Context, streamTitle string) (Track, bool) {
+	artist, title, ok := SplitArtistTitle(streamTitle)
+	if !ok {
+		return Track{}, false
+	}
+	key := enrichKey(artist, title)
+	e.mu.Lock()
+	res, hit := e.cache[key]
+	e.mu.Unlock()
+	if hit {
+		return res.track, res.found
+	}
+
+	track, releases, found, err := e.search(ctx, artist, title)
+	if err != nil && ctx.Err() != nil {
+		return Track{}, false
+	}
+	if found {
+		track.ArtworkURL = e.findCover(ctx, releases)
+	}
+	e.mu.Lock()
+	if len(e.cache) >= enrichCacheSize {
+		// stations repeat a small rotation, so starting over is cheap
+		e.cache = map[string]enrichResult{}
+	}
+	e.cache[key] = enrichResult{track: track, found: found}
+	e.mu.Unlock()
+	return track, found
+}
+
+// enrichKey normalises artist and title for the cache: case and runs of
+// whitespace are ignored.
+func enrichKey(artist, title string) string {
+	norm := func(s string) string { return strings.Join(strings.Fields(strings.ToLower(s)), " ") }
+	return norm(artist) + "\x00" + norm(title)
+}
+
+// mbSearch is the part of a MusicBrainz recording search response used here.
+type mbSearch struct {
+	Recordings []struct {
+		ID           string `json:"id"`
+		Score        int    `json:"score"`
+		Title        string `json:"title"`
+		ArtistCredit []struct {
+			Name       string `json:"name"`
+			JoinPhrase string `json:"joinphrase"`
+		} `json:"artist-credit"`
+		Releases []struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"releases"`
+	} `json:"recordings"`
+}
+
+// search queries MusicBrainz for the best recording of artist and title and
+// returns it with the IDs of up to maxCoverReleases of its releases.
+func (e *Enricher) search(ctx context.Context, artist, title string) (Track, []string, bool, error) {
+	q := fmt.Sprintf(`artist:"%s" AND recording:"%s"`, luceneQuote(artist), luceneQuote(title))
+	target := e.searchURL + "?" + url.Values{"query": {q}, "fmt": {"json"}, "limit": {"5"}}.Encode()
+	if err := e.wait(ctx); err != nil {
+		return Track{}, nil, false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return Track{}, nil, false, err
+	}
+	req.Header.Set("User-Agent", musicBrainzUA)
+	req.Header.Set("Accept", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return Track{}, nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Track{}, nil, false, fmt.Errorf("musicbrainz: %s", resp.Status)
+	}
+	var body mbSearch
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Track{}, nil, false, fmt.Errorf("musicbrainz: %w", err)
+	}
+	for _, r := range body.Recordings {
+		if r.Score < minRecordingScore {
+			continue
+		}
+		var credit strings.Builder
+		for _, c := range r.ArtistCredit {
+			credit.WriteString(c.Name + c.JoinPhrase)
+		}
+		t := Track{Artist: strings.TrimSpace(credit.String()), Title: r.Title}
+		var ids []string
+		for i, rel := range r.Releases {
+			if i == 0 {
+				t.Album = rel.Title
+			}
+			if len(ids) < maxCoverReleases && rel.ID != "" {
+				ids = append(ids, rel.ID)
+			}
+		}
+		return t, ids, true, nil
+	}
+	return Track{}, nil, false, nil
+}
+
+// findCover returns the 250 px front cover of the first release that has
+// one, or "".
+func (e *Enricher) findCover(ctx context.Context, releases []string) string {
+	for _, id := range releases {
+		art := e.coverURL + "/" + url.PathEscape(id) + "/front-250"
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, art, nil)
+		if err != nil {
+			continue
+		}
+		req.Header.Set("User-Agent", musicBrainzUA)
+		resp, err := e.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ""
+			}
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return art
+		}
+	}
+	return ""
+}
+
+// wait blocks until the next MusicBrainz request may be sent.
+func (e *Enricher) wait(ctx context.Context) error {
+	e.reqMu.Lock()
+	defer e.reqMu.Unlock()
+	if d := e.interval - time.Since(e.last); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	e.last = time.Now()
+	return nil
+}
+
+// luceneQuote escapes s for use inside a quoted Lucene phrase.
+func luceneQuote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
@@ -2,12 +2,22 @@ package metadata
 
 import (
 	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/tls"
+	"fmt"
 	"html"
 	"io"
+	"net"
+	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 )
 
@@ -15,6 +25,167 @@ import (
 // agents, so we mimic a simple desktop client.
 var defaultUA = "MiniRadio/1.0 (+https://local)"
 
+const (
+	defaultPollInterval   = 10 * time.Second
+	defaultRequestTimeout = 2 * time.Second
+	defaultDialTimeout    = 7 * time.Second
+	// defaultDiscoveryTimeout bounds autoWatch, see
+	// ProviderOptions.DiscoveryTimeout
+	defaultDiscoveryTimeout = 15 * time.Second
+)
+
+// orDefault returns d unless it is zero or negative.
+func orDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+// proxyFn selects the proxy for a request, as http.Transport.Proxy does.
+type proxyFn func(*http.Request) (*url.URL, error)
+
+// parseProxy maps a ProviderOptions.Proxy value to a proxy function: "" and
+// ProxySystem use the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment, ProxyNone
+// connects directly, and anything else must be an absolute proxy URL such as
+// "http://proxy.corp:3128" or "socks5://127.0.0.1:1080".
+func parseProxy(spec string) (proxyFn, error) {
+	spec = strings.TrimSpace(spec)
+	switch strings.ToLower(spec) {
+	case "", ProxySystem:
+		return http.ProxyFromEnvironment, nil
+	case ProxyNone:
+		return nil, nil
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return http.ProxyFromEnvironment, fmt.Errorf("proxy %q: %w", spec, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return http.ProxyFromEnvironment, fmt.Errorf("proxy %q: want system, none or a scheme://host:port URL", spec)
+	}
+	return http.ProxyURL(u), nil
+}
+
+// newHTTPClient builds the client used when callers do not supply one: no
+// HTTP/2 (ICY servers speak HTTP/1.x), permissive TLS for old Icecast boxes,
+// and bounded connect/handshake times without an overall deadline so
+// long-lived ICY reads keep streaming. A nil proxy connects directly.
+func newHTTPClient(dialTimeout time.Duration, proxy proxyFn) *http.Client {
+	dialTimeout = orDefault(dialTimeout, defaultDialTimeout)
+	return &http.Client{
+		Transport: &http.Transport{
+			ForceAttemptHTTP2: false,
+			Proxy:             proxy,
+			DialContext: (&net.Dialer{
+				Timeout:   dialTimeout,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: dialTimeout,
+			TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS10},
+		},
+		Timeout: 0,
+	}
+}
+
+// withIPVersion returns a copy of client that dials over IPv4 only (v = 4)
+// or IPv6 only (v = 6). Any other v, or a client whose transport is not an
+// *http.Transport, returns client itself.
+func withIPVersion(client *http.Client, v int) *http.Client {
+	if v != 4 && v != 6 {
+		return client
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return client
+	}
+	t = t.Clone()
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dial(ctx, ipNetwork(network, v), addr)
+	}
+	out := *client
+	out.Transport = t
+	return &out
+}
+
+// ipNetwork narrows a "tcp" or "udp" dial to IP version v; networks that
+// already name a version are left alone.
+func ipNetwork(network string, v int) string {
+	if network == "tcp" || network == "udp" {
+		return network + strconv.Itoa(v)
+	}
+	return network
+}
+
+// headerTransport overrides the User-Agent and Referer strategies put on
+// their requests, for stations that only answer particular clients.
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	referer   string
+}
+
+func (t *headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	if t.userAgent != "" {
+		r.Header.Set("User-Agent", t.userAgent)
+	}
+	if t.referer != "" {
+		r.Header.Set("Referer", t.referer)
+	}
+	return t.base.RoundTrip(r)
+}
+
+// withHeaders returns a copy of client whose requests carry userAgent and
+// referer; client itself is returned when both are empty.
+func withHeaders(client *http.Client, userAgent, referer string) *http.Client {
+	userAgent, referer = strings.TrimSpace(userAgent), strings.TrimSpace(referer)
+	if userAgent == "" && referer == "" {
+		return client
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	out := *client
+	out.Transport = &headerTransport{base: base, userAgent: userAgent, referer: referer}
+	return &out
+}
+
+// connLimiter is a counting semaphore bounding concurrent metadata
+// connections. A nil limiter never blocks.
+type connLimiter chan struct{}
+
+func newConnLimiter(n int) connLimiter {
+	if n <= 0 {
+		return nil
+	}
+	return make(connLimiter, n)
+}
+
+// hold waits for a free slot, giving up when ctx is cancelled. The returned
+// release func is idempotent so it can be both deferred and called early.
+func (l connLimiter) hold(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-l }) }, nil
+}
+
 // ioReadFull mirrors io.ReadFull but aborts promptly when ctx is cancelled.
 func ioReadFull(ctx context.Context, r io.Reader, buf []byte) (int, error) {
 	type reader interface {
@@ -39,6 +210,31 @@ func ioReadFull(ctx context.Context, r io.Reader, buf []byte) (int, error) {
 	}
 }
 
+// acceptEncoding is sent by strategies that decode bodies with decodedBody.
+const acceptEncoding = "gzip, deflate"
+
+// decodedBody returns resp's body uncompressed according to its
+// Content-Encoding. Go's transport only undoes gzip it asked for itself, and
+// proxies in front of some Icecast servers compress anyway. "deflate" is read
+// as zlib, or as a bare deflate stream from servers that get it wrong. Callers
+// still cap what they read, as the decompressed size is unbounded.
+func decodedBody(resp *http.Response) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		br := bufio.NewReader(resp.Body)
+		if h, err := br.Peek(2); err == nil && h[0]&0x0f == 8 && (int(h[0])<<8|int(h[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+}
+
 // fixMojibake keeps ASCII text untouched (some stations send pre-encoded UTF-8
 // via ISO-8859-1); for non-ASCII we defer to the original string.
 func fixMojibake(s string) string {
@@ -62,16 +258,42 @@ func isASCII(s string) bool {
 
 // extractStreamTitle parses ICY metadata blocks, extracting `StreamTitle`.
 func extractStreamTitle(meta string) string {
+	return extractICYField(meta, "StreamTitle")
+}
+
+// extractStreamURL returns the `StreamUrl` field of an ICY metadata block when
+// it holds an absolute http(s) URL, which stations commonly use for cover art.
+// Anything else yields "" so a malformed value never disturbs title parsing.
+func extractStreamURL(meta string) string {
+	return artworkURL(extractICYField(meta, "StreamUrl"))
+}
+
+// artworkURL keeps raw only if it is an absolute http(s) URL.
+func artworkURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+// extractICYField pulls a single `Key='value';` pair out of an ICY metadata
+// block, tolerating unescaped quotes inside the value.
+func extractICYField(meta, key string) string {
 	if meta == "" {
 		return ""
 	}
 
-	idx := strings.Index(meta, "StreamTitle=")
+	idx := strings.Index(meta, key+"=")
 	if idx < 0 {
 		return ""
 	}
 
-	meta = meta[idx+12:]
+	meta = meta[idx+len(key)+1:]
 	meta = strings.TrimSpace(meta)
 	if meta == "" {
 		return ""
@@ -185,3 +407,17 @@ func stationCacheKey(raw string) string {
 
 	return strings.ToLower(u.Host) + "|" + baseNameFromURL(u)
 }
+
+// parseICYBitrate reads an icy-br header. Some servers list several values
+// ("128,128"); the first one wins. Returns 0 when absent or malformed.
+func parseICYBitrate(v string) int {
+	v = strings.TrimSpace(v)
+	if i := strings.IndexAny(v, ",; "); i >= 0 {
+		v = v[:i]
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,390 @@
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+var errNoID3 = errors.New("hls id3 metadata unavailable")
+
+const (
+	// hlsDefaultTargetDuration is used when a playlist omits EXT-X-TARGETDURATION.
+	hlsDefaultTargetDuration = 6 * time.Second
+	// hlsMaxSegmentBytes caps how much of a media segment is scanned for ID3.
+	hlsMaxSegmentBytes = 4 << 20
+)
+
+// hlsStrategy reads ID3v2 timed metadata carried inside HLS media segments.
+// It follows the playlist on its target duration and scans the newest segment
+// for TIT2/TPE1 frames.
+type hlsStrategy struct {
+	client *http.Client
+	logger Logger
+	limit  connLimiter
+}
+
+func newHLSStrategy(client *http.Client, log Logger, limit connLimiter) *hlsStrategy {
+	return &hlsStrategy{client: client, logger: log, limit: limit}
+}
+
+// isHLSURL reports whether the stream URL points at an HLS playlist.
+func isHLSURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u == nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Path), ".m3u8")
+}
+
+// Watch polls the playlist until the context is cancelled. It fails with
+// errNoID3 when the newest segment carries no usable ID3 frames so the
+// dispatcher can fall through to other strategies.
+func (s *hlsStrategy) Watch(ctx context.Context, playlistURL string, onReady func(), onUpdate func(Info)) error {
+	mediaURL, pl, err := s.loadMediaPlaylist(ctx, playlistURL)
+	if err != nil {
+		return err
+	}
+	last, info, ok := s.readNewest(ctx, pl, "")
+	if !ok {
+		return errNoID3
+	}
+	if onReady != nil {
+		onReady()
+	}
+	onUpdate(info)
+	for {
+		timer := time.NewTimer(pl.targetDuration)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		next, _, err := s.fetchPlaylist(ctx, mediaURL)
+		if err != nil {
+			continue
+		}
+		pl = next
+		seg, info, ok := s.readNewest(ctx, pl, last)
+		if seg != "" {
+			last = seg
+		}
+		if ok {
+			onUpdate(info)
+		}
+	}
+}
+
+// loadMediaPlaylist fetches the playlist and, for master playlists, descends
+// into the first variant so segments are available.
+func (s *hlsStrategy) loadMediaPlaylist(ctx context.Context, playlistURL string) (string, hlsPlaylist, error) {
+	pl, final, err := s.fetchPlaylist(ctx, playlistURL)
+	if err != nil {
+		return "", hlsPlaylist{}, err
+	}
+	if len(pl.segments) == 0 && len(pl.variants) > 0 {
+		variant := pl.variants[0]
+		pl, final, err = s.fetchPlaylist(ctx, variant)
+		if err != nil {
+			return "", hlsPlaylist{}, err
+		}
+	}
+	if len(pl.segments) == 0 {
+		return "", hlsPlaylist{}, errors.New("hls playlist has no segments")
+	}
+	return final, pl, nil
+}
+
+// readNewest downloads the last segment of the playlist unless it matches
+// the previously processed one, returning its URL and any ID3 info found.
+func (s *hlsStrategy) readNewest(ctx context.Context, pl hlsPlaylist, previous string) (string, Info, bool) {
+	if len(pl.segments) == 0 {
+		return "", Info{}, false
+	}
+	seg := pl.segments[len(pl.segments)-1]
+	if seg == previous {
+		return "", Info{}, false
+	}
+	data, _, err := s.fetch(ctx, seg, hlsMaxSegmentBytes)
+	if err != nil {
+		return "", Info{}, false
+	}
+	info, ok := scanID3(data)
+	return seg, info, ok
+}
+
+// fetchPlaylist downloads and parses a playlist, returning the final URL used
+// as the base for relative entries.
+func (s *hlsStrategy) fetchPlaylist(ctx context.Context, playlistURL string) (hlsPlaylist, string, error) {
+	body, final, err := s.fetch(ctx, playlistURL, 1<<20)
+	if err != nil {
+		return hlsPlaylist{}, "", err
+	}
+	base, err := url.Parse(final)
+	if err != nil {
+		return hlsPlaylist{}, "", err
+	}
+	return parseHLSPlaylist(base, string(body)), final, nil
+}
+
+// fetch performs a bounded GET and reports the URL after redirects.
+func (s *hlsStrategy) fetch(ctx context.Context, target string, maxBytes int64) ([]byte, string, error) {
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(cctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", defaultUA)
+	release, err := s.limit.hold(cctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("hls fetch: status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+	if err != nil {
+		return nil, "", err
+	}
+	final := target
+	if resp.Request != nil && resp.Request.URL != nil {
+		final = resp.Request.URL.String()
+	}
+	return body, final, nil
+}
+
+// hlsPlaylist holds the parts of an M3U8 playlist the strategy needs.
+type hlsPlaylist struct {
+	targetDuration time.Duration
+	segments       []string
+	variants       []string
+}
+
+// parseHLSPlaylist extracts absolute segment and variant URLs plus the target
+// duration from an M3U8 body.
+func parseHLSPlaylist(base *url.URL, body string) hlsPlaylist {
+	pl := hlsPlaylist{targetDuration: hlsDefaultTargetDuration}
+	nextIsVariant := false
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			switch {
+			case strings.HasPrefix(line, "#EXT-X-TARGETDURATION:"):
+				v := strings.TrimPrefix(line, "#EXT-X-TARGETDURATION:")
+				if secs, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && secs > 0 {
+					pl.targetDuration = time.Duration(secs * float64(time.Second))
+				}
+			case strings.HasPrefix(line, "#EXT-X-STREAM-INF"):
+				nextIsVariant = true
+			}
+			continue
+		}
+		ref, err := url.Parse(line)
+		if err != nil {
+			nextIsVariant = false
+			continue
+		}
+		abs := ref.String()
+		if base != nil {
+			abs = base.ResolveReference(ref).String()
+		}
+		if nextIsVariant {
+			pl.variants = append(pl.variants, abs)
+		} else {
+			pl.segments = append(pl.segments, abs)
+		}
+		nextIsVariant = false
+	}
+	if pl.targetDuration < time.Second {
+		pl.targetDuration = time.Second
+	}
+	return pl
+}
+
+// scanID3 searches a segment for ID3v2 tags and returns the info from the last
+// tag that carried a title or artist.
+func scanID3(data []byte) (Info, bool) {
+	var (
+		out   Info
+		found bool
+	)
+	for i := 0; i+10 <= len(data); {
+		j := bytes.Index(data[i:], []byte("ID3"))
+		if j < 0 {
+			break
+		}
+		start := i + j
+		if info, n, ok := parseID3v2(data[start:]); ok {
+			out, found = info, true
+			i = start + n
+			continue
+		}
+		i = start + 3
+	}
+	return out, found
+}
+
+// parseID3v2 decodes a single ID3v2.2–2.4 tag at the start of b, returning the
+// info, the tag length in bytes, and whether a title or artist was present.
+func parseID3v2(b []byte) (Info, int, bool) {
+	if len(b) < 10 || string(b[:3]) != "ID3" {
+		return Info{}, 0, false
+	}
+	major := b[3]
+	if major < 2 || major > 4 || b[4] == 0xFF {
+		return Info{}, 0, false
+	}
+	size, ok := syncsafe(b[6:10])
+	if !ok || 10+size > len(b) {
+		return Info{}, 0, false
+	}
+	end := 10 + size
+	body := b[10:end]
+	if b[5]&0x40 != 0 && major >= 3 {
+		// skip the extended header
+		if len(body) < 4 {
+			return Info{}, 0, false
+		}
+		ext := int(binary.BigEndian.Uint32(body[:4]))
+		if major == 4 {
+			ext, _ = syncsafe(body[:4])
+		} else {
+			ext += 4
+		}
+		if ext > len(body) {
+			return Info{}, 0, false
+		}
+		body = body[ext:]
+	}
+
+	idLen, hdrLen := 4, 10
+	if major == 2 {
+		idLen, hdrLen = 3, 6
+	}
+	var title, artist, station string
+	for len(body) >= hdrLen {
+		if body[0] == 0 {
+			break // padding
+		}
+		id := string(body[:idLen])
+		var frameSize int
+		switch major {
+		case 2:
+			frameSize = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			frameSize = int(binary.BigEndian.Uint32(body[4:8]))
+		default:
+			frameSize, ok = syncsafe(body[4:8])
+			if !ok {
+				frameSize = -1
+			}
+		}
+		if frameSize < 0 || hdrLen+frameSize > len(body) {
+			break
+		}
+		frame := body[hdrLen : hdrLen+frameSize]
+		switch id {
+		case "TIT2", "TT2":
+			title = decodeID3Text(frame)
+		case "TPE1", "TP1":
+			artist = decodeID3Text(frame)
+		case "TRSN":
+			station = decodeID3Text(frame)
+		}
+		body = body[hdrLen+frameSize:]
+	}
+	if title == "" && artist == "" {
+		return Info{}, end, false
+	}
+	full := title
+	if artist != "" && title != "" {
+		full = artist + " - " + title
+	} else if artist != "" {
+		full = artist
+	}
+	return Info{Title: fixMojibake(full), Station: fixMojibake(station)}, end, true
+}
+
+// syncsafe decodes a 28-bit ID3 syncsafe integer.
+func syncsafe(b []byte) (int, bool) {
+	if len(b) != 4 {
+		return 0, false
+	}
+	n := 0
+	for _, c := range b {
+		if c&0x80 != 0 {
+			return 0, false
+		}
+		n = n<<7 | int(c)
+	}
+	return n, true
+}
+
+// decodeID3Text converts a text frame payload to a Go string, honoring the
+// leading encoding byte and keeping only the first of multiple values.
+func decodeID3Text(frame []byte) string {
+	if len(frame) == 0 {
+		return ""
+	}
+	enc, data := frame[0], frame[1:]
+	var s string
+	switch enc {
+	case 1, 2:
+		bigEndian := enc == 2
+		if len(data) >= 2 {
+			switch {
+			case data[0] == 0xFF && data[1] == 0xFE:
+				bigEndian, data = false, data[2:]
+			case data[0] == 0xFE && data[1] == 0xFF:
+				bigEndian, data = true, data[2:]
+			}
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			if bigEndian {
+				units = append(units, binary.BigEndian.Uint16(data[i:]))
+			} else {
+				units = append(units, binary.LittleEndian.Uint16(data[i:]))
+			}
+		}
+		s = string(utf16.Decode(units))
+	case 3:
+		s = string(data)
+	default:
+		runes := make([]rune, len(data))
+		for i, c := range data {
+			runes[i] = rune(c)
+		}
+		s = string(runes)
+	}
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
@@ -53,3 +53,135 @@ func TestExtractStreamTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSevenHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{name: "html wrapped", body: "<html><body>1,1,5,100,3,128,Artist - Track</body></html>", want: "Artist - Track", wantOK: true},
+		{name: "title with commas", body: "1,1,5,100,3,128,Crosby, Stills & Nash - Song", want: "Crosby, Stills & Nash - Song", wantOK: true},
+		{name: "entities", body: "1,1,5,100,3,128,AC/DC &amp; Friends", want: "AC/DC & Friends", wantOK: true},
+		{name: "too few fields", body: "1,1,5,100", wantOK: false},
+		{name: "empty title", body: "1,0,5,100,3,128,", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseSevenHTML(tt.body)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("parseSevenHTML(%q) = %q, %v; want %q, %v", tt.body, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestScanID3(t *testing.T) {
+	seg := append([]byte{0x47, 0x40, 0x11, 0x10}, buildID3Tag(4, "Artist", "Track")...)
+	seg = append(seg, 0x00, 0xFF, 0xF1)
+	info, ok := scanID3(seg)
+	if !ok || info.Title != "Artist - Track" {
+		t.Fatalf("scanID3 = %+v, %v", info, ok)
+	}
+
+	utf16Frame := []byte{1, 0xFF, 0xFE, 'S', 0, 'o', 0, 'n', 0, 'g', 0}
+	if got := decodeID3Text(utf16Frame); got != "Song" {
+		t.Fatalf("decodeID3Text(utf16) = %q", got)
+	}
+	if _, ok := scanID3([]byte("no tags here ID3 broken")); ok {
+		t.Fatal("expected no tag in garbage input")
+	}
+}
+
+// buildID3Tag assembles an ID3v2.3/2.4 tag with UTF-8/Latin-1 TPE1 and TIT2 frames.
+func buildID3Tag(major byte, artist, title string) []byte {
+	frame := func(id, text string) []byte {
+		enc := byte(0)
+		if major == 4 {
+			enc = 3
+		}
+		payload := append([]byte{enc}, text...)
+		n := len(payload)
+		hdr := []byte(id)
+		if major == 4 {
+			hdr = append(hdr, byte(n>>21&0x7F), byte(n>>14&0x7F), byte(n>>7&0x7F), byte(n&0x7F))
+		} else {
+			hdr = append(hdr, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+		}
+		hdr = append(hdr, 0, 0)
+		return append(hdr, payload...)
+	}
+	body := append(frame("TPE1", artist), frame("TIT2", title)...)
+	n := len(body)
+	tag := []byte{'I', 'D', '3', major, 0, 0, byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+	return append(tag, body...)
+}
+
+func TestExtractStreamURL(t *testing.T) {
+	tests := []struct {
+		name string
+		meta string
+		want string
+	}{
+		{name: "after title", meta: "StreamTitle='Artist - Track';StreamUrl='http://cdn.example/cover.jpg';", want: "http://cdn.example/cover.jpg"},
+		{name: "title keeps parsing", meta: "StreamTitle='A;B';StreamUrl='https://x.example/a.png';", want: "https://x.example/a.png"},
+		{name: "empty value", meta: "StreamTitle='Track';StreamUrl='';", want: ""},
+		{name: "relative value", meta: "StreamTitle='Track';StreamUrl='cover.jpg';", want: ""},
+		{name: "non http scheme", meta: "StreamUrl='ftp://x.example/a.jpg';", want: ""},
+		{name: "absent", meta: "StreamTitle='Track';", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractStreamURL(tt.meta); got != tt.want {
+				t.Fatalf("extractStreamURL(%q) = %q, want %q", tt.meta, got, tt.want)
+			}
+		})
+	}
+	if got := extractStreamTitle("StreamTitle='Track';StreamUrl='garbage"); got != "Track" {
+		t.Fatalf("title should survive a malformed StreamUrl, got %q", got)
+	}
+}
+
+func TestPlaylistEntries(t *testing.T) {
+	pls := "[playlist]\nNumberOfEntries=2\nFile2=http://b.example/two\nFile1=http://a.example/one\nTitle1=One\n"
+	if got := firstPLSEntry(pls); got != "http://a.example/one" {
+		t.Fatalf("firstPLSEntry = %q", got)
+	}
+	m3u := "\xef\xbb\xbf#EXTM3U\n#EXTINF:-1,Station\n\nhttp://c.example/live\nhttp://d.example/backup\n"
+	if got := firstM3UEntry(m3u); got != "http://c.example/live" {
+		t.Fatalf("firstM3UEntry = %q", got)
+	}
+	tests := []struct {
+		ct, path, head, want string
+	}{
+		{ct: "audio/x-scpls", want: "pls"},
+		{ct: "audio/x-mpegurl; charset=utf-8", want: "m3u"},
+		{ct: "text/plain", head: "[playlist]\nFile1", want: "pls"},
+		{head: "#EXTM3U\n", want: "m3u"},
+		{ct: "text/plain", path: "/listen.m3u", head: "http://x/live\n", want: "m3u"},
+		{ct: "audio/mpeg", path: "/listen.m3u", head: "\xff\xfb\x90\x00", want: ""},
+		{ct: "audio/mpeg", path: "/stream", head: "\xff\xfb\x90\x00", want: ""},
+	}
+	for _, tt := range tests {
+		if got := playlistKind(tt.ct, tt.path, []byte(tt.head)); got != tt.want {
+			t.Errorf("playlistKind(%q, %q, %q) = %q, want %q", tt.ct, tt.path, tt.head, got, tt.want)
+		}
+	}
+}
+
+func TestParseICYBitrate(t *testing.T) {
+	cases := map[string]int{
+		"128":     128,
+		" 320 ":   320,
+		"128,128": 128,
+		"":        0,
+		"abc":     0,
+		"-64":     0,
+	}
+	for in, want := range cases {
+		if got := parseICYBitrate(in); got != want {
+			t.Fatalf("parseICYBitrate(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
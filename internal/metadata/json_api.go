@@ -0,0 +1,237 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNoJSONFields is returned when a now-playing API answers with JSON whose
+// configured paths hold neither a title nor a station name.
+var errNoJSONFields = errors.New("no title or station at the configured JSON paths")
+
+// JSONFields say where the JSON of a MetadataTypeAPI, MetadataTypeWS or
+// MetadataTypeSSE source keeps each field, as dot-separated paths such as
+// "data.current.title". Numeric parts index arrays; "tracks[0].title" and a
+// leading "$." are accepted too. An empty path tries the common layouts: a
+// flat {"title", "artist"} object and AzuraCast's now-playing data. An artist
+// is joined to the title as "Artist - Title".
+type JSONFields struct {
+	Title   string
+	Artist  string
+	Station string
+	Artwork string
+}
+
+// default paths tried for JSONFields left empty
+var (
+	jsonTitlePaths   = []string{"title", "song.title", "now_playing.song.title", "np.now_playing.song.title"}
+	jsonArtistPaths  = []string{"artist", "song.artist", "now_playing.song.artist", "np.now_playing.song.artist"}
+	jsonStationPaths = []string{"station", "station.name", "np.station.name"}
+	jsonArtworkPaths = []string{"artwork", "art", "song.art", "now_playing.song.art", "np.now_playing.song.art"}
+)
+
+// jsonIndex matches the "[n]" array steps of a path.
+var jsonIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+// ValidateJSONPath reports whether path is a usable JSONFields path; "" is
+// valid and selects the defaults.
+func ValidateJSONPath(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	for _, key := range jsonPathKeys(path) {
+		if key == "" {
+			return fmt.Errorf("JSON path %q has an empty step", path)
+		}
+		if strings.ContainsAny(key, " []$*") {
+			return fmt.Errorf("JSON path %q: bad step %q", path, key)
+		}
+	}
+	return nil
+}
+
+// Validate checks every path of f with ValidateJSONPath.
+func (f JSONFields) Validate() error {
+	for _, p := range []string{f.Title, f.Artist, f.Station, f.Artwork} {
+		if err := ValidateJSONPath(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonPathKeys splits path into the keys and indexes it steps through.
+func jsonPathKeys(path string) []string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = jsonIndex.ReplaceAllString(path, ".$1")
+	return strings.Split(path, ".")
+}
+
+// info maps one JSON document onto Info, reporting false when it does not
+// parse or carries neither a title nor a station name.
+func (f JSONFields) info(msg []byte) (Info, bool) {
+	var v any
+	if err := json.Unmarshal(msg, &v); err != nil {
+		return Info{}, false
+	}
+	title := jsonField(v, f.Title, jsonTitlePaths)
+	if artist := jsonField(v, f.Artist, jsonArtistPaths); artist != "" && title != "" {
+		title = artist + " - " + title
+	}
+	info := Info{
+		Title:      title,
+		Station:    jsonField(v, f.Station, jsonStationPaths),
+		ArtworkURL: artworkURL(jsonField(v, f.Artwork, jsonArtworkPaths)),
+	}
+	return info, info.Title != "" || info.Station != ""
+}
+
+// jsonField returns the value at path in v, or at the first of defaults that
+// holds one when path is empty.
+func jsonField(v any, path string, defaults []string) string {
+	if strings.TrimSpace(path) != "" {
+		return jsonPath(v, path)
+	}
+	for _, p := range defaults {
+		if s := jsonPath(v, p); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// jsonPath walks path through decoded JSON and returns the string or number
+// it ends at, trimmed; anything else, or a step that does not resolve, yields
+// "".
+func jsonPath(v any, path string) string {
+	for _, key := range jsonPathKeys(path) {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return ""
+			}
+			v = node[i]
+		default:
+			return ""
+		}
+	}
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return ""
+}
+
+// jsonAPIStrategy polls a station's own now-playing JSON API, reading the
+// fields wherever JSONFields say.
+type jsonAPIStrategy struct {
+	client *http.Client
+	logger Logger
+	limit  connLimiter
+	fields JSONFields
+	// zero values fall back to defaultPollInterval/defaultRequestTimeout
+	pollInterval   time.Duration
+	requestTimeout time.Duration
+}
+
+func newJSONAPIStrategy(client *http.Client, log Logger, limit connLimiter) *jsonAPIStrategy {
+	return &jsonAPIStrategy{client: client, logger: log, limit: limit}
+}
+
+// Watch mirrors statusJSONStrategy.Watch for the API at apiURL. The first
+// poll must resolve, or its error is returned so the caller can fall back to
+// discovery; later polls that fail or resolve nothing are logged once per
+// spell and leave the last title up.
+func (s *jsonAPIStrategy) Watch(ctx context.Context, apiURL string, onReady func(), onUpdate func(Info)) error {
+	if err := s.fields.Validate(); err != nil {
+		return err
+	}
+	info, err := s.pollOnce(ctx, apiURL)
+	if err != nil {
+		return err
+	}
+	if onReady != nil {
+		onReady()
+	}
+	onUpdate(info)
+	ticker := time.NewTicker(orDefault(s.pollInterval, defaultPollInterval))
+	defer ticker.Stop()
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			info, err := s.pollOnce(ctx, apiURL)
+			if err != nil {
+				if !failing && ctx.Err() == nil && s.logger != nil {
+					s.logger.Printf("json api %s: %v", apiURL, err)
+				}
+				failing = true
+				continue
+			}
+			failing = false
+			onUpdate(info)
+		}
+	}
+}
+
+// pollOnce fetches the API once and maps its answer.
+func (s *jsonAPIStrategy) pollOnce(ctx context.Context, apiURL string) (Info, error) {
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cctx, cancel := context.WithTimeout(ctx, orDefault(s.requestTimeout, defaultRequestTimeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(cctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return Info{}, err
+	}
+	req.Header.Set("User-Agent", defaultUA)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	release, err := s.limit.hold(cctx)
+	if err != nil {
+		return Info{}, err
+	}
+	defer release()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Info{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Info{}, fmt.Errorf("status %s", resp.Status)
+	}
+	body, err := decodedBody(resp)
+	if err != nil {
+		return Info{}, err
+	}
+	b, err := io.ReadAll(io.LimitReader(body, 1<<20))
+	if err != nil {
+		return Info{}, err
+	}
+	if !json.Valid(b) {
+		return Info{}, errors.New("response is not JSON")
+	}
+	info, ok := s.fields.info(b)
+	if !ok {
+		return Info{}, errNoJSONFields
+	}
+	return info, nil
+}
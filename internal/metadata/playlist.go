@@ -0,0 +1,196 @@
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxPlaylistDepth bounds playlists that point at further playlists.
+const maxPlaylistDepth = 3
+
+var (
+	errEmptyPlaylist = errors.New("playlist has no entries")
+	errNotPlaylist   = errors.New("not a PLS or M3U playlist")
+	errHLSPlaylist   = errors.New("HLS playlists list segments, not a stream; use the playlist's URL instead")
+)
+
+// ResolvePlaylist turns a PLS or M3U playlist URL into the stream it lists.
+// The target is recognised by its Content-Type (audio/x-mpegurl,
+// audio/x-scpls, …) or by a body starting with "[playlist]" or "#EXTM3U".
+// Anything else — including HLS playlists, which players handle natively —
+// is returned unchanged, so it is safe to call on every stream URL.
+func ResolvePlaylist(ctx context.Context, client *http.Client, rawURL string) (string, error) {
+	if client == nil {
+		client = newHTTPClient(0, http.ProxyFromEnvironment)
+	}
+	current := strings.TrimSpace(rawURL)
+	for depth := 0; depth < maxPlaylistDepth; depth++ {
+		next, ok, err := resolvePlaylistOnce(ctx, client, current)
+		if err != nil {
+			return rawURL, err
+		}
+		if !ok {
+			return current, nil
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// ParsePlaylist returns the first entry of a PLS or M3U playlist read from
+// disk. name is the file name, used like a URL path to recognise playlists
+// without a header. Entries are returned as written; HLS playlists are
+// rejected since they list media segments.
+func ParsePlaylist(name string, body []byte) (string, error) {
+	kind := playlistKind("", name, body)
+	if kind == "" {
+		return "", errNotPlaylist
+	}
+	if isHLSPlaylist(body) {
+		return "", errHLSPlaylist
+	}
+	var entry string
+	if kind == "pls" {
+		entry = firstPLSEntry(string(body))
+	} else {
+		entry = firstM3UEntry(string(body))
+	}
+	if entry == "" {
+		return "", errEmptyPlaylist
+	}
+	return entry, nil
+}
+
+// resolvePlaylistOnce fetches target and reports the first entry when it is a
+// playlist. ok is false when target is a plain stream.
+func resolvePlaylistOnce(ctx context.Context, client *http.Client, target string) (string, bool, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(cctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("User-Agent", defaultUA)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// let the player report the failure against the original URL
+		return "", false, nil
+	}
+
+	br := bufio.NewReader(io.LimitReader(resp.Body, 64<<10))
+	head, _ := br.Peek(16)
+	kind := playlistKind(resp.Header.Get("Content-Type"), resp.Request.URL.Path, head)
+	if kind == "" {
+		return "", false, nil
+	}
+	body, err := io.ReadAll(br)
+	if err != nil {
+		return "", false, err
+	}
+	if isHLSPlaylist(body) {
+		return "", false, nil
+	}
+	var entry string
+	if kind == "pls" {
+		entry = firstPLSEntry(string(body))
+	} else {
+		entry = firstM3UEntry(string(body))
+	}
+	if entry == "" {
+		return "", false, errEmptyPlaylist
+	}
+	base := resp.Request.URL
+	ref, err := url.Parse(entry)
+	if err != nil {
+		return "", false, err
+	}
+	return base.ResolveReference(ref).String(), true, nil
+}
+
+// playlistKind classifies a response as "pls", "m3u", or "" (not a playlist).
+// The URL extension is only trusted for textual bodies, since some servers
+// answer ".m3u" links with audio directly.
+func playlistKind(contentType, urlPath string, head []byte) string {
+	ct := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	switch ct {
+	case "audio/x-scpls", "application/pls+xml":
+		return "pls"
+	case "audio/x-mpegurl", "audio/mpegurl", "application/x-mpegurl", "application/vnd.apple.mpegurl":
+		return "m3u"
+	}
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	switch {
+	case len(trimmed) >= 10 && strings.EqualFold(string(trimmed[:10]), "[playlist]"):
+		return "pls"
+	case bytes.HasPrefix(trimmed, []byte("#EXTM3U")):
+		return "m3u"
+	case len(trimmed) == 0 || bytes.IndexByte(trimmed, 0) >= 0 || strings.HasPrefix(ct, "audio/mpeg") || strings.HasPrefix(ct, "audio/aac"):
+		return ""
+	}
+	switch strings.ToLower(path.Ext(urlPath)) {
+	case ".pls":
+		return "pls"
+	case ".m3u":
+		return "m3u"
+	}
+	return ""
+}
+
+// isHLSPlaylist reports whether an M3U body uses HLS tags.
+func isHLSPlaylist(body []byte) bool {
+	return bytes.Contains(body, []byte("#EXT-X-"))
+}
+
+// firstPLSEntry returns the lowest-numbered FileN= value of a PLS body.
+func firstPLSEntry(body string) string {
+	best, bestN := "", -1
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		key, val, ok := strings.Cut(line, "=")
+		if !ok || len(key) < 5 || !strings.EqualFold(key[:4], "file") {
+			continue
+		}
+		n := 0
+		for _, c := range key[4:] {
+			if c < '0' || c > '9' {
+				n = -1
+				break
+			}
+			n = n*10 + int(c-'0')
+		}
+		val = strings.TrimSpace(val)
+		if n < 0 || val == "" {
+			continue
+		}
+		if bestN < 0 || n < bestN {
+			best, bestN = val, n
+		}
+	}
+	return best
+}
+
+// firstM3UEntry returns the first non-comment line of an M3U body.
+func firstM3UEntry(body string) string {
+	sc := bufio.NewScanner(strings.NewReader(strings.TrimPrefix(body, "\xef\xbb\xbf")))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
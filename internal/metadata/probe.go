@@ -0,0 +1,54 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ProbeResult is how a stream URL answered a single request.
+type ProbeResult struct {
+	FinalURL    string // URL that answered, after redirects
+	Redirected  bool   // FinalURL differs from the URL probed
+	Status      int    // HTTP status code of the answer
+	ContentType string
+	MetaInt     string // icy-metaint header; empty when no ICY metadata is offered
+	Station     string // icy-name header
+}
+
+// Probe requests rawURL once, asking for ICY metadata and following redirects
+// as ResolveFinalURL does, and reports how the server answered. Only the
+// headers are read; the body is closed as soon as they arrive, so probing a
+// live stream never plays or buffers any of it. ctx bounds the whole probe.
+func Probe(ctx context.Context, client *http.Client, rawURL string) (ProbeResult, error) {
+	if client == nil {
+		client = newHTTPClient(0, http.ProxyFromEnvironment)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(rawURL), nil)
+	if err != nil {
+		return ProbeResult{}, err
+	}
+	req.Header.Set("Icy-MetaData", "1")
+	req.Header.Set("User-Agent", defaultUA)
+	cli := *client
+	cli.CheckRedirect = func(_ *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return errTooManyRedirects
+		}
+		return nil
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return ProbeResult{}, err
+	}
+	resp.Body.Close()
+	final := resp.Request.URL.String()
+	return ProbeResult{
+		FinalURL:    final,
+		Redirected:  final != req.URL.String(),
+		Status:      resp.StatusCode,
+		ContentType: strings.TrimSpace(resp.Header.Get("Content-Type")),
+		MetaInt:     strings.TrimSpace(resp.Header.Get("icy-metaint")),
+		Station:     strings.TrimSpace(resp.Header.Get("icy-name")),
+	}, nil
+}
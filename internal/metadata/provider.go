@@ -4,16 +4,36 @@ package metadata
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
-// Info describes metadata updates reported by strategies.
+// Info describes metadata updates reported by strategies. The zero Info is a
+// keep-alive: the source still answers but has nothing new, as an ICY stream
+// whose metadata blocks stay empty while the title is unchanged.
 type Info struct {
 	Title       string
 	Description string
 	Station     string
+	// ArtworkURL is an optional absolute http(s) link to cover art; empty
+	// when the source does not provide one.
+	ArtworkURL string
+	// Bitrate (kbps) and Genre come from the icy-br / icy-genre response
+	// headers or the status-json source; zero values mean the server did not
+	// send them.
+	Bitrate int
+	Genre   string
+	// Listeners and ListenURL are reported by Icecast status-json only: the
+	// mount's current listener count and its public URL.
+	Listeners int
+	ListenURL string
+	// Raw is the ICY metadata block a direct ICY title was parsed from,
+	// without its NUL padding, for diagnostics; empty for other sources.
+	Raw string
 }
 
 const (
@@ -21,6 +41,18 @@ const (
 	MetadataTypeICY = "ICY"
 	// MetadataTypeJSON indicates metadata received via a JSON status endpoint.
 	MetadataTypeJSON = "JSON"
+	// MetadataTypeShoutcast indicates metadata polled from Shoutcast v1/v2
+	// stats pages ("/7.html" or "/stats?sid=1").
+	MetadataTypeShoutcast = "SHOUTCAST"
+	// MetadataTypeHLS indicates ID3 tags embedded in HLS media segments.
+	MetadataTypeHLS = "HLS"
+	// MetadataTypeAPI indicates a station's own now-playing JSON API, read
+	// through JSONFields. MetadataTypeWS and MetadataTypeSSE indicate JSON
+	// messages pushed over a WebSocket or as Server-Sent Events. These are
+	// never discovered: a preset names them together with the source URL.
+	MetadataTypeAPI = "API"
+	MetadataTypeWS  = "WS"
+	MetadataTypeSSE = "SSE"
 )
 
 // StrategyHint allows callers to provide or receive the chosen metadata strategy.
@@ -32,6 +64,10 @@ type StrategyHint struct {
 // Provider watches metadata for a stream and emits updates through onUpdate.
 type Provider interface {
 	Watch(ctx context.Context, streamURL string, hint StrategyHint, onUpdate func(Info), onStrategy func(StrategyHint))
+	// Run is the blocking form of Watch: it returns once every applicable
+	// strategy has given up or ctx is done, for callers such as command-line
+	// tools that need to know when watching ended.
+	Run(ctx context.Context, streamURL string, hint StrategyHint, onUpdate func(Info), onStrategy func(StrategyHint))
 }
 
 // Logger is a small logging interface used by strategies for non-fatal errors.
@@ -39,58 +75,342 @@ type Logger interface {
 	Printf(format string, args ...any)
 }
 
+// DefaultMaxConnections is the number of simultaneous metadata connections a
+// provider opens when ProviderOptions.MaxConnections is unset.
+const DefaultMaxConnections = 4
+
+// ProviderOptions tunes a Provider. Zero values select the defaults.
+type ProviderOptions struct {
+	// MaxConnections caps concurrent outbound metadata requests shared by all
+	// strategies of one provider, so a single host is never hammered. A
+	// long-lived ICY, SSE or WebSocket stream counts only until it is
+	// connected.
+	MaxConnections int
+	// PollInterval is how often status-json and Shoutcast stats are refreshed
+	// (default 10s).
+	PollInterval time.Duration
+	// RequestTimeout bounds a single status-json or Shoutcast stats request
+	// (default 2s).
+	RequestTimeout time.Duration
+	// DialTimeout bounds TCP connect and TLS handshake for metadata
+	// connections, including the direct ICY socket (default 7s).
+	DialTimeout time.Duration
+	// Proxy routes metadata requests: ProxySystem (or "") follows the
+	// HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment, ProxyNone connects
+	// directly, and any other value is a proxy URL such as
+	// "http://proxy.corp:3128". An invalid URL is logged and treated as
+	// ProxySystem.
+	Proxy string
+	// UserAgent and Referer replace the headers sent with every metadata
+	// request ("" = the package's generic desktop agent and no referer).
+	UserAgent string
+	Referer   string
+	// MinTLS is the lowest TLS version metadata connections accept, such as
+	// tls.VersionTLS12 (0 = TLS 1.0, which old Icecast servers still need).
+	MinTLS uint16
+	// RequireTLS refuses plain-HTTP metadata requests with ErrInsecure,
+	// including redirects from https to http.
+	RequireTLS bool
+	// VerifyConnection, when set, runs after the usual certificate checks of
+	// every metadata TLS connection; an error fails it with ErrCertRejected.
+	// PinSHA256 builds one that pins a station's certificate.
+	VerifyConnection func(tls.ConnectionState) error
+	// OnSecurityError receives each request refused by RequireTLS or
+	// VerifyConnection, so the user can be told why metadata is missing. It
+	// may be called from any goroutine.
+	OnSecurityError func(error)
+	// IPVersion connects over IPv4 only (4) or IPv6 only (6), for
+	// dual-stack networks where a station is unreachable or slow over one of
+	// them; 0 lets the system choose. With a proxy it applies to the proxy.
+	IPVersion int
+	// SiblingOrder is the bitrate order sibling mounts are probed in when
+	// the stream itself has no ICY metadata: SiblingHighest (or "") first
+	// tries the highest, SiblingLowest the lowest, for metered connections,
+	// and SiblingMatch the closest at or below the stream's own bitrate.
+	SiblingOrder string
+	// JSONFields locate the title, artist, station and artwork in the JSON
+	// of a MetadataTypeAPI, MetadataTypeWS or MetadataTypeSSE source.
+	JSONFields JSONFields
+	// DiscoveryTimeout bounds the search for a stream's metadata source
+	// when no hint names one (default 15s, negative = no limit). A source
+	// found in time keeps being watched; otherwise probing stops until the
+	// stream is watched again.
+	DiscoveryTimeout time.Duration
+	// OnUnavailable is called with the stream URL when discovery ends
+	// without finding a metadata source, because every strategy failed or
+	// DiscoveryTimeout ran out, so a neutral text can replace "waiting for
+	// a title". It may be called from any goroutine.
+	OnUnavailable func(streamURL string)
+	// OnICYHeaders receives the response headers of every direct ICY
+	// connection that serves metadata, for diagnostics. It is called from the
+	// watching goroutine.
+	OnICYHeaders func(streamURL string, h http.Header)
+	// OnICYBlock receives every metadata block a direct ICY connection
+	// reads, after any update it carries: raw is the block text, "" for the
+	// empty blocks servers send while the title is unchanged. It is called
+	// from the watching goroutine.
+	OnICYBlock func(streamURL, raw string)
+}
+
+const (
+	// SiblingHighest, SiblingLowest and SiblingMatch are the
+	// ProviderOptions.SiblingOrder values.
+	SiblingHighest = "highest"
+	SiblingLowest  = "lowest"
+	SiblingMatch   = "match"
+)
+
+const (
+	// ProxySystem selects the proxy from the environment.
+	ProxySystem = "system"
+	// ProxyNone disables proxying for metadata requests.
+	ProxyNone = "none"
+)
+
+// withDefaults fills zero fields with the package defaults.
+func (o ProviderOptions) withDefaults() ProviderOptions {
+	if o.MaxConnections <= 0 {
+		o.MaxConnections = DefaultMaxConnections
+	}
+	o.PollInterval = orDefault(o.PollInterval, defaultPollInterval)
+	o.RequestTimeout = orDefault(o.RequestTimeout, defaultRequestTimeout)
+	o.DialTimeout = orDefault(o.DialTimeout, defaultDialTimeout)
+	if o.DiscoveryTimeout == 0 {
+		o.DiscoveryTimeout = defaultDiscoveryTimeout
+	}
+	return o
+}
+
+// NewHTTPClient returns a client configured like the provider's default one,
+// honoring opts.DialTimeout, opts.Proxy, the TLS settings and the header
+// overrides, for callers that fetch stream URLs outside a Provider (playlist
+// and redirect resolution).
+func NewHTTPClient(opts ProviderOptions) *http.Client {
+	opts = opts.withDefaults()
+	proxy, _ := parseProxy(opts.Proxy)
+	client := withIPVersion(newHTTPClient(opts.DialTimeout, proxy), opts.IPVersion)
+	return withHeaders(withSecurity(client, opts), opts.UserAgent, opts.Referer)
+}
+
 // NewProvider builds the root dispatcher that tries strategies in order.
-func NewProvider(client *http.Client, log Logger) Provider {
+// When client is nil, every strategy uses a default client honoring opts.
+func NewProvider(client *http.Client, log Logger, opts ProviderOptions) Provider {
+	opts = opts.withDefaults()
+	proxy, err := parseProxy(opts.Proxy)
+	if err != nil && log != nil {
+		log.Printf("metadata: %v; using system proxy settings", err)
+	}
 	if client == nil {
-		client = http.DefaultClient
+		client = newHTTPClient(opts.DialTimeout, proxy)
 	}
+	client = withIPVersion(client, opts.IPVersion)
+	client = withHeaders(withSecurity(client, opts), opts.UserAgent, opts.Referer)
+	limit := newConnLimiter(opts.MaxConnections)
+	direct := newDirectStrategy(client, log, limit)
+	direct.dialTimeout, direct.proxy = opts.DialTimeout, proxy
+	direct.onHeaders, direct.onBlock = opts.OnICYHeaders, opts.OnICYBlock
+	status := newStatusJSONStrategy(client, log, limit)
+	status.pollInterval, status.requestTimeout = opts.PollInterval, opts.RequestTimeout
+	shoutcast := newShoutcastStrategy(client, log, limit)
+	shoutcast.pollInterval, shoutcast.requestTimeout = opts.PollInterval, opts.RequestTimeout
+	sibling := newSiblingStrategy(client, log, limit)
+	sibling.proxy, sibling.order = proxy, opts.SiblingOrder
+	push := newPushStrategy(client, log, limit)
+	push.fields = opts.JSONFields
+	api := newJSONAPIStrategy(client, log, limit)
+	api.fields = opts.JSONFields
+	api.pollInterval, api.requestTimeout = opts.PollInterval, opts.RequestTimeout
 	return &dispatcher{
-		client:  client,
-		logger:  log,
-		direct:  newDirectStrategy(client, log),
-		status:  newStatusJSONStrategy(client, log),
-		sibling: newSiblingStrategy(client, log),
+		client:    client,
+		logger:    log,
+		limit:     limit,
+		direct:    direct,
+		status:    status,
+		sibling:   sibling,
+		shoutcast: shoutcast,
+		hls:       newHLSStrategy(client, log, limit),
+		push:      push,
+		api:       api,
+
+		discoveryTimeout: opts.DiscoveryTimeout,
+		onUnavailable:    opts.OnUnavailable,
 	}
 }
 
 var errNoICY = errors.New("icy metadata unavailable")
 
-// dispatcher implements Provider by chaining direct ICY, sibling discovery, and
-// JSON status strategies until one produces data.
+// dispatcher implements Provider by chaining HLS ID3, direct ICY, sibling
+// discovery, Shoutcast stats, and JSON status strategies until one produces
+// data. Station APIs, WebSocket and SSE sources are used only when the hint
+// names them.
 type dispatcher struct {
-	client  *http.Client
-	logger  Logger
-	direct  *directStrategy
-	status  *statusJSONStrategy
-	sibling *siblingStrategy
+	client    *http.Client
+	logger    Logger
+	limit     connLimiter
+	direct    *directStrategy
+	status    *statusJSONStrategy
+	sibling   *siblingStrategy
+	shoutcast *shoutcastStrategy
+	hls       *hlsStrategy
+	push      *pushStrategy
+	api       *jsonAPIStrategy
+
+	// discoveryTimeout and onUnavailable are ProviderOptions.DiscoveryTimeout
+	// and ProviderOptions.OnUnavailable, see autoWatch
+	discoveryTimeout time.Duration
+	onUnavailable    func(streamURL string)
 }
 
 func (d *dispatcher) Watch(ctx context.Context, streamURL string, hint StrategyHint, onUpdate func(Info), onStrategy func(StrategyHint)) {
+	if ctx == nil || streamURL == "" || onUpdate == nil {
+		return
+	}
+	go d.Run(ctx, streamURL, hint, onUpdate, onStrategy)
+}
+
+func (d *dispatcher) Run(ctx context.Context, streamURL string, hint StrategyHint, onUpdate func(Info), onStrategy func(StrategyHint)) {
 	if ctx == nil || streamURL == "" || onUpdate == nil {
 		return
 	}
 	hType := strings.ToUpper(strings.TrimSpace(hint.Type))
 	switch hType {
 	case MetadataTypeJSON:
+		d.runStatus(ctx, streamURL, hint.URL, onUpdate, onStrategy)
+	case MetadataTypeShoutcast:
+		d.runShoutcast(ctx, streamURL, hint.URL, onUpdate, onStrategy)
+	case MetadataTypeAPI:
+		if hint.URL == "" {
+			if d.logger != nil {
+				d.logger.Printf("metadata hint %s has no URL, discovering", hType)
+			}
+			d.autoWatch(ctx, streamURL, onUpdate, onStrategy)
+			return
+		}
+		if err := d.api.Watch(ctx, hint.URL, func() {
+			if onStrategy != nil {
+				onStrategy(StrategyHint{Type: hType, URL: hint.URL})
+			}
+		}, onUpdate); err != nil && ctx.Err() == nil {
+			if d.logger != nil {
+				d.logger.Printf("json api %s: %v; discovering", hint.URL, err)
+			}
+			d.autoWatch(ctx, streamURL, onUpdate, onStrategy)
+		}
+	case MetadataTypeWS, MetadataTypeSSE:
+		if hint.URL == "" {
+			if d.logger != nil {
+				d.logger.Printf("metadata hint %s has no URL, discovering", hType)
+			}
+			d.autoWatch(ctx, streamURL, onUpdate, onStrategy)
+			return
+		}
+		_ = d.push.Watch(ctx, hType, hint.URL, func() {
+			if onStrategy != nil {
+				onStrategy(StrategyHint{Type: hType, URL: hint.URL})
+			}
+		}, onUpdate)
+	case MetadataTypeHLS:
 		target := hint.URL
-		go d.runStatus(ctx, streamURL, target, onUpdate, onStrategy)
-		return
+		if target == "" {
+			target = streamURL
+		}
+		d.runHLS(ctx, target, onUpdate, onStrategy)
 	case MetadataTypeICY:
 		target := hint.URL
 		if target == "" {
 			target = streamURL
 		}
-		go d.runDirect(ctx, target, onUpdate, onStrategy)
-		return
+		update := onUpdate
+		if target != streamURL {
+			// a remembered sibling mount
+			update = withoutBitrate(onUpdate)
+		}
+		// hints are remembered across restarts, so the mount may have lost
+		// its metadata since; rediscover rather than stay silent
+		if err := d.direct.Watch(ctx, target, func() {
+			if onStrategy != nil {
+				onStrategy(StrategyHint{Type: MetadataTypeICY, URL: target})
+			}
+		}, update); errors.Is(err, errNoICY) && ctx.Err() == nil {
+			if d.logger != nil {
+				d.logger.Printf("metadata hint %s no longer serves ICY, rediscovering", target)
+			}
+			d.autoWatch(ctx, streamURL, onUpdate, onStrategy)
+		}
+	default:
+		d.autoWatch(ctx, streamURL, onUpdate, onStrategy)
 	}
-	go d.autoWatch(ctx, streamURL, onUpdate, onStrategy)
 }
 
-// autoWatch tries strategies in the following order:
-//   1. Direct ICY metadata on the stream URL.
-//   2. Sibling discovery (common patterns on aggregator hosts).
-//   3. JSON status endpoints.
+// autoWatch runs discover within d.discoveryTimeout: once a strategy reports
+// a source or an update the deadline no longer applies, but if none has by
+// then every probe is cancelled. Either way d.onUnavailable hears about a
+// stream for which nothing was found, unless ctx ended first.
 func (d *dispatcher) autoWatch(ctx context.Context, streamURL string, onUpdate func(Info), onStrategy func(StrategyHint)) {
+	dctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu             sync.Mutex
+		found, expired bool
+	)
+	// mark records that discovery succeeded, unless it already timed out
+	mark := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if !expired {
+			found = true
+		}
+		return !expired
+	}
+	if d.discoveryTimeout > 0 {
+		timer := time.AfterFunc(d.discoveryTimeout, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if !found {
+				expired = true
+				cancel()
+			}
+		})
+		defer timer.Stop()
+	}
+	d.discover(dctx, streamURL, func(info Info) {
+		if mark() {
+			onUpdate(info)
+		}
+	}, func(sel StrategyHint) {
+		if mark() && onStrategy != nil {
+			onStrategy(sel)
+		}
+	})
+	mu.Lock()
+	unavailable := !found
+	mu.Unlock()
+	if unavailable && ctx.Err() == nil {
+		if d.logger != nil {
+			d.logger.Printf("no metadata source found for %s", streamURL)
+		}
+		if d.onUnavailable != nil {
+			d.onUnavailable(streamURL)
+		}
+	}
+}
+
+// discover tries strategies in the following order:
+//  0. ID3 tags in HLS segments (only for ".m3u8" stream URLs).
+//  1. Direct ICY metadata on the stream URL.
+//  2. Sibling discovery (common patterns on aggregator hosts).
+//  3. Shoutcast v1/v2 stats pages.
+//  4. JSON status endpoints.
+func (d *dispatcher) discover(ctx context.Context, streamURL string, onUpdate func(Info), onStrategy func(StrategyHint)) {
+	if isHLSURL(streamURL) {
+		if err := d.hls.Watch(ctx, streamURL, func() {
+			if onStrategy != nil {
+				onStrategy(StrategyHint{Type: MetadataTypeHLS, URL: streamURL})
+			}
+		}, onUpdate); err == nil || ctx.Err() != nil {
+			return
+		}
+	}
 	if err := d.direct.Watch(ctx, streamURL, func() {
 		if onStrategy != nil {
 			onStrategy(StrategyHint{Type: MetadataTypeICY, URL: streamURL})
@@ -102,12 +422,20 @@ func (d *dispatcher) autoWatch(ctx context.Context, streamURL string, onUpdate f
 		if onStrategy != nil {
 			onStrategy(StrategyHint{Type: MetadataTypeICY, URL: target})
 		}
+		onUpdate = withoutBitrate(onUpdate)
 		if info.Title != "" || info.Station != "" {
 			onUpdate(info)
 		}
 		d.direct.Watch(ctx, target, nil, onUpdate)
 		return
 	}
+	if err := d.shoutcast.Watch(ctx, streamURL, "", func(api string) {
+		if onStrategy != nil {
+			onStrategy(StrategyHint{Type: MetadataTypeShoutcast, URL: api})
+		}
+	}, onUpdate); err == nil || ctx.Err() != nil {
+		return
+	}
 	_ = d.status.Watch(ctx, streamURL, "", func(api string) {
 		if onStrategy != nil {
 			onStrategy(StrategyHint{Type: MetadataTypeJSON, URL: api})
@@ -115,14 +443,13 @@ func (d *dispatcher) autoWatch(ctx context.Context, streamURL string, onUpdate f
 	}, onUpdate)
 }
 
-func (d *dispatcher) runDirect(ctx context.Context, url string, onUpdate func(Info), onStrategy func(StrategyHint)) {
-	// direct strategy invocation intentionally ignores returned error; autoWatch
-	// handles fallback ordering while hint-driven runs assume the caller knows best.
-	_ = d.direct.Watch(ctx, url, func() {
-		if onStrategy != nil {
-			onStrategy(StrategyHint{Type: MetadataTypeICY, URL: url})
-		}
-	}, onUpdate)
+// withoutBitrate drops the bitrate from updates read off a sibling mount,
+// which by definition differs from the stream being played.
+func withoutBitrate(onUpdate func(Info)) func(Info) {
+	return func(info Info) {
+		info.Bitrate = 0
+		onUpdate(info)
+	}
 }
 
 func (d *dispatcher) runStatus(ctx context.Context, streamURL, apiURL string, onUpdate func(Info), onStrategy func(StrategyHint)) {
@@ -133,3 +460,20 @@ func (d *dispatcher) runStatus(ctx context.Context, streamURL, apiURL string, on
 		}
 	}, onUpdate)
 }
+
+func (d *dispatcher) runShoutcast(ctx context.Context, streamURL, apiURL string, onUpdate func(Info), onStrategy func(StrategyHint)) {
+	// like runStatus, the strategy resolves the endpoint itself when apiURL is empty.
+	_ = d.shoutcast.Watch(ctx, streamURL, apiURL, func(actual string) {
+		if onStrategy != nil {
+			onStrategy(StrategyHint{Type: MetadataTypeShoutcast, URL: actual})
+		}
+	}, onUpdate)
+}
+
+func (d *dispatcher) runHLS(ctx context.Context, playlistURL string, onUpdate func(Info), onStrategy func(StrategyHint)) {
+	_ = d.hls.Watch(ctx, playlistURL, func() {
+		if onStrategy != nil {
+			onStrategy(StrategyHint{Type: MetadataTypeHLS, URL: playlistURL})
+		}
+	}, onUpdate)
+}
@@ -2,11 +2,19 @@ package metadata
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -30,7 +38,7 @@ func TestStatusJSONSuccess(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	strat := newStatusJSONStrategy(srv.Client(), testLogger{})
+	strat := newStatusJSONStrategy(srv.Client(), testLogger{}, nil)
 	got := make(chan Info, 1)
 	go func() {
 		_ = strat.Watch(ctx, srv.URL+"/stream", "", func(string) {}, func(info Info) {
@@ -49,13 +57,121 @@ func TestStatusJSONSuccess(t *testing.T) {
 	}
 }
 
+func TestStatusJSONStationDetails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("v") {
+		case "rich":
+			io.WriteString(w, `{"icestats":{"source":{"title":"Foo - Bar","server_name":"Station",`+
+				`"genre":" Jazz ","bitrate":128,"listeners":42,"listenurl":"http://radio.example:8000/jazz"}}}`)
+		case "strings":
+			io.WriteString(w, `{"icestats":{"source":{"title":"Foo - Bar","bitrate":"192,192","listeners":"7"}}}`)
+		default:
+			io.WriteString(w, `{"icestats":{"source":{"title":"Foo - Bar","bitrate":"n/a","listeners":null,"listenurl":"not a url"}}}`)
+		}
+	}))
+	defer srv.Close()
+
+	strat := newStatusJSONStrategy(srv.Client(), testLogger{}, nil)
+	tests := []struct {
+		v    string
+		want Info
+	}{
+		{"rich", Info{Title: "Foo - Bar", Station: "Station", Genre: "Jazz", Bitrate: 128, Listeners: 42, ListenURL: "http://radio.example:8000/jazz"}},
+		{"strings", Info{Title: "Foo - Bar", Bitrate: 192, Listeners: 7}},
+		// odd values are dropped, never the source
+		{"junk", Info{Title: "Foo - Bar"}},
+	}
+	for _, tt := range tests {
+		got, ok := strat.pollOnce(context.Background(), srv.URL+"/status-json.xsl?v="+tt.v, "")
+		if !ok || got != tt.want {
+			t.Fatalf("%s: pollOnce = %+v, %v; want %+v", tt.v, got, ok, tt.want)
+		}
+	}
+}
+
+func TestStatusJSONCompressed(t *testing.T) {
+	const doc = `{"icestats":{"source":{"title":"Zipped - Song","server_name":"Station"}}}`
+	tests := []struct {
+		name, encoding string
+		newWriter      func(io.Writer) io.WriteCloser
+	}{
+		{"gzip", "gzip", func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }},
+		{"zlib deflate", "deflate", func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }},
+		// some servers send a bare deflate stream for "deflate"
+		{"raw deflate", "deflate", func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Encoding", tt.encoding)
+				zw := tt.newWriter(w)
+				io.WriteString(zw, doc)
+				zw.Close()
+			}))
+			defer srv.Close()
+
+			strat := newStatusJSONStrategy(srv.Client(), testLogger{}, nil)
+			info, ok := strat.pollOnce(context.Background(), srv.URL+"/status-json.xsl", "")
+			if !ok || info.Title != "Zipped - Song" || info.Station != "Station" {
+				t.Fatalf("pollOnce = %+v, %v", info, ok)
+			}
+		})
+	}
+}
+
+func TestStatusJSONMatchesMount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "status-json.xsl") {
+			io.WriteString(w, `{"icestats":{"source":[`+
+				`{"title":"Jazz Song","server_name":"Jazz","listenurl":"http://radio.example:8000/jazz"},`+
+				`{"title":"Rock Song","server_name":"Rock","listenurl":"http://radio.example:8000/rock"}]}}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	strat := newStatusJSONStrategy(srv.Client(), testLogger{}, nil)
+	got := make(chan Info, 1)
+	go func() {
+		_ = strat.Watch(ctx, srv.URL+"/rock", "", nil, func(info Info) {
+			got <- info
+			cancel()
+		})
+	}()
+
+	select {
+	case info := <-got:
+		if info.Title != "Rock Song" || info.Station != "Rock" {
+			t.Fatalf("expected the /rock source, got %+v", info)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for metadata")
+	}
+
+	sources := []iceSource{{Title: "A", Mount: "/a"}, {Title: "B", Mount: "/b"}}
+	if src, _ := pickSource(sources, "/missing"); src.Title != "A" {
+		t.Fatalf("unmatched mount should fall back to the first source, got %q", src.Title)
+	}
+	sources = []iceSource{{Title: "A", Mount: "/a"}, {Mount: "/b"}}
+	if src, ok := pickSource(sources, "/b"); !ok || src.Mount != "/b" || src.Title != "" {
+		t.Fatalf("untitled matching mount should win over another channel, got %+v", src)
+	}
+}
+
 func TestStatusJSONNotFound(t *testing.T) {
 	srv := httptest.NewServer(http.NotFoundHandler())
 	defer srv.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
-	strat := newStatusJSONStrategy(srv.Client(), testLogger{})
+	strat := newStatusJSONStrategy(srv.Client(), testLogger{}, nil)
 	if err := strat.Watch(ctx, srv.URL+"/stream", "", func(string) {}, func(Info) {}); err == nil {
 		t.Fatal("expected error for missing status json")
 	}
@@ -68,12 +184,77 @@ func TestStatusJSONInvalidJSON(t *testing.T) {
 	defer srv.Close()
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
-	strat := newStatusJSONStrategy(srv.Client(), testLogger{})
+	strat := newStatusJSONStrategy(srv.Client(), testLogger{}, nil)
 	if err := strat.Watch(ctx, srv.URL+"/stream", "", func(string) {}, func(Info) {}); err == nil {
 		t.Fatal("expected error for invalid json")
 	}
 }
 
+func TestBuildSiblingCandidatesOrder(t *testing.T) {
+	names := []string{"/radio-stream", "/radio-live", "/radio-aac", "/radio-aacp", "/radio-mp3"}
+	with := func(paths ...string) []string { return append(paths, names...) }
+	for _, tt := range []struct {
+		path, order string
+		want        []string
+	}{
+		{"/radio-320k", "", with("/radio-320", "/radio-320k", "/radio-256", "/radio-256k",
+			"/radio-192", "/radio-192k", "/radio-128", "/radio-128k")},
+		{"/radio-320k", SiblingLowest, with("/radio-128", "/radio-128k", "/radio-192", "/radio-192k",
+			"/radio-256", "/radio-256k", "/radio-320", "/radio-320k")},
+		{"/radio-192k", SiblingMatch, with("/radio-192", "/radio-192k", "/radio-128", "/radio-128k")},
+		{"/radio-64kbps", SiblingMatch, with()},
+		// no bitrate to match: the default order
+		{"/radio-flac", SiblingMatch, with("/radio-320", "/radio-320k", "/radio-256", "/radio-256k",
+			"/radio-192", "/radio-192k", "/radio-128", "/radio-128k")},
+	} {
+		if got := buildSiblingCandidates(tt.path, tt.order); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s (%q):\n got %q\nwant %q", tt.path, tt.order, got, tt.want)
+		}
+	}
+	for in, want := range map[string]int{"128": 128, "96k": 96, "64KBPS": 64, "aac": 0, "aac64": 0, "k": 0} {
+		if got := suffixBitrate(in); got != want {
+			t.Errorf("suffixBitrate(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestSiblingProbesInParallelByPriority(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		switch r.URL.Path {
+		case "/rock-256":
+			// preferred, but slower than the 128k sibling
+			time.Sleep(300 * time.Millisecond)
+			fallthrough
+		case "/rock-128":
+			w.Header().Set("icy-metaint", "1")
+			w.Write(buildICYBody("Slow - Preferred"))
+		default:
+			time.Sleep(200 * time.Millisecond)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	s := newSiblingStrategy(srv.Client(), testLogger{}, newConnLimiter(DefaultMaxConnections))
+	start := time.Now()
+	target, _, err := s.scanCandidates(context.Background(), srv.URL+"/rock-flac")
+	if err != nil || target != srv.URL+"/rock-256" {
+		t.Fatalf("target %q, err %v; want the 256k sibling", target, err)
+	}
+	// one at a time, with the old 80ms gaps, this took over 800ms
+	if d := time.Since(start); d > 700*time.Millisecond {
+		t.Fatalf("scan took %s", d)
+	}
+	if p := peak.Load(); p > siblingParallel {
+		t.Fatalf("%d probes at once, want at most %d", p, siblingParallel)
+	}
+}
+
 func TestStrategyFallbackToSibling(t *testing.T) {
 	streamTitle := "Test Title"
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -91,7 +272,7 @@ func TestStrategyFallbackToSibling(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	prov := NewProvider(srv.Client(), testLogger{})
+	prov := NewProvider(srv.Client(), testLogger{}, ProviderOptions{})
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	strategyResolved := make(chan StrategyHint, 1)
@@ -122,6 +303,40 @@ func TestStrategyFallbackToSibling(t *testing.T) {
 	}
 }
 
+func TestStaleICYHintRediscovers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/live":
+			w.Header().Set("icy-metaint", "1")
+			w.Write(buildICYBody("Fresh Title"))
+		case "/old-mount":
+			// the remembered sibling stopped sending metadata
+			w.Write([]byte("audio without icy"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	prov := NewProvider(srv.Client(), testLogger{}, ProviderOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	strategyResolved := make(chan StrategyHint, 1)
+	prov.Watch(ctx, srv.URL+"/live", StrategyHint{Type: MetadataTypeICY, URL: srv.URL + "/old-mount"}, func(Info) {}, func(h StrategyHint) {
+		strategyResolved <- h
+		cancel()
+	})
+
+	select {
+	case hint := <-strategyResolved:
+		if hint.Type != MetadataTypeICY || hint.URL != srv.URL+"/live" {
+			t.Fatalf("unexpected hint: %+v", hint)
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("timeout waiting for strategy hint")
+	}
+}
+
 func TestStrategyFallbackJSON(t *testing.T) {
 	var statusHits atomic.Int32
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -137,7 +352,7 @@ func TestStrategyFallbackJSON(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	prov := NewProvider(srv.Client(), testLogger{})
+	prov := NewProvider(srv.Client(), testLogger{}, ProviderOptions{})
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	strategyResolved := make(chan StrategyHint, 1)
@@ -170,3 +385,1153 @@ func buildICYBody(title string) []byte {
 	buf.WriteString(meta)
 	return buf.Bytes()
 }
+
+func TestShoutcastStatsFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/7.html":
+			http.NotFound(w, r)
+		case "/stats":
+			if r.URL.Query().Get("sid") != "1" {
+				http.NotFound(w, r)
+				return
+			}
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><SHOUTCASTSERVER><SERVERTITLE>Shout FM</SERVERTITLE><SONGTITLE>Artist - Song</SONGTITLE></SHOUTCASTSERVER>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	strat := newShoutcastStrategy(srv.Client(), testLogger{}, nil)
+	ready := make(chan string, 1)
+	got := make(chan Info, 1)
+	go func() {
+		_ = strat.Watch(ctx, srv.URL+"/stream", "", func(api string) { ready <- api }, func(info Info) {
+			got <- info
+			cancel()
+		})
+	}()
+
+	select {
+	case info := <-got:
+		if info.Title != "Artist - Song" || info.Station != "Shout FM" {
+			t.Fatalf("unexpected info: %+v", info)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for metadata")
+	}
+	if api := <-ready; !strings.HasSuffix(api, "/stats?sid=1") {
+		t.Fatalf("unexpected endpoint %q", api)
+	}
+}
+
+func TestStrategyFallbackShoutcast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rock-flac":
+			w.Write([]byte("no icy"))
+		case "/7.html":
+			io.WriteString(w, `<html><body>12,1,40,100,10,128,Band, The - Song</body></html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	prov := NewProvider(srv.Client(), testLogger{}, ProviderOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	strategyResolved := make(chan StrategyHint, 1)
+	updates := make(chan Info, 1)
+	prov.Watch(ctx, srv.URL+"/rock-flac", StrategyHint{}, func(info Info) {
+		select {
+		case updates <- info:
+		default:
+		}
+	}, func(h StrategyHint) {
+		strategyResolved <- h
+	})
+
+	select {
+	case hint := <-strategyResolved:
+		if hint.Type != MetadataTypeShoutcast || !strings.HasSuffix(hint.URL, "/7.html") {
+			t.Fatalf("unexpected hint: %+v", hint)
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("timeout waiting for shoutcast strategy")
+	}
+	select {
+	case info := <-updates:
+		if info.Title != "Band, The - Song" {
+			t.Fatalf("unexpected title %q", info.Title)
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("timeout waiting for metadata update")
+	}
+}
+
+func TestStrategyHLSID3(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/live/master.m3u8":
+			io.WriteString(w, "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=128000\naudio/index.m3u8\n")
+		case "/live/audio/index.m3u8":
+			io.WriteString(w, "#EXTM3U\n#EXT-X-TARGETDURATION:4\n#EXTINF:4,\nseg1.aac\n#EXTINF:4,\nseg2.aac\n")
+		case "/live/audio/seg2.aac":
+			w.Write(append(buildID3Tag(3, "Artist", "Song"), 0xFF, 0xF1, 0x50))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	prov := NewProvider(srv.Client(), testLogger{}, ProviderOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	strategyResolved := make(chan StrategyHint, 1)
+	updates := make(chan Info, 1)
+	prov.Watch(ctx, srv.URL+"/live/master.m3u8", StrategyHint{}, func(info Info) {
+		select {
+		case updates <- info:
+		default:
+		}
+	}, func(h StrategyHint) {
+		strategyResolved <- h
+	})
+
+	select {
+	case hint := <-strategyResolved:
+		if hint.Type != MetadataTypeHLS {
+			t.Fatalf("unexpected hint: %+v", hint)
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("timeout waiting for hls strategy")
+	}
+	select {
+	case info := <-updates:
+		if info.Title != "Artist - Song" {
+			t.Fatalf("unexpected title %q", info.Title)
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("timeout waiting for metadata update")
+	}
+}
+
+func TestResolvePlaylist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/listen.pls":
+			w.Header().Set("Content-Type", "audio/x-scpls")
+			io.WriteString(w, "[playlist]\nFile1=/nested.m3u\n")
+		case "/nested.m3u":
+			io.WriteString(w, "#EXTM3U\n#EXTINF:-1,Rock\nstream/rock\n")
+		case "/hls.m3u8":
+			io.WriteString(w, "#EXTM3U\n#EXT-X-TARGETDURATION:6\nseg1.aac\n")
+		case "/stream/rock":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Write([]byte{0xFF, 0xFB, 0x90, 0x00})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	got, err := ResolvePlaylist(ctx, srv.Client(), srv.URL+"/listen.pls")
+	if err != nil || got != srv.URL+"/stream/rock" {
+		t.Fatalf("ResolvePlaylist(pls) = %q, %v", got, err)
+	}
+	for _, direct := range []string{"/stream/rock", "/hls.m3u8"} {
+		if got, err := ResolvePlaylist(ctx, srv.Client(), srv.URL+direct); err != nil || got != srv.URL+direct {
+			t.Fatalf("ResolvePlaylist(%s) = %q, %v; want unchanged", direct, got, err)
+		}
+	}
+}
+
+func TestParsePlaylist(t *testing.T) {
+	cases := []struct {
+		name, body, want string
+		wantErr          bool
+	}{
+		{"station.pls", "[playlist]\nNumberOfEntries=2\nFile2=http://b/\nFile1=http://a/live\n", "http://a/live", false},
+		{"station.m3u", "#EXTM3U\n#EXTINF:-1,Jazz\nhttp://jazz/stream\n", "http://jazz/stream", false},
+		{"plain.m3u", "\n# comment\nhttps://plain/stream.mp3\n", "https://plain/stream.mp3", false},
+		{"live.m3u8", "#EXTM3U\n#EXT-X-TARGETDURATION:6\nseg1.aac\n", "", true},
+		{"empty.pls", "[playlist]\nNumberOfEntries=0\n", "", true},
+		{"notes.txt", "http://not/a/playlist\n", "", true},
+	}
+	for _, c := range cases {
+		got, err := ParsePlaylist(c.name, []byte(c.body))
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("ParsePlaylist(%s) = %q, %v; want %q (error %v)", c.name, got, err, c.want, c.wantErr)
+		}
+	}
+}
+
+func TestResolveFinalURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/short":
+			http.Redirect(w, r, "/hop", http.StatusFound)
+		case "/hop":
+			http.Redirect(w, r, "/live/stream.mp3", http.StatusMovedPermanently)
+		case "/live/stream.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Write([]byte{0xFF, 0xFB, 0x90, 0x00})
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if got, err := ResolveFinalURL(ctx, srv.Client(), srv.URL+"/short"); err != nil || got != srv.URL+"/live/stream.mp3" {
+		t.Fatalf("ResolveFinalURL(short) = %q, %v", got, err)
+	}
+	if got, err := ResolveFinalURL(ctx, srv.Client(), srv.URL+"/live/stream.mp3"); err != nil || got != srv.URL+"/live/stream.mp3" {
+		t.Fatalf("ResolveFinalURL(direct) = %q, %v; want unchanged", got, err)
+	}
+	if got, err := ResolveFinalURL(ctx, srv.Client(), srv.URL+"/loop"); err == nil || got != srv.URL+"/loop" {
+		t.Fatalf("ResolveFinalURL(loop) = %q, %v; want original URL and error", got, err)
+	}
+}
+
+func TestProviderLimitsConcurrentConnections(t *testing.T) {
+	const limit = 2
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, `{"icestats":{"source":{"title":"A - B"}}}`)
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{MaxConnections: limit}).(*dispatcher)
+	done := make(chan bool)
+	for i := 0; i < 6; i++ {
+		go func() {
+			_, ok := d.status.pollOnce(context.Background(), srv.URL+"/status-json.xsl", "")
+			done <- ok
+		}()
+	}
+	for i := 0; i < 6; i++ {
+		if !<-done {
+			t.Fatal("expected poll to succeed")
+		}
+	}
+	if got := peak.Load(); got > limit {
+		t.Fatalf("peak concurrent connections = %d, want <= %d", got, limit)
+	}
+}
+
+func TestDirectICYFreesSlotWhileStreaming(t *testing.T) {
+	streaming := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("icy-metaint", "1")
+		w.Write(buildICYBody("Artist - Song"))
+		w.(http.Flusher).Flush()
+		close(streaming)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{MaxConnections: 1}).(*dispatcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		_ = d.direct.Watch(ctx, srv.URL+"/live", nil, func(Info) {})
+	}()
+	defer func() {
+		cancel()
+		<-watched
+	}()
+	<-streaming
+	hctx, hcancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer hcancel()
+	release, err := d.direct.limit.hold(hctx)
+	if err != nil {
+		t.Fatalf("a playing ICY stream still holds the only slot: %v", err)
+	}
+	release()
+}
+
+func TestConnLimiterHonorsCancellation(t *testing.T) {
+	l := newConnLimiter(1)
+	release, err := l.hold(context.Background())
+	if err != nil {
+		t.Fatalf("first hold: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := l.hold(ctx); err == nil {
+		t.Fatal("expected hold to fail once the context expires")
+	}
+	release()
+	release() // idempotent
+	if len(l) != 0 {
+		t.Fatalf("slots in use = %d after release, want 0", len(l))
+	}
+	if _, err := l.hold(context.Background()); err != nil {
+		t.Fatalf("hold after release: %v", err)
+	}
+}
+
+func TestProviderOptionsDefaults(t *testing.T) {
+	got := ProviderOptions{}.withDefaults()
+	want := ProviderOptions{
+		MaxConnections: DefaultMaxConnections,
+		PollInterval:   10 * time.Second,
+		RequestTimeout: 2 * time.Second,
+		DialTimeout:    7 * time.Second,
+
+		DiscoveryTimeout: 15 * time.Second,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("withDefaults() = %+v, want %+v", got, want)
+	}
+	custom := ProviderOptions{PollInterval: time.Second, DialTimeout: 20 * time.Second}.withDefaults()
+	if custom.PollInterval != time.Second || custom.DialTimeout != 20*time.Second || custom.RequestTimeout != 2*time.Second {
+		t.Fatalf("custom values not preserved: %+v", custom)
+	}
+}
+
+func TestStatusJSONCustomPollInterval(t *testing.T) {
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := polls.Add(1)
+		fmt.Fprintf(w, `{"icestats":{"source":{"title":"Song %d"}}}`, n)
+	}))
+	defer srv.Close()
+
+	prov := NewProvider(srv.Client(), testLogger{}, ProviderOptions{PollInterval: 50 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	updates := make(chan Info, 8)
+	prov.Watch(ctx, srv.URL+"/stream", StrategyHint{Type: MetadataTypeJSON}, func(info Info) {
+		select {
+		case updates <- info:
+		default:
+		}
+	}, nil)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-updates:
+		case <-time.After(time.Second):
+			t.Fatalf("expected a second poll well before the 10s default (got %d)", i)
+		}
+	}
+}
+
+func TestDirectICYReportsBitrateAndGenre(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("icy-metaint", "1")
+		w.Header().Set("icy-br", "128,128")
+		w.Header().Set("icy-genre", "Jazz")
+		w.Write(buildICYBody("Artist - Song"))
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{}).(*dispatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var got Info
+	_ = d.direct.Watch(ctx, srv.URL+"/live", nil, func(info Info) {
+		if info.Title != "" {
+			got = info
+			cancel()
+		}
+	})
+	if got.Title != "Artist - Song" {
+		t.Fatalf("unexpected title %q", got.Title)
+	}
+	if got.Bitrate != 128 || got.Genre != "Jazz" {
+		t.Fatalf("bitrate/genre = %d/%q, want 128/Jazz", got.Bitrate, got.Genre)
+	}
+}
+
+func TestDirectICYReportsRawBlockAndHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("icy-metaint", "1")
+		w.Header().Set("icy-name", "Station")
+		w.Write(buildICYBody("Artist - Song"))
+	}))
+	defer srv.Close()
+
+	var headers http.Header
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{
+		OnICYHeaders: func(_ string, h http.Header) { headers = h },
+	}).(*dispatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var got Info
+	_ = d.direct.Watch(ctx, srv.URL+"/live", nil, func(info Info) {
+		if info.Title != "" {
+			got = info
+			cancel()
+		}
+	})
+	if got.Raw != "StreamTitle='Artist - Song';" {
+		t.Fatalf("raw = %q", got.Raw)
+	}
+	if headers.Get("icy-name") != "Station" || headers.Get("icy-metaint") != "1" {
+		t.Fatalf("headers = %v", headers)
+	}
+}
+
+func TestDirectICYReportsEveryBlock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("icy-metaint", "1")
+		body := append(buildICYBody("Artist - Song"), 0, 0, 0, 0) // two empty blocks
+		w.Write(append(body, buildICYBody("Artist - Song")...))
+	}))
+	defer srv.Close()
+
+	var blocks []string
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{
+		OnICYBlock: func(_ string, raw string) {
+			if blocks = append(blocks, raw); len(blocks) == 4 {
+				cancel()
+			}
+		},
+	}).(*dispatcher)
+	_ = d.direct.Watch(ctx, srv.URL+"/live", nil, func(Info) {})
+	want := []string{"StreamTitle='Artist - Song';", "", "", "StreamTitle='Artist - Song';"}
+	if fmt.Sprint(blocks) != fmt.Sprint(want) {
+		t.Fatalf("blocks = %q, want %q", blocks, want)
+	}
+}
+
+func TestDirectICYReconnectsAfterDrop(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			// drop the first connection before any response
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Header().Set("icy-metaint", "1")
+		w.Write(buildICYBody("Back - Again"))
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{}).(*dispatcher)
+	d.direct.retryDelay = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var got string
+	_ = d.direct.Watch(ctx, srv.URL+"/live", nil, func(info Info) {
+		if info.Title != "" {
+			got = info.Title
+			cancel()
+		}
+	})
+	if got != "Back - Again" || hits.Load() != 2 {
+		t.Fatalf("title %q after %d connections", got, hits.Load())
+	}
+}
+
+func TestDirectICYReportsKeepAlives(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("icy-metaint", "1")
+		w.Write(buildICYBody("DJ - Two Hour Mix"))
+		// then only empty metadata blocks, as while a title is unchanged
+		for i := 0; i < 40; i++ {
+			w.Write([]byte{0, 0})
+			w.(http.Flusher).Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{}).(*dispatcher)
+	d.direct.keepAlive = 50 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var titles, keepAlives int
+	_ = d.direct.Watch(ctx, srv.URL+"/live", nil, func(info Info) {
+		switch {
+		case info.Title != "":
+			titles++
+		case info == Info{}:
+			if keepAlives++; keepAlives == 3 {
+				cancel()
+			}
+		}
+	})
+	if titles != 1 || keepAlives < 3 {
+		t.Fatalf("%d titles, %d keep-alives", titles, keepAlives)
+	}
+}
+
+func TestDirectICYFollowsRedirectChain(t *testing.T) {
+	var hops atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Icy-MetaData") != "1" {
+			t.Errorf("%s: request without Icy-MetaData", r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+		case "/c":
+			w.Header().Set("icy-metaint", "1")
+			w.Write(buildICYBody("At - Last"))
+			return
+		default:
+			// /loop redirects to itself
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+		}
+		hops.Add(1)
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{}).(*dispatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var got string
+	_ = d.direct.Watch(ctx, srv.URL+"/a", nil, func(info Info) {
+		if info.Title != "" {
+			got = info.Title
+			cancel()
+		}
+	})
+	if got != "At - Last" || hops.Load() != 2 {
+		t.Fatalf("title %q after %d redirects", got, hops.Load())
+	}
+
+	hops.Store(0)
+	err := d.direct.Watch(context.Background(), srv.URL+"/loop", nil, func(Info) {})
+	if !errors.Is(err, errNoICY) || hops.Load() != maxICYRedirects+1 {
+		t.Fatalf("loop: err %v after %d redirects", err, hops.Load())
+	}
+}
+
+func TestDirectICYGivesUpAfterRetries(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{}).(*dispatcher)
+	d.direct.retries, d.direct.retryDelay = 2, 10*time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := d.direct.Watch(ctx, srv.URL+"/live", nil, func(Info) {})
+	if !errors.Is(err, errNoICY) || hits.Load() != 3 {
+		t.Fatalf("err %v after %d connections, want errNoICY after 3", err, hits.Load())
+	}
+
+	// a stream without ICY is not retried
+	hits.Store(0)
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("audio"))
+	}))
+	defer plain.Close()
+	if err := d.direct.Watch(ctx, plain.URL, nil, func(Info) {}); !errors.Is(err, errNoICY) || hits.Load() != 1 {
+		t.Fatalf("plain stream: err %v after %d connections", err, hits.Load())
+	}
+}
+
+func TestParseProxy(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://stream.example/live", nil)
+	for _, spec := range []string{"", "system", " System "} {
+		if fn, err := parseProxy(spec); err != nil || fn == nil {
+			t.Fatalf("parseProxy(%q) = %v, %v; want environment proxy", spec, fn != nil, err)
+		}
+	}
+	if fn, err := parseProxy("none"); err != nil || fn != nil {
+		t.Fatalf("parseProxy(none) should connect directly, err %v", err)
+	}
+	fn, err := parseProxy("http://proxy.corp:3128")
+	if err != nil {
+		t.Fatalf("parseProxy(url): %v", err)
+	}
+	if u, _ := fn(req); u == nil || u.Host != "proxy.corp:3128" {
+		t.Fatalf("proxy for request = %v", u)
+	}
+	for _, bad := range []string{"proxy.corp:3128", "http://", "::"} {
+		if _, err := parseProxy(bad); err == nil {
+			t.Fatalf("parseProxy(%q) should fail", bad)
+		}
+	}
+}
+
+func TestProviderRoutesThroughProxy(t *testing.T) {
+	var viaProxy atomic.Bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host == "stream.invalid" {
+			viaProxy.Store(true)
+		}
+		w.Header().Set("icy-metaint", "1")
+		w.Write(buildICYBody("Proxied - Song"))
+	}))
+	defer proxy.Close()
+
+	d := NewProvider(nil, testLogger{}, ProviderOptions{Proxy: proxy.URL}).(*dispatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var got Info
+	_ = d.direct.Watch(ctx, "http://stream.invalid/live", nil, func(info Info) {
+		if info.Title != "" {
+			got = info
+			cancel()
+		}
+	})
+	if got.Title != "Proxied - Song" || !viaProxy.Load() {
+		t.Fatalf("title %q via proxy %v", got.Title, viaProxy.Load())
+	}
+}
+
+func TestRequireTLSRefusesDowngrade(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "audio")
+	}))
+	defer plain.Close()
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			http.Redirect(w, r, plain.URL+"/live", http.StatusFound)
+			return
+		}
+		io.WriteString(w, "audio")
+	}))
+	defer secure.Close()
+
+	var reported []error
+	d := NewProvider(secure.Client(), testLogger{}, ProviderOptions{
+		RequireTLS:      true,
+		OnSecurityError: func(err error) { reported = append(reported, err) },
+	}).(*dispatcher)
+	if resp, err := d.client.Get(secure.URL + "/live"); err != nil {
+		t.Fatalf("https request refused: %v", err)
+	} else {
+		resp.Body.Close()
+	}
+	for _, u := range []string{secure.URL + "/down", plain.URL + "/live"} {
+		if _, err := d.client.Get(u); !errors.Is(err, ErrInsecure) {
+			t.Fatalf("GET %s: err %v, want ErrInsecure", u, err)
+		}
+	}
+	if len(reported) != 2 {
+		t.Fatalf("reported %d refusals, want 2: %v", len(reported), reported)
+	}
+	// the ICY watcher gives up at once instead of retrying
+	start := time.Now()
+	if err := d.direct.Watch(context.Background(), plain.URL, nil, func(Info) {}); !errors.Is(err, ErrInsecure) || time.Since(start) > time.Second {
+		t.Fatalf("direct ICY: err %v after %s", err, time.Since(start))
+	}
+}
+
+func TestCertificatePinning(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	good := "sha256//" + base64.StdEncoding.EncodeToString(sum[:])
+	other := sha256.Sum256([]byte("another key"))
+	wrong := "sha256/" + base64.StdEncoding.EncodeToString(other[:])
+
+	get := func(pins ...string) error {
+		verify, err := PinSHA256(pins)
+		if err != nil {
+			t.Fatalf("PinSHA256(%q): %v", pins, err)
+		}
+		client := NewProvider(srv.Client(), testLogger{}, ProviderOptions{VerifyConnection: verify}).(*dispatcher).client
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	if err := get(wrong, good); err != nil {
+		t.Fatalf("matching pin refused: %v", err)
+	}
+	if err := get(wrong); !errors.Is(err, ErrCertRejected) {
+		t.Fatalf("wrong pin: err %v, want ErrCertRejected", err)
+	}
+	for _, bad := range [][]string{nil, {"md5//abc"}, {"sha256//not base64"}, {"sha256//" + base64.StdEncoding.EncodeToString([]byte("short"))}} {
+		if _, err := PinSHA256(bad); err == nil {
+			t.Fatalf("PinSHA256(%q) should fail", bad)
+		}
+	}
+}
+
+func TestMinTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		min uint16
+		ok  bool
+	}{{0, true}, {tls.VersionTLS12, true}, {tls.VersionTLS13, false}} {
+		client := NewProvider(srv.Client(), testLogger{}, ProviderOptions{MinTLS: tt.min}).(*dispatcher).client
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if (err == nil) != tt.ok {
+			t.Fatalf("MinTLS %x against a TLS 1.2 server: err %v", tt.min, err)
+		}
+	}
+}
+
+func TestIPVersionPinsAddressFamily(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	// httptest listens on 127.0.0.1, which only IPv4 can reach
+	for _, tt := range []struct {
+		v  int
+		ok bool
+	}{{0, true}, {4, true}, {6, false}} {
+		client := NewHTTPClient(ProviderOptions{IPVersion: tt.v, Proxy: ProxyNone})
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if (err == nil) != tt.ok {
+			t.Fatalf("IPVersion %d: err %v", tt.v, err)
+		}
+	}
+	if got := ipNetwork("tcp6", 4); got != "tcp6" {
+		t.Fatalf("explicit network changed to %q", got)
+	}
+}
+
+func TestProviderSendsConfiguredHeaders(t *testing.T) {
+	var ua, ref atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua.Store(r.Header.Get("User-Agent"))
+		ref.Store(r.Header.Get("Referer"))
+		w.Header().Set("icy-metaint", "1")
+		w.Write(buildICYBody("Artist - Song"))
+	}))
+	defer srv.Close()
+
+	opts := ProviderOptions{UserAgent: "Aggregator/2.0", Referer: "https://aggregator.example/"}
+	d := NewProvider(srv.Client(), testLogger{}, opts).(*dispatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_ = d.direct.Watch(ctx, srv.URL+"/live", nil, func(info Info) {
+		if info.Title != "" {
+			cancel()
+		}
+	})
+	if ua.Load() != "Aggregator/2.0" || ref.Load() != "https://aggregator.example/" {
+		t.Fatalf("headers = %v / %v", ua.Load(), ref.Load())
+	}
+
+	if _, err := ResolveFinalURL(context.Background(), NewHTTPClient(ProviderOptions{}), srv.URL+"/live"); err != nil {
+		t.Fatalf("ResolveFinalURL: %v", err)
+	}
+	if ua.Load() != defaultUA || ref.Load() != "" {
+		t.Fatalf("default headers = %v / %v", ua.Load(), ref.Load())
+	}
+}
+
+func TestRunReturnsWhenNothingFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	p := NewProvider(nil, testLogger{}, ProviderOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx, srv.URL+"/live", StrategyHint{}, func(Info) {}, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(8 * time.Second):
+		t.Fatal("Run kept going after every strategy failed")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("Run only ended because of the deadline")
+	}
+}
+
+func TestDiscoveryTimeout(t *testing.T) {
+	// every probe hangs until its client gives up
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	unavailable := make(chan string, 1)
+	p := NewProvider(nil, testLogger{}, ProviderOptions{
+		RequestTimeout:   5 * time.Second,
+		DiscoveryTimeout: 300 * time.Millisecond,
+		OnUnavailable:    func(u string) { unavailable <- u },
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	p.Run(ctx, srv.URL+"/live", StrategyHint{}, func(Info) {}, nil)
+	if took := time.Since(start); took > 2*time.Second {
+		t.Fatalf("discovery ran %s past a 300ms deadline", took)
+	}
+	select {
+	case u := <-unavailable:
+		if u != srv.URL+"/live" {
+			t.Fatalf("OnUnavailable(%q)", u)
+		}
+	default:
+		t.Fatal("OnUnavailable not called")
+	}
+}
+
+func TestDiscoveryTimeoutSparesFoundSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("icy-metaint", "1")
+		w.Write(buildICYBody("First - Song"))
+		w.(http.Flusher).Flush()
+		time.Sleep(400 * time.Millisecond)
+		w.Write(buildICYBody("Second - Song"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	var unavailable atomic.Bool
+	p := NewProvider(srv.Client(), testLogger{}, ProviderOptions{
+		DiscoveryTimeout: 200 * time.Millisecond,
+		OnUnavailable:    func(string) { unavailable.Store(true) },
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var got string
+	p.Run(ctx, srv.URL+"/live", StrategyHint{}, func(info Info) {
+		if info.Title == "Second - Song" {
+			got = info.Title
+			cancel()
+		}
+	}, nil)
+	if got == "" || unavailable.Load() {
+		t.Fatalf("title %q, unavailable %v: the deadline cut off a working source", got, unavailable.Load())
+	}
+}
+
+func TestFollowRedirectsReportsHops(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/cdn/c", http.StatusFound)
+		case "/cdn/c":
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	var hops []string
+	got, err := FollowRedirects(context.Background(), srv.Client(), srv.URL+"/a", 2, func(u string) { hops = append(hops, u) })
+	if err != nil || got != srv.URL+"/cdn/c" {
+		t.Fatalf("FollowRedirects = %q, %v", got, err)
+	}
+	if len(hops) != 2 || hops[0] != srv.URL+"/b" || hops[1] != srv.URL+"/cdn/c" {
+		t.Fatalf("hops = %v", hops)
+	}
+	if _, err := FollowRedirects(context.Background(), srv.Client(), srv.URL+"/a", 1, nil); err == nil {
+		t.Fatal("expected an error beyond the hop limit")
+	}
+}
+
+func TestEnricherLookup(t *testing.T) {
+	var searches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ws/2/recording":
+			atomic.AddInt32(&searches, 1)
+			if !strings.Contains(strings.ToLower(r.URL.Query().Get("query")), `artist:"daft punk"`) {
+				io.WriteString(w, `{"recordings":[]}`)
+				return
+			}
+			io.WriteString(w, `{"recordings":[
+				{"id":"rec","score":100,"title":"One More Time",
+				 "artist-credit":[{"name":"Daft Punk"}],
+				 "releases":[{"id":"r1","title":"Discovery"},{"id":"r2","title":"Discovery (Deluxe)"}]}]}`)
+		case "/release/r2/front-250":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	e := NewEnricher(ProviderOptions{Proxy: ProxyNone})
+	e.searchURL, e.coverURL, e.interval = srv.URL+"/ws/2/recording", srv.URL+"/release", 0
+	ctx := context.Background()
+
+	got, ok := e.Lookup(ctx, "daft punk - one more  time")
+	want := Track{Artist: "Daft Punk", Title: "One More Time", Album: "Discovery", ArtworkURL: srv.URL + "/release/r2/front-250"}
+	if !ok || got != want {
+		t.Fatalf("Lookup = %+v, %v; want %+v", got, ok, want)
+	}
+	if _, ok := e.Lookup(ctx, "Daft Punk - One More Time"); !ok || atomic.LoadInt32(&searches) != 1 {
+		t.Fatalf("normalised repeat should hit the cache; searches = %d", searches)
+	}
+	if _, ok := e.Lookup(ctx, "Unknown - Jingle"); ok {
+		t.Fatal("unmatched title reported a track")
+	}
+	if _, ok := e.Lookup(ctx, "Station ID"); ok || atomic.LoadInt32(&searches) != 2 {
+		t.Fatalf("titles without an artist must not be searched; searches = %d", searches)
+	}
+}
+
+func TestTitleFilter(t *testing.T) {
+	f, err := NewTitleFilter([]string{"AdBreak", "/^Advert/", "strip:Radio X -", "strip:/\\s*\\[[^]]*\\]$/", " "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"Artist - Song", "Artist - Song", true},
+		{"adbreak", "", false},
+		{"Advertisement", "", false},
+		{"Your Advert here", "Your Advert here", true},
+		{"RADIO X - Artist - Song", "Artist - Song", true},
+		{"Artist - Song [Radio Edit]", "Artist - Song", true},
+		{"Radio X - ", "", false},
+	}
+	for _, c := range cases {
+		if got, ok := f.Apply(c.in); got != c.want || ok != c.ok {
+			t.Errorf("Apply(%q) = %q, %v; want %q, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+	if f, err := NewTitleFilter([]string{"", "strip:"}); f != nil || err != nil {
+		t.Fatalf("blank patterns = %v, %v; want nil filter", f, err)
+	}
+	if got, ok := (*TitleFilter)(nil).Apply("As is"); got != "As is" || !ok {
+		t.Fatalf("nil filter changed the title: %q, %v", got, ok)
+	}
+	if _, err := NewTitleFilter([]string{"/(/"}); err == nil {
+		t.Fatal("expected an error for an invalid regular expression")
+	}
+}
+
+func TestProbeReportsHeadersAndRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/listen":
+			http.Redirect(w, r, "/live", http.StatusFound)
+		case "/live":
+			if r.Header.Get("Icy-MetaData") == "1" {
+				w.Header().Set("icy-metaint", "16000")
+			}
+			w.Header().Set("icy-name", "Probe FM")
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Write([]byte("audio"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	got, err := Probe(context.Background(), srv.Client(), srv.URL+"/listen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ProbeResult{FinalURL: srv.URL + "/live", Redirected: true, Status: 200, ContentType: "audio/mpeg", MetaInt: "16000", Station: "Probe FM"}
+	if got != want {
+		t.Fatalf("Probe = %+v, want %+v", got, want)
+	}
+	if got, err := Probe(context.Background(), srv.Client(), srv.URL+"/gone"); err != nil || got.Status != http.StatusNotFound || got.Redirected {
+		t.Fatalf("Probe of a missing mount = %+v, %v", got, err)
+	}
+}
+
+func TestJSONFieldsInfo(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		fields JSONFields
+		msg    string
+		want   Info
+		ok     bool
+	}{
+		{name: "flat", msg: `{"title":"Song","artist":"Artist","station":"Radio X"}`,
+			want: Info{Title: "Artist - Song", Station: "Radio X"}, ok: true},
+		{name: "azuracast", msg: `{"station":{"name":"Radio X"},"now_playing":{"song":{"title":"Song","artist":"Artist","art":"https://cdn.example/a.jpg"}}}`,
+			want: Info{Title: "Artist - Song", Station: "Radio X", ArtworkURL: "https://cdn.example/a.jpg"}, ok: true},
+		{name: "custom paths", fields: JSONFields{Title: "data.tracks.0.name", Artist: "data.tracks.0.by"},
+			msg: `{"data":{"tracks":[{"name":"Song","by":"Artist"},{"name":"Older"}]}}`, want: Info{Title: "Artist - Song"}, ok: true},
+		{name: "bracket paths", fields: JSONFields{Title: "$.data.tracks[1].name"},
+			msg: `{"data":{"tracks":[{"name":"Song"},{"name":"Older"}]}}`, want: Info{Title: "Older"}, ok: true},
+		{name: "unresolved path", fields: JSONFields{Title: "data.current.title"}, msg: `{"title":"Song"}`},
+		{name: "no title", msg: `{"listeners":12}`},
+		{name: "not json", msg: `hello`},
+	} {
+		got, ok := tt.fields.info([]byte(tt.msg))
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: got %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSSEStrategyReconnects(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		// each connection sends one event, split over two data lines, then drops
+		title := fmt.Sprintf("Song %d", hits.Add(1))
+		fmt.Fprintf(w, ": keep-alive\n\nevent: np\ndata: {\"artist\":\"Artist\",\ndata: \"title\":%q}\n\n", title)
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{}).(*dispatcher)
+	d.push.retryDelay = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var titles []string
+	var chosen StrategyHint
+	d.Run(ctx, srv.URL+"/live", StrategyHint{Type: "sse", URL: srv.URL + "/events"}, func(info Info) {
+		titles = append(titles, info.Title)
+		if len(titles) == 2 {
+			cancel()
+		}
+	}, func(h StrategyHint) { chosen = h })
+	if len(titles) != 2 || titles[0] != "Artist - Song 1" || titles[1] != "Artist - Song 2" {
+		t.Fatalf("titles %q", titles)
+	}
+	if chosen != (StrategyHint{Type: MetadataTypeSSE, URL: srv.URL + "/events"}) {
+		t.Fatalf("strategy %+v", chosen)
+	}
+}
+
+func TestWebSocketStrategy(t *testing.T) {
+	pong := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+			wsAccept(r.Header.Get("Sec-WebSocket-Key")))
+		// a ping, then one message split over a text and a continuation frame
+		rw.Write([]byte{0x80 | wsPing, 2, 'h', 'i'})
+		first, rest := `{"title":"So`, `ng","artist":"Artist"}`
+		rw.Write(append([]byte{wsText, byte(len(first))}, first...))
+		rw.Write(append([]byte{0x80 | wsContinuation, byte(len(rest))}, rest...))
+		rw.Flush()
+		if _, op, payload, err := readWSFrame(rw.Reader); err == nil && op == wsPong {
+			pong <- payload
+		}
+		// hold the socket until the client goes away
+		io.Copy(io.Discard, rw)
+	}))
+	defer srv.Close()
+
+	d := NewProvider(srv.Client(), testLogger{}, ProviderOptions{}).(*dispatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var got string
+	d.Run(ctx, srv.URL+"/live", StrategyHint{Type: MetadataTypeWS, URL: "ws" + strings.TrimPrefix(srv.URL, "http") + "/np"}, func(info Info) {
+		got = info.Title
+		cancel()
+	}, nil)
+	if got != "Artist - Song" {
+		t.Fatalf("title %q", got)
+	}
+	select {
+	case p := <-pong:
+		if string(p) != "hi" {
+			t.Fatalf("pong payload %q", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ping not answered")
+	}
+}
+
+func TestValidateJSONPath(t *testing.T) {
+	for _, p := range []string{"", "title", "data.current.title", "$.data.tracks[0].title", "items.2"} {
+		if err := ValidateJSONPath(p); err != nil {
+			t.Errorf("%q: %v", p, err)
+		}
+	}
+	for _, p := range []string{"data..title", "data.", ".", "data.*.title", "tracks[x]", "now playing"} {
+		if ValidateJSONPath(p) == nil {
+			t.Errorf("%q accepted", p)
+		}
+	}
+}
+
+func TestJSONAPIStrategy(t *testing.T) {
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"current":{"artist":"Artist","title":"Song %d"}}}`, polls.Add(1))
+	}))
+	defer srv.Close()
+
+	opts := ProviderOptions{PollInterval: 10 * time.Millisecond,
+		JSONFields: JSONFields{Title: "data.current.title", Artist: "data.current.artist"}}
+	d := NewProvider(srv.Client(), testLogger{}, opts).(*dispatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var titles []string
+	var chosen StrategyHint
+	d.Run(ctx, srv.URL+"/live", StrategyHint{Type: MetadataTypeAPI, URL: srv.URL + "/np"}, func(info Info) {
+		titles = append(titles, info.Title)
+		if len(titles) == 2 {
+			cancel()
+		}
+	}, func(h StrategyHint) { chosen = h })
+	if len(titles) != 2 || titles[0] != "Artist - Song 1" || titles[1] != "Artist - Song 2" {
+		t.Fatalf("titles %q", titles)
+	}
+	if chosen != (StrategyHint{Type: MetadataTypeAPI, URL: srv.URL + "/np"}) {
+		t.Fatalf("strategy %+v", chosen)
+	}
+
+	// paths that resolve nothing fail the first poll, as do invalid ones
+	d.api.fields = JSONFields{Title: "data.next.title"}
+	if err := d.api.Watch(context.Background(), srv.URL+"/np", nil, func(Info) {}); !errors.Is(err, errNoJSONFields) {
+		t.Fatalf("unresolved paths: %v", err)
+	}
+	d.api.fields = JSONFields{Title: "data..title"}
+	if err := d.api.Watch(context.Background(), srv.URL+"/np", nil, func(Info) {}); err == nil {
+		t.Fatal("invalid path accepted")
+	}
+}
@@ -0,0 +1,315 @@
+package metadata
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPushMessage caps one WebSocket message or SSE event.
+	maxPushMessage = 1 << 20
+	// pushRetryDelay is the first wait before reconnecting a dropped push
+	// source; it doubles on each failure in a row up to maxPushRetryDelay.
+	pushRetryDelay    = time.Second
+	maxPushRetryDelay = 30 * time.Second
+	// wsGUID is the fixed key suffix of the WebSocket handshake (RFC 6455).
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// WebSocket opcodes (RFC 6455 section 5.2).
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xa
+)
+
+// pushStrategy reads now-playing messages that a station pushes over a
+// WebSocket or as Server-Sent Events, mapping each JSON message with fields.
+type pushStrategy struct {
+	client *http.Client
+	logger Logger
+	limit  connLimiter
+	fields JSONFields
+	// retryDelay is the first reconnect wait, see Watch
+	retryDelay time.Duration
+}
+
+func newPushStrategy(client *http.Client, log Logger, limit connLimiter) *pushStrategy {
+	return &pushStrategy{client: client, logger: log, limit: limit, retryDelay: pushRetryDelay}
+}
+
+// Watch connects to the push source at apiURL, a WebSocket for
+// MetadataTypeWS or an event stream for MetadataTypeSSE, and reports every
+// message that carries a title or station name. It runs until ctx is done: a
+// dropped or failed connection is re-established after a backoff that doubles
+// up to maxPushRetryDelay and starts afresh once a connection delivers an
+// update. Only a refusal by the TLS settings returns early. onReady fires with
+// the first update.
+func (s *pushStrategy) Watch(ctx context.Context, typ, apiURL string, onReady func(), onUpdate func(Info)) error {
+	delay := s.retryDelay
+	for {
+		got := false
+		handle := func(msg []byte) {
+			info, ok := s.fields.info(msg)
+			if !ok {
+				return
+			}
+			if !got && onReady != nil {
+				onReady()
+				onReady = nil
+			}
+			got = true
+			onUpdate(info)
+		}
+		var err error
+		if typ == MetadataTypeWS {
+			err = s.readWS(ctx, apiURL, handle)
+		} else {
+			err = s.readSSE(ctx, apiURL, handle)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if isSecurityError(err) {
+			return err
+		}
+		if got {
+			delay = s.retryDelay
+		}
+		if s.logger != nil {
+			s.logger.Printf("%s %s: %v; reconnecting in %s", strings.ToLower(typ), apiURL, err, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxPushRetryDelay)
+	}
+}
+
+// do sends req holding a connection slot, which release frees.
+func (s *pushStrategy) do(req *http.Request) (resp *http.Response, release func(), err error) {
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req.Header.Set("User-Agent", defaultUA)
+	release, err = s.limit.hold(req.Context())
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return resp, release, nil
+}
+
+// readSSE reads one event stream until it ends, passing the data of each event
+// to handle.
+func (s *pushStrategy) readSSE(ctx context.Context, apiURL string, handle func([]byte)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	resp, release, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("event stream: %s", resp.Status)
+	}
+	// connected: the open stream no longer counts against the limit
+	release()
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 4096), maxPushMessage)
+	var data []string
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case line == "":
+			// a blank line ends the event
+			if len(data) > 0 {
+				handle([]byte(strings.Join(data, "\n")))
+				data = nil
+			}
+		case strings.HasPrefix(line, ":"):
+			// comment, often a keep-alive
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			if field == "data" {
+				data = append(data, strings.TrimPrefix(value, " "))
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return errors.New("event stream closed")
+}
+
+// readWS opens one WebSocket and reads it until it closes, passing each
+// message to handle. The handshake goes through the strategy's client, so
+// proxy, TLS and header settings apply as to any other metadata request.
+func (s *pushStrategy) readWS(ctx context.Context, apiURL string, handle func([]byte)) error {
+	target := apiURL
+	if rest, ok := cutPrefixFold(target, "ws://"); ok {
+		target = "http://" + rest
+	} else if rest, ok := cutPrefixFold(target, "wss://"); ok {
+		target = "https://" + rest
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	resp, release, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return fmt.Errorf("websocket handshake: %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAccept(key) {
+		return errors.New("websocket handshake: bad Sec-WebSocket-Accept")
+	}
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		return errors.New("websocket handshake: connection not upgraded")
+	}
+	release()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	r := bufio.NewReader(conn)
+	var msg []byte
+	for {
+		fin, op, payload, err := readWSFrame(r)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case wsPing:
+			if err := writeWSFrame(conn, wsPong, payload); err != nil {
+				return err
+			}
+		case wsClose:
+			_ = writeWSFrame(conn, wsClose, nil)
+			return errors.New("websocket closed by server")
+		case wsText, wsBinary, wsContinuation:
+			msg = append(msg, payload...)
+			if len(msg) > maxPushMessage {
+				return errors.New("websocket message too large")
+			}
+			if fin {
+				handle(msg)
+				msg = nil
+			}
+		}
+	}
+}
+
+// cutPrefixFold is strings.CutPrefix ignoring ASCII case.
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
+
+// wsAccept is the Sec-WebSocket-Accept value a server must answer key with.
+func wsAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// readWSFrame reads one WebSocket frame, unmasking its payload if needed.
+func readWSFrame(r *bufio.Reader) (fin bool, op byte, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(r, h[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op = h[0]&0x80 != 0, h[0]&0x0f
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var b [2]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(b[:])
+	}
+	if n > maxPushMessage {
+		return false, 0, nil, errors.New("websocket frame too large")
+	}
+	var mask [4]byte
+	masked := h[1]&0x80 != 0
+	if masked {
+		if _, err = io.ReadFull(r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// writeWSFrame sends a control frame; frames from a client are always masked.
+func writeWSFrame(w io.Writer, op byte, payload []byte) error {
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+	frame := make([]byte, 6, 6+len(payload))
+	frame[0] = 0x80 | op
+	frame[1] = 0x80 | byte(len(payload))
+	if _, err := rand.Read(frame[2:6]); err != nil {
+		return err
+	}
+	for i, b := range payload {
+		frame = append(frame, b^frame[2+i%4])
+	}
+	_, err := w.Write(frame)
+	return err
+}
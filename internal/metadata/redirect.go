@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxRedirects bounds the Location chain followed by ResolveFinalURL.
+const maxRedirects = 10
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+// ResolveFinalURL follows HTTP redirects from rawURL and returns the endpoint
+// of the Location chain. Each hop is a single GET whose body is closed as soon
+// as the headers arrive, so pointing it at a live stream is cheap. A target
+// that does not redirect is returned unchanged; on error rawURL is returned
+// together with the error.
+func ResolveFinalURL(ctx context.Context, client *http.Client, rawURL string) (string, error) {
+	return FollowRedirects(ctx, client, rawURL, maxRedirects, nil)
+}
+
+// FollowRedirects is ResolveFinalURL with a caller-chosen hop limit; onHop,
+// when set, receives every absolute URL the chain moves on to, with relative
+// Location headers resolved against the previous request.
+func FollowRedirects(ctx context.Context, client *http.Client, rawURL string, max int, onHop func(string)) (string, error) {
+	if client == nil {
+		client = newHTTPClient(0, http.ProxyFromEnvironment)
+	}
+	// never let the client follow on its own; each hop is inspected here
+	cli := *client
+	cli.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	current := strings.TrimSpace(rawURL)
+	for hop := 0; hop <= max; hop++ {
+		next, ok, err := redirectTarget(ctx, &cli, current)
+		if err != nil {
+			return rawURL, err
+		}
+		if !ok {
+			return current, nil
+		}
+		if hop == max {
+			break
+		}
+		if onHop != nil {
+			onHop(next)
+		}
+		current = next
+	}
+	return rawURL, errTooManyRedirects
+}
+
+// redirectTarget requests target once and reports the absolute Location it
+// redirects to. ok is false for any non-redirect answer.
+func redirectTarget(ctx context.Context, client *http.Client, target string) (string, bool, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(cctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("User-Agent", defaultUA)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return "", false, nil
+	}
+	loc := strings.TrimSpace(resp.Header.Get("Location"))
+	if loc == "" {
+		return "", false, nil
+	}
+	ref, err := url.Parse(loc)
+	if err != nil {
+		return "", false, err
+	}
+	return resp.Request.URL.ResolveReference(ref).String(), true, nil
+}
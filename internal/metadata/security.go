@@ -0,0 +1,121 @@
+package metadata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrInsecure is returned for plain-HTTP requests refused because
+	// ProviderOptions.RequireTLS is set, including redirects off https.
+	ErrInsecure = errors.New("plain HTTP refused: station requires TLS")
+	// ErrCertRejected is returned when ProviderOptions.VerifyConnection
+	// rejects a server, e.g. because its certificate matches no pin.
+	ErrCertRejected = errors.New("server certificate rejected")
+)
+
+// isSecurityError reports whether err comes from RequireTLS or
+// VerifyConnection; trying again or elsewhere cannot help.
+func isSecurityError(err error) bool {
+	return errors.Is(err, ErrInsecure) || errors.Is(err, ErrCertRejected)
+}
+
+// PinSHA256 returns a ProviderOptions.VerifyConnection hook that accepts a
+// server only when some certificate of its verified chain has a public key
+// whose SHA-256 matches one of pins. Pins use curl's --pinnedpubkey form,
+// "sha256//" followed by the base64 hash; "sha256/" is accepted too.
+func PinSHA256(pins []string) (func(tls.ConnectionState) error, error) {
+	var want [][]byte
+	for _, p := range pins {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		enc, ok := strings.CutPrefix(p, "sha256/")
+		if !ok {
+			return nil, fmt.Errorf("pin %q: want sha256//<base64>", p)
+		}
+		sum, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(enc, "/"))
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("pin %q: not a base64 SHA-256 hash", p)
+		}
+		want = append(want, sum)
+	}
+	if len(want) == 0 {
+		return nil, errors.New("no certificate pins given")
+	}
+	return func(cs tls.ConnectionState) error {
+		for _, cert := range cs.PeerCertificates {
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			for _, w := range want {
+				if bytes.Equal(sum[:], w) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%s: no certificate matches the pinned keys", cs.ServerName)
+	}, nil
+}
+
+// withSecurity returns a copy of client enforcing opts.MinTLS,
+// opts.VerifyConnection and opts.RequireTLS, reporting refusals to
+// opts.OnSecurityError. client itself is returned when none is set.
+func withSecurity(client *http.Client, opts ProviderOptions) *http.Client {
+	if opts.MinTLS == 0 && opts.VerifyConnection == nil && !opts.RequireTLS {
+		return client
+	}
+	report := func(err error) error {
+		if opts.OnSecurityError != nil {
+			opts.OnSecurityError(err)
+		}
+		return err
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t, ok := base.(*http.Transport); ok && (opts.MinTLS != 0 || opts.VerifyConnection != nil) {
+		t = t.Clone()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		if opts.MinTLS != 0 {
+			t.TLSClientConfig.MinVersion = opts.MinTLS
+		}
+		if verify := opts.VerifyConnection; verify != nil {
+			t.TLSClientConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+				if err := verify(cs); err != nil {
+					return report(fmt.Errorf("%w: %v", ErrCertRejected, err))
+				}
+				return nil
+			}
+		}
+		base = t
+	}
+	if opts.RequireTLS {
+		base = &tlsOnlyTransport{base: base, report: report}
+	}
+	out := *client
+	out.Transport = base
+	return &out
+}
+
+// tlsOnlyTransport refuses every request that is not https. Redirects pass
+// through RoundTrip too, so a downgrade is caught at the hop that makes it.
+type tlsOnlyTransport struct {
+	base   http.RoundTripper
+	report func(error) error
+}
+
+func (t *tlsOnlyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !strings.EqualFold(r.URL.Scheme, "https") {
+		return nil, t.report(fmt.Errorf("%w: %s", ErrInsecure, r.URL.Redacted()))
+	}
+	return t.base.RoundTrip(r)
+}
@@ -0,0 +1,179 @@
+package metadata
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// shoutcastUA looks like a browser on purpose: Shoutcast v1 serves the audio
+// stream instead of 7.html unless the user agent mentions "Mozilla".
+var shoutcastUA = "Mozilla/5.0 (compatible; " + defaultUA + ")"
+
+// shoutcastStrategy polls classic Shoutcast servers on the stream host. v1
+// exposes "/7.html" (comma-separated counters followed by the song title) and
+// v2 exposes "/stats?sid=1" as XML.
+type shoutcastStrategy struct {
+	client *http.Client
+	logger Logger
+	limit  connLimiter
+	// zero values fall back to defaultPollInterval/defaultRequestTimeout
+	pollInterval   time.Duration
+	requestTimeout time.Duration
+}
+
+func newShoutcastStrategy(client *http.Client, log Logger, limit connLimiter) *shoutcastStrategy {
+	return &shoutcastStrategy{client: client, logger: log, limit: limit}
+}
+
+// Watch mirrors statusJSONStrategy.Watch: it reports the working endpoint via
+// onReady after the first successful poll and then refreshes on the same poll
+// interval until the context is cancelled.
+func (s *shoutcastStrategy) Watch(ctx context.Context, streamURL string, apiURL string, onReady func(string), onUpdate func(Info)) error {
+	endpoints := []string{apiURL}
+	if strings.TrimSpace(apiURL) == "" {
+		var err error
+		endpoints, err = buildShoutcastURLs(streamURL)
+		if err != nil {
+			return err
+		}
+	}
+	var (
+		info     Info
+		endpoint string
+		ok       bool
+	)
+	for _, ep := range endpoints {
+		if info, ok = s.pollOnce(ctx, ep); ok {
+			endpoint = ep
+			break
+		}
+	}
+	if !ok {
+		return errors.New("shoutcast stats unavailable")
+	}
+	if onReady != nil {
+		onReady(endpoint)
+	}
+	onUpdate(info)
+	ticker := time.NewTicker(orDefault(s.pollInterval, defaultPollInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if info, ok := s.pollOnce(ctx, endpoint); ok {
+				onUpdate(info)
+			}
+		}
+	}
+}
+
+// pollOnce fetches a single stats page and parses it according to its kind.
+func (s *shoutcastStrategy) pollOnce(ctx context.Context, endpoint string) (Info, bool) {
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cctx, cancel := context.WithTimeout(ctx, orDefault(s.requestTimeout, defaultRequestTimeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(cctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Info{}, false
+	}
+	req.Header.Set("User-Agent", shoutcastUA)
+	release, err := s.limit.hold(cctx)
+	if err != nil {
+		return Info{}, false
+	}
+	defer release()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Info{}, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Info{}, false
+	}
+	// a server that ignores the path may answer with the stream itself
+	if strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "audio/") {
+		return Info{}, false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return Info{}, false
+	}
+	if isSevenHTML(endpoint) {
+		title, ok := parseSevenHTML(string(body))
+		return Info{Title: title}, ok
+	}
+	return parseShoutcastStats(body)
+}
+
+// buildShoutcastURLs returns the v1 and v2 stats endpoints on the stream host,
+// in the order they should be tried.
+func buildShoutcastURLs(streamURL string) ([]string, error) {
+	u, err := url.Parse(streamURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.New("invalid url")
+	}
+	v1 := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/7.html"}
+	v2 := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/stats", RawQuery: "sid=1"}
+	return []string{v1.String(), v2.String()}, nil
+}
+
+// isSevenHTML reports whether the endpoint is a Shoutcast v1 "7.html" page.
+func isSevenHTML(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimPrefix(u.Path, "/"), "7.html")
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// parseSevenHTML extracts the song title from a 7.html body of the form
+// "listeners,status,peak,max,unique,bitrate,song title". The title is the
+// seventh field and may itself contain commas.
+func parseSevenHTML(body string) (string, bool) {
+	text := strings.TrimSpace(htmlTagPattern.ReplaceAllString(body, ""))
+	fields := strings.SplitN(text, ",", 7)
+	if len(fields) < 7 {
+		return "", false
+	}
+	title := strings.TrimSpace(html.UnescapeString(fields[6]))
+	if title == "" {
+		return "", false
+	}
+	return title, true
+}
+
+type shoutcastStats struct {
+	SongTitle   string `xml:"SONGTITLE"`
+	ServerTitle string `xml:"SERVERTITLE"`
+}
+
+// parseShoutcastStats decodes the Shoutcast v2 "/stats" XML document.
+func parseShoutcastStats(body []byte) (Info, bool) {
+	var st shoutcastStats
+	if err := xml.Unmarshal(body, &st); err != nil {
+		return Info{}, false
+	}
+	title := strings.TrimSpace(st.SongTitle)
+	if title == "" {
+		return Info{}, false
+	}
+	return Info{Title: title, Station: strings.TrimSpace(st.ServerTitle)}, true
+}
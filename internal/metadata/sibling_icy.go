@@ -5,9 +5,11 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"html"
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,20 +21,43 @@ import (
 type siblingStrategy struct {
 	client *http.Client
 	logger Logger
+	limit  connLimiter
+	// proxy applies to the fallback client built when client is nil.
+	proxy proxyFn
+	// order is ProviderOptions.SiblingOrder
+	order string
 }
 
+// Sibling probing is bounded per scan: at most siblingParallel probes run at
+// once, and each starts siblingProbeGap after the one before.
+const (
+	siblingParallel = 3
+	siblingProbeGap = 80 * time.Millisecond
+)
+
+// siblingBitrates are the bitrates tried for a sibling mount, highest first;
+// siblingNames are the codec and generic mount names tried after them.
+var (
+	siblingBitrates = []int{320, 256, 192, 128}
+	siblingNames    = []string{"stream", "live", "aac", "aacp", "mp3"}
+)
+
 var siblingCache sync.Map // family key -> sibling URL
 
 // newSiblingStrategy constructs a new siblingStrategy with optional HTTP client
 // and logger overrides.
-func newSiblingStrategy(client *http.Client, log Logger) *siblingStrategy {
-	return &siblingStrategy{client: client, logger: log}
+func newSiblingStrategy(client *http.Client, log Logger, limit connLimiter) *siblingStrategy {
+	return &siblingStrategy{client: client, logger: log, limit: limit, proxy: http.ProxyFromEnvironment}
 }
 
 // Discover attempts to find a lossy sibling stream that exposes ICY metadata.
 // Returns the sibling URL and an optional initial Info sample.
 func (s *siblingStrategy) Discover(ctx context.Context, streamURL string) (string, Info, error) {
 	key := stationCacheKey(streamURL)
+	if key != "" && s.order != "" && s.order != SiblingHighest {
+		// another preference may pick another sibling of the same family
+		key += "|" + s.order
+	}
 	if key != "" {
 		if cached, ok := siblingCache.Load(key); ok {
 			if target, _ := cached.(string); target != "" {
@@ -54,38 +79,100 @@ func (s *siblingStrategy) scanCandidates(ctx context.Context, streamURL string)
 	if err != nil || u == nil {
 		return "", Info{}, errors.New("invalid url")
 	}
-	candidates := buildSiblingCandidates(u.Path)
-	if len(candidates) == 0 {
+	paths := buildSiblingCandidates(u.Path, s.order)
+	if len(paths) == 0 {
 		return "", Info{}, errors.New("no candidates")
 	}
-	for i, suffix := range candidates {
+	candidates := make([]string, len(paths))
+	for i, p := range paths {
+		candidates[i] = (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: p}).String()
+	}
+	i, info, ok := s.probeInOrder(ctx, candidates)
+	if ctx.Err() != nil {
+		return "", Info{}, ctx.Err()
+	}
+	if !ok {
+		return "", Info{}, errors.New("no sibling metadata")
+	}
+	return candidates[i], info, nil
+}
+
+// probeInOrder probes candidates, all on one host, up to siblingParallel at a
+// time and starting them siblingProbeGap apart so the server is not
+// hammered. It returns the first candidate in priority order that serves
+// metadata, waiting for the ones before it to fail, and cancels the rest.
+func (s *siblingStrategy) probeInOrder(ctx context.Context, candidates []string) (int, Info, bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	type result struct {
+		i    int
+		info Info
+		ok   bool
+	}
+	results := make(chan result, len(candidates))
+	slots := make(chan struct{}, siblingParallel)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, cand := range candidates {
+			if i > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(siblingProbeGap):
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				info, ok := s.probeCandidate(ctx, cand)
+				<-slots
+				results <- result{i, info, ok}
+			}()
+		}
+	}()
+
+	done := make([]bool, len(candidates))
+	found := make([]*Info, len(candidates))
+	next := 0 // lowest candidate not yet known to have failed
+	for next < len(candidates) {
 		select {
 		case <-ctx.Done():
-			return "", Info{}, ctx.Err()
-		default:
-		}
-		cand := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: suffix}
-		candURL := cand.String()
-		title, station, ok := s.probeCandidate(ctx, candURL)
-		if ok {
-			return cand.String(), Info{Title: title, Station: station}, nil
+			return -1, Info{}, false
+		case r := <-results:
+			done[r.i] = true
+			if r.ok {
+				found[r.i] = &r.info
+			}
 		}
-		if i < len(candidates)-1 {
-			time.Sleep(80 * time.Millisecond)
+		for next < len(candidates) && done[next] {
+			if found[next] != nil {
+				return next, *found[next], true
+			}
+			next++
 		}
 	}
-	return "", Info{}, errors.New("no sibling metadata")
+	return -1, Info{}, false
 }
 
 // probeCandidate performs a lightweight HEAD-ish ICY request and only downloads
-// the first metadata block to confirm viability.
-func (s *siblingStrategy) probeCandidate(ctx context.Context, candidate string) (string, string, bool) {
+// the first metadata block to confirm viability. The candidate's bitrate is
+// not reported since it is not the stream being played.
+func (s *siblingStrategy) probeCandidate(ctx context.Context, candidate string) (Info, bool) {
 	client := s.client
 	if client == nil {
 		client = &http.Client{
 			Transport: &http.Transport{
 				ForceAttemptHTTP2: false,
-				Proxy:             http.ProxyFromEnvironment,
+				Proxy:             s.proxy,
 				DialContext: (&net.Dialer{
 					Timeout:   2 * time.Second,
 					KeepAlive: 15 * time.Second,
@@ -102,34 +189,44 @@ func (s *siblingStrategy) probeCandidate(ctx context.Context, candidate string)
 	defer cancel()
 	req, err := http.NewRequestWithContext(cctx, http.MethodGet, candidate, nil)
 	if err != nil {
-		return "", "", false
+		return Info{}, false
 	}
 	req.Header.Set("Icy-MetaData", "1")
 	req.Header.Set("User-Agent", defaultUA)
+	release, err := s.limit.hold(cctx)
+	if err != nil {
+		return Info{}, false
+	}
+	defer release()
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", "", false
+		return Info{}, false
 	}
 	defer resp.Body.Close()
 	metaIntStr := resp.Header.Get("icy-metaint")
 	if metaIntStr == "" {
-		return "", "", false
+		return Info{}, false
 	}
 	metaInt, err := strconv.Atoi(metaIntStr)
 	if err != nil || metaInt <= 0 {
-		return "", "", false
+		return Info{}, false
 	}
 	title, err := firstMetaBlock(bufio.NewReader(resp.Body), metaInt)
 	if err != nil || strings.TrimSpace(title) == "" {
-		return "", "", false
+		return Info{}, false
 	}
 	station := strings.TrimSpace(resp.Header.Get("icy-name"))
-	return fixMojibake(title), fixMojibake(station), true
+	genre := html.UnescapeString(strings.TrimSpace(resp.Header.Get("icy-genre")))
+	return Info{Title: fixMojibake(title), Station: fixMojibake(station), Genre: genre}, true
 }
 
 // buildSiblingCandidates generates prioritized sibling mount paths by swapping
-// bitrate or codec suffixes (e.g. "-320k" -> "-128k").
-func buildSiblingCandidates(originalPath string) []string {
+// bitrate or codec suffixes (e.g. "-320k" -> "-128k"). order is a
+// SiblingOrder value: bitrates are tried highest first by default, lowest
+// first for SiblingLowest, and for SiblingMatch closest to the original at or
+// below it, leaving out higher ones; an original whose suffix names no
+// bitrate keeps the default order.
+func buildSiblingCandidates(originalPath, order string) []string {
 	if originalPath == "" {
 		return nil
 	}
@@ -141,13 +238,21 @@ func buildSiblingCandidates(originalPath string) []string {
 	if suffix == "" {
 		return nil
 	}
-	priorities := []string{
-		"320", "320k",
-		"256", "256k",
-		"192", "192k",
-		"128", "128k",
-		"stream", "live", "aac", "aacp", "mp3",
+	bitrates := siblingBitrates
+	switch order {
+	case SiblingLowest:
+		bitrates = slices.Clone(bitrates)
+		slices.Reverse(bitrates)
+	case SiblingMatch:
+		if orig := suffixBitrate(suffix); orig > 0 {
+			bitrates = slices.DeleteFunc(slices.Clone(bitrates), func(b int) bool { return b > orig })
+		}
+	}
+	priorities := make([]string, 0, 2*len(bitrates)+len(siblingNames))
+	for _, b := range bitrates {
+		priorities = append(priorities, strconv.Itoa(b), strconv.Itoa(b)+"k")
 	}
+	priorities = append(priorities, siblingNames...)
 	result := make([]string, 0, len(priorities))
 	for _, label := range priorities {
 		result = append(result, strings.Replace(orig, suffix, label, 1))
@@ -155,6 +260,23 @@ func buildSiblingCandidates(originalPath string) []string {
 	return result
 }
 
+// suffixBitrate reads the bitrate a mount suffix such as "128", "128k" or
+// "64kbps" names, or 0 when it names none.
+func suffixBitrate(suffix string) int {
+	suffix = strings.ToLower(suffix)
+	digits := strings.TrimRight(suffix, "abcdefghijklmnopqrstuvwxyz")
+	switch suffix[len(digits):] {
+	case "", "k", "kbps", "kbit":
+	default:
+		return 0
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
+
 // detectSiblingSuffix extracts the trailing token (bitrate/codec) to swap.
 func detectSiblingSuffix(path string) string {
 	if path == "" {
@@ -17,14 +17,19 @@ import (
 type statusJSONStrategy struct {
 	client *http.Client
 	logger Logger
+	limit  connLimiter
+	// zero values fall back to defaultPollInterval/defaultRequestTimeout
+	pollInterval   time.Duration
+	requestTimeout time.Duration
 }
 
-func newStatusJSONStrategy(client *http.Client, log Logger) *statusJSONStrategy {
-	return &statusJSONStrategy{client: client, logger: log}
+func newStatusJSONStrategy(client *http.Client, log Logger, limit connLimiter) *statusJSONStrategy {
+	return &statusJSONStrategy{client: client, logger: log, limit: limit}
 }
 
 // Watch sends the initial update after the first successful poll and then
-// continues to refresh every 10 seconds until the context is cancelled.
+// continues to refresh every poll interval (10s by default) until the context
+// is cancelled.
 func (s *statusJSONStrategy) Watch(ctx context.Context, streamURL string, apiURL string, onReady func(string), onUpdate func(Info)) error {
 	if strings.TrimSpace(apiURL) == "" {
 		var err error
@@ -33,67 +38,127 @@ func (s *statusJSONStrategy) Watch(ctx context.Context, streamURL string, apiURL
 			return err
 		}
 	}
-	title, station, ok := s.pollOnce(ctx, apiURL)
+	mount := streamMount(streamURL)
+	info, ok := s.pollOnce(ctx, apiURL, mount)
 	if !ok {
 		return errors.New("status-json unavailable")
 	}
 	if onReady != nil {
 		onReady(apiURL)
 	}
-	onUpdate(Info{Title: title, Station: station})
-	ticker := time.NewTicker(10 * time.Second)
+	onUpdate(info)
+	ticker := time.NewTicker(orDefault(s.pollInterval, defaultPollInterval))
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
-			title, station, ok := s.pollOnce(ctx, apiURL)
-			if ok {
-				onUpdate(Info{Title: title, Station: station})
+			if info, ok := s.pollOnce(ctx, apiURL, mount); ok {
+				onUpdate(info)
 			}
 		}
 	}
 }
 
-// pollOnce performs a single request, returning (title, station, true) if a
-// valid source entry exists.
-func (s *statusJSONStrategy) pollOnce(ctx context.Context, apiURL string) (string, string, bool) {
+// pollOnce performs a single request, returning the track info and true if a
+// valid source entry exists. On multi-mount servers the source whose mount
+// matches the stream path wins; otherwise the first titled source is used.
+func (s *statusJSONStrategy) pollOnce(ctx context.Context, apiURL, mount string) (Info, bool) {
 	client := s.client
 	if client == nil {
 		client = http.DefaultClient
 	}
-	cctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	cctx, cancel := context.WithTimeout(ctx, orDefault(s.requestTimeout, defaultRequestTimeout))
 	defer cancel()
 	req, err := http.NewRequestWithContext(cctx, http.MethodGet, apiURL, nil)
 	if err != nil {
-		return "", "", false
+		return Info{}, false
 	}
 	req.Header.Set("User-Agent", defaultUA)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	release, err := s.limit.hold(cctx)
+	if err != nil {
+		return Info{}, false
+	}
+	defer release()
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", "", false
+		return Info{}, false
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", "", false
+		return Info{}, false
+	}
+	body, err := decodedBody(resp)
+	if err != nil {
+		return Info{}, false
 	}
 	var st iceStats
-	dec := json.NewDecoder(io.LimitReader(resp.Body, 1<<20))
+	dec := json.NewDecoder(io.LimitReader(body, 1<<20))
 	if err := dec.Decode(&st); err != nil {
-		return "", "", false
+		return Info{}, false
+	}
+	src, ok := pickSource(extractSources(st.IceStats.Source), mount)
+	if !ok {
+		return Info{}, false
+	}
+	station := src.Server
+	if strings.TrimSpace(station) == "" {
+		station = src.IcyName
 	}
-	sources := extractSources(st.IceStats.Source)
+	art := artworkURL(src.Artwork)
+	if art == "" {
+		art = artworkURL(src.ArtworkURL)
+	}
+	return Info{
+		Title:      src.Title,
+		Station:    station,
+		ArtworkURL: art,
+		Genre:      strings.TrimSpace(src.Genre),
+		Bitrate:    int(src.Bitrate),
+		Listeners:  int(src.Listeners),
+		ListenURL:  artworkURL(src.ListenURL),
+	}, true
+}
+
+// pickSource returns the source serving mount, even while it has no title:
+// another mount's title belongs to a different channel. Only when no source
+// matches (or mount is unknown) does it fall back to the first titled one.
+func pickSource(sources []iceSource, mount string) (iceSource, bool) {
+	var (
+		first iceSource
+		found bool
+	)
 	for _, src := range sources {
-		if strings.TrimSpace(src.Title) != "" {
-			station := src.Server
-			if strings.TrimSpace(station) == "" {
-				station = src.IcyName
-			}
-			return src.Title, station, true
+		if mount != "" && src.mountPath() == mount {
+			return src, true
 		}
+		if !found && strings.TrimSpace(src.Title) != "" {
+			first, found = src, true
+		}
+	}
+	return first, found
+}
+
+// streamMount returns the normalized mount path of a stream URL ("/rock").
+func streamMount(streamURL string) string {
+	u, err := url.Parse(streamURL)
+	if err != nil {
+		return ""
+	}
+	return normalizeMount(u.Path)
+}
+
+func normalizeMount(p string) string {
+	p = strings.TrimRight(strings.TrimSpace(p), "/")
+	if p == "" {
+		return ""
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
 	}
-	return "", "", false
+	return p
 }
 
 // buildStatusURL converts a stream URL ("/live/rock") into its sibling JSON
@@ -117,6 +182,38 @@ type iceSource struct {
 	Title   string `json:"title"`
 	Server  string `json:"server_name"`
 	IcyName string `json:"icy-name"`
+	// artwork is not part of stock Icecast; some source clients add it.
+	Artwork    string `json:"artwork"`
+	ArtworkURL string `json:"artwork_url"`
+	// ListenURL is the public URL of the mount; some servers add Mount too.
+	ListenURL string `json:"listenurl"`
+	Mount     string `json:"mount"`
+	Genre     string `json:"genre"`
+	// Icecast versions and source clients disagree on whether these are
+	// numbers or strings
+	Bitrate   flexInt `json:"bitrate"`
+	Listeners flexInt `json:"listeners"`
+}
+
+// flexInt decodes a positive count given as a JSON number or a string, read
+// like an icy-br header: 128, "128" and "128,128" are all 128. Anything else,
+// such as null or "n/a", reads as 0 rather than failing the source.
+type flexInt int
+
+func (n *flexInt) UnmarshalJSON(b []byte) error {
+	*n = flexInt(parseICYBitrate(strings.Trim(string(b), `"`)))
+	return nil
+}
+
+// mountPath reports the source's mount, preferring the explicit field.
+func (src iceSource) mountPath() string {
+	if m := normalizeMount(src.Mount); m != "" {
+		return m
+	}
+	if src.ListenURL == "" {
+		return ""
+	}
+	return streamMount(src.ListenURL)
 }
 
 // extractSources normalizes Icecast's `source` field which may be a single
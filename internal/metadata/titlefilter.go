@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TitleFilterStrip prefixes a title filter pattern whose matches are cut out
+// of the title instead of suppressing it.
+const TitleFilterStrip = "strip:"
+
+// TitleFilter cleans up now-playing titles of stations that announce ad
+// breaks or repeat their own name. It is built from patterns of the form
+//
+//	AdBreak             suppress titles containing "AdBreak" (any case)
+//	/^Advert/           suppress titles matching the regular expression
+//	strip:Radio X -     remove "Radio X -" (any case) from titles
+//	strip:/^\[.*?\]\s*/ remove what the regular expression matches
+//
+// A nil *TitleFilter passes every title through unchanged.
+type TitleFilter struct {
+	rules []titleRule
+}
+
+type titleRule struct {
+	re    *regexp.Regexp
+	strip bool
+}
+
+// NewTitleFilter compiles patterns; blank entries are ignored and an empty
+// list yields a nil filter. The first invalid regular expression is reported.
+func NewTitleFilter(patterns []string) (*TitleFilter, error) {
+	var rules []titleRule
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		strip := false
+		if len(p) >= len(TitleFilterStrip) && strings.EqualFold(p[:len(TitleFilterStrip)], TitleFilterStrip) {
+			strip = true
+			p = strings.TrimSpace(p[len(TitleFilterStrip):])
+		}
+		if p == "" {
+			continue
+		}
+		expr := "(?i)" + regexp.QuoteMeta(p)
+		if len(p) >= 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+			expr = p[1 : len(p)-1]
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("title filter %q: %w", p, err)
+		}
+		rules = append(rules, titleRule{re: re, strip: strip})
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return &TitleFilter{rules: rules}, nil
+}
+
+// Apply returns title with the strip rules applied, trimmed of leftover
+// spaces and separators. ok is false when a suppress rule matches or nothing
+// is left, in which case the update should not be shown.
+func (f *TitleFilter) Apply(title string) (string, bool) {
+	if f == nil {
+		return title, true
+	}
+	for _, r := range f.rules {
+		if r.strip {
+			title = r.re.ReplaceAllString(title, "")
+			continue
+		}
+		if r.re.MatchString(title) {
+			return "", false
+		}
+	}
+	title = strings.Trim(strings.TrimSpace(title), " -|:")
+	return title, title != ""
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+// Package mediakeys is compiled as a no-op on non-Windows platforms.
+package mediakeys
+
+import "fyne.io/fyne/v2"
+
+// Key identifies a hardware media key.
+type Key int
+
+const (
+	PlayPause Key = iota + 1
+	Next
+	Previous
+	Stop
+	Play
+	Pause
+)
+
+// Register is a stub that returns false on non-Windows platforms.
+func Register(fw fyne.Window, handler func(Key)) bool {
+	return false
+}
@@ -0,0 +1,124 @@
+//go:build windows
+
+// Package mediakeys forwards the keyboard's media keys to the app on Windows.
+// fyne only reports keys typed into the canvas, while media keys arrive as
+// WM_APPCOMMAND messages, so the window procedure is subclassed to catch them.
+package mediakeys
+
+import (
+	"sync"
+	"syscall"
+
+	"fyne.io/fyne/v2"
+
+	windowpos "github.com/edward-ap/miniradio/internal/platform/win/windowpos"
+)
+
+// Key identifies a hardware media key.
+type Key int
+
+const (
+	PlayPause Key = iota + 1
+	Next
+	Previous
+	Stop
+	Play
+	Pause
+)
+
+var (
+	user32                = syscall.NewLazyDLL("user32.dll")
+	procSetWindowLongPtrW = user32.NewProc("SetWindowLongPtrW")
+	procSetWindowLongW    = user32.NewProc("SetWindowLongW")
+	procCallWindowProcW   = user32.NewProc("CallWindowProcW")
+)
+
+const (
+	wmAPPCOMMAND = 0x0319
+	gwlpWNDPROC  = ^uintptr(3) // -4
+
+	appcommandMediaNextTrack     = 11
+	appcommandMediaPreviousTrack = 12
+	appcommandMediaStop          = 13
+	appcommandMediaPlayPause     = 14
+	appcommandMediaPlay          = 46
+	appcommandMediaPause         = 47
+)
+
+var (
+	mu       sync.Mutex
+	handler  func(Key)
+	prevProc uintptr
+	hooked   uintptr
+	callback = syscall.NewCallback(wndProc)
+)
+
+// Register subclasses the window procedure of w so media keys are delivered
+// to fn. Calling it again only swaps the handler. Returns true once the hook
+// is installed; it fails until the native window exists.
+func Register(w fyne.Window, fn func(Key)) bool {
+	mu.Lock()
+	handler = fn
+	installed := hooked != 0
+	mu.Unlock()
+	if installed {
+		return true
+	}
+	return windowpos.WithNativeHWND(w, func(hwnd uintptr) bool {
+		proc := procSetWindowLongPtrW
+		if proc.Find() != nil {
+			// 32-bit user32 only exports the non-Ptr variant
+			proc = procSetWindowLongW
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		prev, _, err := proc.Call(hwnd, gwlpWNDPROC, callback)
+		if prev == 0 {
+			if err != syscall.Errno(0) {
+				fyne.LogError("SetWindowLongPtr failed", err)
+			}
+			return false
+		}
+		prevProc = prev
+		hooked = hwnd
+		return true
+	})
+}
+
+// wndProc handles WM_APPCOMMAND media commands and passes everything else to
+// the original window procedure.
+func wndProc(hwnd, msg, wParam, lParam uintptr) uintptr {
+	if msg == wmAPPCOMMAND {
+		// GET_APPCOMMAND_LPARAM: the command lives in the high word, minus
+		// the device bits.
+		cmd := (lParam >> 16) & 0x0FFF
+		var key Key
+		switch cmd {
+		case appcommandMediaPlayPause:
+			key = PlayPause
+		case appcommandMediaNextTrack:
+			key = Next
+		case appcommandMediaPreviousTrack:
+			key = Previous
+		case appcommandMediaStop:
+			key = Stop
+		case appcommandMediaPlay:
+			key = Play
+		case appcommandMediaPause:
+			key = Pause
+		}
+		mu.Lock()
+		fn := handler
+		mu.Unlock()
+		if key != 0 && fn != nil {
+			// never block the message loop on app work
+			go fn(key)
+			return 1
+		}
+	}
+	mu.Lock()
+	prev := prevProc
+	mu.Unlock()
+	ret, _, _ := procCallWindowProcW.Call(prev, hwnd, msg, wParam, lParam)
+	return ret
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+// Package smtc is compiled as a no-op on non-Windows platforms.
+package smtc
+
+import (
+	"fyne.io/fyne/v2"
+
+	mediakeys "github.com/edward-ap/miniradio/internal/platform/win/mediakeys"
+)
+
+// Session is a placeholder; every method is a no-op.
+type Session struct{}
+
+// Open is a stub that returns nil on non-Windows platforms.
+func Open(fw fyne.Window, onCommand func(mediakeys.Key)) *Session {
+	return nil
+}
+
+// SetPlaying is a no-op on non-Windows platforms.
+func (s *Session) SetPlaying(playing bool) {}
+
+// SetTrack is a no-op on non-Windows platforms.
+func (s *Session) SetTrack(title string) {}
+
+// SetStation is a no-op on non-Windows platforms.
+func (s *Session) SetStation(name string) {}
+
+// Close is a no-op on non-Windows platforms.
+func (s *Session) Close() {}
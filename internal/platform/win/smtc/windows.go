@@ -0,0 +1,413 @@
+//go:build windows
+
+// Package smtc publishes playback state to the Windows System Media Transport
+// Controls, so the OS media overlay and lock screen show the current station
+// and track, and their play/pause/stop buttons reach the app.
+package smtc
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"fyne.io/fyne/v2"
+
+	mediakeys "github.com/edward-ap/miniradio/internal/platform/win/mediakeys"
+	windowpos "github.com/edward-ap/miniradio/internal/platform/win/windowpos"
+)
+
+var (
+	combase                    = syscall.NewLazyDLL("combase.dll")
+	procRoInitialize           = combase.NewProc("RoInitialize")
+	procRoGetActivationFactory = combase.NewProc("RoGetActivationFactory")
+	procWindowsCreateString    = combase.NewProc("WindowsCreateString")
+	procWindowsDeleteString    = combase.NewProc("WindowsDeleteString")
+)
+
+type guid struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+var (
+	iidUnknown  = guid{0x00000000, 0x0000, 0x0000, [8]byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+	iidAgile    = guid{0x94ea2b94, 0xe9cc, 0x49e0, [8]byte{0xc0, 0xff, 0xee, 0x64, 0xca, 0x8f, 0x5b, 0x90}}
+	iidInterop  = guid{0xddb0472d, 0xc911, 0x4a1f, [8]byte{0x86, 0xd9, 0xdc, 0x3d, 0x71, 0xa9, 0x5f, 0x5a}}
+	iidControls = guid{0x99fa3ff4, 0x1742, 0x42a6, [8]byte{0x90, 0x2e, 0x08, 0x7d, 0x41, 0xf9, 0x65, 0xec}}
+	// ITypedEventHandler<SystemMediaTransportControls, ButtonPressedEventArgs>
+	iidButtonHandler = guid{0x0557e996, 0x7b23, 0x4bae, [8]byte{0xaa, 0x81, 0xea, 0x0d, 0x67, 0x11, 0x43, 0xa4}}
+)
+
+const (
+	roInitMultithreaded = 1
+	eNoInterface        = 0x80004002
+
+	mediaPlaybackTypeMusic = 1
+
+	statusClosed  = 0
+	statusStopped = 2
+	statusPlaying = 3
+
+	buttonPlay     = 0
+	buttonPause    = 1
+	buttonStop     = 2
+	buttonNext     = 6
+	buttonPrevious = 7
+)
+
+// vtable slots; IUnknown and IInspectable occupy 0…5.
+const (
+	slotRelease = 2
+
+	// ISystemMediaTransportControlsInterop
+	slotGetForWindow = 6
+
+	// ISystemMediaTransportControls
+	slotPutPlaybackStatus    = 7
+	slotGetDisplayUpdater    = 8
+	slotPutIsEnabled         = 11
+	slotPutIsPlayEnabled     = 13
+	slotPutIsStopEnabled     = 15
+	slotPutIsPauseEnabled    = 17
+	slotPutIsPreviousEnabled = 25
+	slotPutIsNextEnabled     = 27
+	slotAddButtonPressed     = 32
+	slotRemoveButtonPressed  = 33
+
+	// ISystemMediaTransportControlsDisplayUpdater
+	slotPutType          = 7
+	slotGetMusicProperty = 12
+	slotUpdate           = 17
+
+	// IMusicDisplayProperties
+	slotPutTitle  = 7
+	slotPutArtist = 11
+
+	// ISystemMediaTransportControlsButtonPressedEventArgs
+	slotGetButton = 6
+)
+
+// comObj is a raw COM interface pointer.
+type comObj struct {
+	vtbl *[64]uintptr
+}
+
+func (o *comObj) call(slot int, args ...uintptr) uintptr {
+	all := append([]uintptr{uintptr(unsafe.Pointer(o))}, args...)
+	hr, _, _ := syscall.SyscallN(o.vtbl[slot], all...)
+	return hr
+}
+
+func (o *comObj) release() {
+	if o != nil {
+		o.call(slotRelease)
+	}
+}
+
+func failed(hr uintptr) bool { return int32(uint32(hr)) < 0 }
+
+func hresultError(op string, hr uintptr) error {
+	return fmt.Errorf("%s: hresult 0x%08x", op, uint32(hr))
+}
+
+// Session owns the transport controls of one window. COM calls are made from
+// a dedicated OS thread; the exported methods only queue work for it, so they
+// are safe to call from any goroutine and on a nil Session.
+type Session struct {
+	mu     sync.Mutex
+	calls  chan func()
+	closed bool
+
+	// owned by the worker thread
+	controls *comObj
+	updater  *comObj
+	music    *comObj
+	token    int64
+	handler  *buttonHandler
+	title    string
+	station  string
+}
+
+// Open registers a transport controls session for the window and routes the
+// OS play/pause/stop/next/previous buttons to onCommand. It returns nil when
+// the native window is not available yet or the OS lacks SMTC.
+func Open(w fyne.Window, onCommand func(mediakeys.Key)) *Session {
+	var hwnd uintptr
+	if !windowpos.WithNativeHWND(w, func(h uintptr) bool {
+		hwnd = h
+		return true
+	}) {
+		return nil
+	}
+	if procRoGetActivationFactory.Find() != nil {
+		return nil
+	}
+	s := &Session{calls: make(chan func(), 16)}
+	ready := make(chan bool, 1)
+	go s.run(hwnd, onCommand, ready)
+	if !<-ready {
+		return nil
+	}
+	return s
+}
+
+func (s *Session) run(hwnd uintptr, onCommand func(mediakeys.Key), ready chan<- bool) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	// S_FALSE or RPC_E_CHANGED_MODE both leave a usable apartment.
+	procRoInitialize.Call(roInitMultithreaded)
+	if err := s.init(hwnd, onCommand); err != nil {
+		fyne.LogError("SMTC unavailable", err)
+		s.teardown()
+		ready <- false
+		return
+	}
+	ready <- true
+	for f := range s.calls {
+		f()
+	}
+	s.teardown()
+}
+
+func (s *Session) init(hwnd uintptr, onCommand func(mediakeys.Key)) error {
+	factory, err := activationFactory("Windows.Media.SystemMediaTransportControls", &iidInterop)
+	if err != nil {
+		return err
+	}
+	defer factory.release()
+	if hr := factory.call(slotGetForWindow, hwnd, uintptr(unsafe.Pointer(&iidControls)), uintptr(unsafe.Pointer(&s.controls))); failed(hr) {
+		s.controls = nil
+		return hresultError("GetForWindow", hr)
+	}
+	c := s.controls
+	for _, slot := range []int{slotPutIsEnabled, slotPutIsPlayEnabled, slotPutIsPauseEnabled, slotPutIsStopEnabled, slotPutIsNextEnabled, slotPutIsPreviousEnabled} {
+		c.call(slot, 1)
+	}
+	if hr := c.call(slotGetDisplayUpdater, uintptr(unsafe.Pointer(&s.updater))); failed(hr) {
+		s.updater = nil
+		return hresultError("get_DisplayUpdater", hr)
+	}
+	s.updater.call(slotPutType, mediaPlaybackTypeMusic)
+	if hr := s.updater.call(slotGetMusicProperty, uintptr(unsafe.Pointer(&s.music))); failed(hr) {
+		s.music = nil
+		return hresultError("get_MusicProperties", hr)
+	}
+	s.handler = newButtonHandler(onCommand)
+	if hr := c.call(slotAddButtonPressed, uintptr(unsafe.Pointer(s.handler)), uintptr(unsafe.Pointer(&s.token))); failed(hr) {
+		s.handler = nil
+		return hresultError("add_ButtonPressed", hr)
+	}
+	c.call(slotPutPlaybackStatus, statusStopped)
+	return nil
+}
+
+func (s *Session) teardown() {
+	if s.controls != nil {
+		if s.handler != nil {
+			s.controls.call(slotRemoveButtonPressed, uintptr(s.token))
+		}
+		s.controls.call(slotPutPlaybackStatus, statusClosed)
+	}
+	s.music.release()
+	s.updater.release()
+	s.controls.release()
+	s.music, s.updater, s.controls = nil, nil, nil
+}
+
+// post queues f for the worker thread, dropping it after Close.
+func (s *Session) post(f func()) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.calls <- f:
+	default:
+		// the OS overlay is best effort; never stall the caller
+	}
+}
+
+// SetPlaying reflects the playback state in the OS overlay.
+func (s *Session) SetPlaying(playing bool) {
+	s.post(func() {
+		status := uintptr(statusStopped)
+		if playing {
+			status = statusPlaying
+		}
+		s.controls.call(slotPutPlaybackStatus, status)
+	})
+}
+
+// SetTrack updates the displayed track title.
+func (s *Session) SetTrack(title string) {
+	s.post(func() {
+		s.title = title
+		s.updateDisplay()
+	})
+}
+
+// SetStation updates the station name, shown as the artist line.
+func (s *Session) SetStation(name string) {
+	s.post(func() {
+		s.station = name
+		s.updateDisplay()
+	})
+}
+
+// Close unregisters the session and releases the COM objects.
+func (s *Session) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.calls)
+	}
+}
+
+func (s *Session) updateDisplay() {
+	title := s.title
+	if title == "" {
+		title = s.station
+	}
+	setHString(s.music, slotPutTitle, title)
+	setHString(s.music, slotPutArtist, s.station)
+	s.updater.call(slotUpdate)
+}
+
+// activationFactory returns the factory of a WinRT runtime class queried for
+// iid.
+func activationFactory(class string, iid *guid) (*comObj, error) {
+	h, err := newHString(class)
+	if err != nil {
+		return nil, err
+	}
+	defer deleteHString(h)
+	var f *comObj
+	if hr, _, _ := procRoGetActivationFactory.Call(h, uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&f))); failed(hr) {
+		return nil, hresultError("RoGetActivationFactory", hr)
+	}
+	return f, nil
+}
+
+func newHString(s string) (uintptr, error) {
+	if s == "" {
+		return 0, nil // the null HSTRING is the empty string
+	}
+	u, err := syscall.UTF16FromString(s)
+	if err != nil {
+		return 0, err
+	}
+	var h uintptr
+	if hr, _, _ := procWindowsCreateString.Call(uintptr(unsafe.Pointer(&u[0])), uintptr(len(u)-1), uintptr(unsafe.Pointer(&h))); failed(hr) {
+		return 0, hresultError("WindowsCreateString", hr)
+	}
+	return h, nil
+}
+
+func deleteHString(h uintptr) {
+	if h != 0 {
+		procWindowsDeleteString.Call(h)
+	}
+}
+
+// setHString calls a string property setter on o.
+func setHString(o *comObj, slot int, v string) {
+	h, err := newHString(v)
+	if err != nil {
+		return
+	}
+	defer deleteHString(h)
+	o.call(slot, h)
+}
+
+// buttonHandler is a minimal COM object implementing the ButtonPressed
+// event handler. The Session keeps it reachable for as long as it is
+// registered.
+type buttonHandler struct {
+	vtbl *buttonHandlerVtbl
+	refs int32
+	fn   func(mediakeys.Key)
+}
+
+type buttonHandlerVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	Invoke         uintptr
+}
+
+var (
+	handlerVtblOnce sync.Once
+	handlerVtbl     *buttonHandlerVtbl
+)
+
+func newButtonHandler(fn func(mediakeys.Key)) *buttonHandler {
+	handlerVtblOnce.Do(func() {
+		handlerVtbl = &buttonHandlerVtbl{
+			QueryInterface: syscall.NewCallback(handlerQueryInterface),
+			AddRef:         syscall.NewCallback(handlerAddRef),
+			Release:        syscall.NewCallback(handlerRelease),
+			Invoke:         syscall.NewCallback(handlerInvoke),
+		}
+	})
+	return &buttonHandler{vtbl: handlerVtbl, refs: 1, fn: fn}
+}
+
+func handlerQueryInterface(this *buttonHandler, riid *guid, out **buttonHandler) uintptr {
+	switch *riid {
+	case iidUnknown, iidAgile, iidButtonHandler:
+		atomic.AddInt32(&this.refs, 1)
+		*out = this
+		return 0
+	}
+	*out = nil
+	return eNoInterface
+}
+
+func handlerAddRef(this *buttonHandler) uintptr {
+	return uintptr(atomic.AddInt32(&this.refs, 1))
+}
+
+func handlerRelease(this *buttonHandler) uintptr {
+	return uintptr(atomic.AddInt32(&this.refs, -1))
+}
+
+// handlerInvoke runs on a system thread; the app's handler is dispatched on
+// its own goroutine so the OS call returns immediately.
+func handlerInvoke(this *buttonHandler, sender, args *comObj) uintptr {
+	var button int32
+	if args == nil || failed(args.call(slotGetButton, uintptr(unsafe.Pointer(&button)))) {
+		return 0
+	}
+	var key mediakeys.Key
+	switch button {
+	case buttonPlay:
+		key = mediakeys.Play
+	case buttonPause:
+		key = mediakeys.Pause
+	case buttonStop:
+		key = mediakeys.Stop
+	case buttonNext:
+		key = mediakeys.Next
+	case buttonPrevious:
+		key = mediakeys.Previous
+	default:
+		return 0
+	}
+	if this.fn != nil {
+		go this.fn(key)
+	}
+	return 0
+}
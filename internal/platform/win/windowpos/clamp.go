@@ -0,0 +1,59 @@
+package windowpos
+
+// Rect is a screen rectangle in virtual-desktop pixels; Right and Bottom are
+// exclusive, as in the Win32 RECT.
+type Rect struct {
+	Left, Top, Right, Bottom int
+}
+
+func (r Rect) contains(x, y int) bool {
+	return x >= r.Left && x < r.Right && y >= r.Top && y < r.Bottom
+}
+
+// distance2 is the squared distance from (x, y) to the nearest point of r.
+func (r Rect) distance2(x, y int) int {
+	dx, dy := 0, 0
+	if x < r.Left {
+		dx = r.Left - x
+	} else if x >= r.Right {
+		dx = x - r.Right + 1
+	}
+	if y < r.Top {
+		dy = r.Top - y
+	} else if y >= r.Bottom {
+		dy = y - r.Bottom + 1
+	}
+	return dx*dx + dy*dy
+}
+
+// ClampToWorkAreas keeps a w×h window whose top-left corner was saved at
+// (x, y) reachable: when that corner lies in one of the monitor work areas the
+// position is returned unchanged, otherwise the window is moved into the
+// nearest work area, as far in as its size allows. With no areas the input is
+// returned as is.
+func ClampToWorkAreas(x, y, w, h int, areas []Rect) (int, int) {
+	if len(areas) == 0 {
+		return x, y
+	}
+	best := areas[0]
+	for _, a := range areas {
+		if a.contains(x, y) {
+			return x, y
+		}
+		if a.distance2(x, y) < best.distance2(x, y) {
+			best = a
+		}
+	}
+	return clampAxis(x, w, best.Left, best.Right), clampAxis(y, h, best.Top, best.Bottom)
+}
+
+// clampAxis fits [v, v+size) into [lo, hi), preferring lo when it cannot fit.
+func clampAxis(v, size, lo, hi int) int {
+	if v+size > hi {
+		v = hi - size
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
@@ -0,0 +1,28 @@
+package windowpos
+
+import "testing"
+
+func TestClampToWorkAreas(t *testing.T) {
+	// laptop panel plus an external monitor to its right
+	areas := []Rect{{0, 0, 1920, 1040}, {1920, 0, 4480, 1400}}
+	tests := []struct {
+		name         string
+		x, y         int
+		wantX, wantY int
+	}{
+		{"on laptop", 100, 100, 100, 100},
+		{"on external", 3000, 500, 3000, 500},
+		{"external unplugged", 5000, 600, 4480 - 400, 600},
+		{"above desktop", 200, -300, 200, 0},
+		{"left of desktop", -900, 1000, 0, 1040 - 120},
+	}
+	for _, tt := range tests {
+		x, y := ClampToWorkAreas(tt.x, tt.y, 400, 120, areas)
+		if x != tt.wantX || y != tt.wantY {
+			t.Errorf("%s: got (%d,%d), want (%d,%d)", tt.name, x, y, tt.wantX, tt.wantY)
+		}
+	}
+	if x, y := ClampToWorkAreas(-50, -50, 400, 120, nil); x != -50 || y != -50 {
+		t.Errorf("no monitors: got (%d,%d)", x, y)
+	}
+}
@@ -1,6 +1,7 @@
-//go:build !windows
+//go:build !windows && (!linux || wayland)
 
-// Package windowpos is compiled as a no-op on non-Windows platforms.
+// Package windowpos is compiled as a no-op where neither Win32 nor X11
+// positioning is available, such as macOS and Wayland builds.
 package windowpos
 
 import "fyne.io/fyne/v2"
@@ -15,3 +16,26 @@ func GetWindowPosition(fw fyne.Window) (int, int, bool) {
 func ApplyWindowPosition(fw fyne.Window, x, y int) bool {
 	return false
 }
+
+// ApplyWindowPositionVisible is a stub that returns false on non-Windows
+// platforms.
+func ApplyWindowPositionVisible(fw fyne.Window, x, y int) bool {
+	return false
+}
+
+// MonitorWorkAreas is a stub that reports no monitors on non-Windows
+// platforms.
+func MonitorWorkAreas() []Rect {
+	return nil
+}
+
+// CenterOnPrimaryMonitor is a stub that returns false on non-Windows
+// platforms; callers fall back to fyne's CenterOnScreen.
+func CenterOnPrimaryMonitor(fw fyne.Window) bool {
+	return false
+}
+
+// WithNativeHWND is a stub that returns false on non-Windows platforms.
+func WithNativeHWND(fw fyne.Window, fn func(hwnd uintptr) bool) bool {
+	return false
+}
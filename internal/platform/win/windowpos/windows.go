@@ -17,6 +17,9 @@ var (
 	user32            = syscall.NewLazyDLL("user32.dll")
 	procGetWindowRect = user32.NewProc("GetWindowRect")
 	procSetWindowPos  = user32.NewProc("SetWindowPos")
+	procSystemParams  = user32.NewProc("SystemParametersInfoW")
+	procEnumMonitors  = user32.NewProc("EnumDisplayMonitors")
+	procMonitorInfo   = user32.NewProc("GetMonitorInfoW")
 )
 
 type winRect struct {
@@ -30,8 +33,51 @@ const (
 	swpNOSIZE     = 0x0001
 	swpNOZORDER   = 0x0004
 	swpNOACTIVATE = 0x0010
+
+	spiGETWORKAREA = 0x0030
+)
+
+// monitorInfo is MONITORINFO.
+type monitorInfo struct {
+	Size    uint32
+	Monitor winRect
+	Work    winRect
+	Flags   uint32
+}
+
+var (
+	// monitorsMu guards workAreas while EnumDisplayMonitors fills it through
+	// the package-level callback (callbacks are a limited resource in Go, so
+	// only one is ever created).
+	monitorsMu sync.Mutex
+	workAreas  []Rect
+	monitorCb  = syscall.NewCallback(func(hMonitor, _, _, _ uintptr) uintptr {
+		mi := monitorInfo{Size: uint32(unsafe.Sizeof(monitorInfo{}))}
+		if ret, _, _ := procMonitorInfo.Call(hMonitor, uintptr(unsafe.Pointer(&mi))); ret != 0 {
+			workAreas = append(workAreas, Rect{
+				Left: int(mi.Work.Left), Top: int(mi.Work.Top),
+				Right: int(mi.Work.Right), Bottom: int(mi.Work.Bottom),
+			})
+		}
+		return 1
+	})
 )
 
+// MonitorWorkAreas lists the work area (desktop minus taskbars) of every
+// connected monitor.
+func MonitorWorkAreas() []Rect {
+	monitorsMu.Lock()
+	defer monitorsMu.Unlock()
+	workAreas = nil
+	if ret, _, err := procEnumMonitors.Call(0, 0, monitorCb, 0); ret == 0 {
+		if err != syscall.Errno(0) {
+			fyne.LogError("EnumDisplayMonitors failed", err)
+		}
+		return nil
+	}
+	return append([]Rect(nil), workAreas...)
+}
+
 // GetWindowPosition returns the top-left corner of the native HWND associated
 // with the provided fyne window. The bool indicates whether a coordinate was
 // successfully retrieved.
@@ -68,6 +114,70 @@ func ApplyWindowPosition(w fyne.Window, x, y int) bool {
 	})
 }
 
+// ApplyWindowPositionVisible is ApplyWindowPosition for saved coordinates: if
+// (x, y) is no longer on any connected monitor, for example after undocking,
+// the window is moved into the nearest monitor's work area instead.
+func ApplyWindowPositionVisible(w fyne.Window, x, y int) bool {
+	return withNativeHWND(w, func(hwnd uintptr) bool {
+		var win winRect
+		if ret, _, err := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&win))); ret == 0 {
+			if err != syscall.Errno(0) {
+				fyne.LogError("GetWindowRect failed", err)
+			}
+			return false
+		}
+		x, y := ClampToWorkAreas(x, y, int(win.Right-win.Left), int(win.Bottom-win.Top), MonitorWorkAreas())
+		ret, _, err := procSetWindowPos.Call(hwnd, 0, uintptr(int32(x)), uintptr(int32(y)), 0, 0, swpNOSIZE|swpNOZORDER|swpNOACTIVATE)
+		if ret == 0 {
+			if err != syscall.Errno(0) {
+				fyne.LogError("SetWindowPos failed", err)
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// CenterOnPrimaryMonitor moves the native HWND to the middle of the primary
+// monitor's work area, keeping its current size. It works regardless of where
+// the window currently sits, including fully off-screen. Returns true on
+// success.
+func CenterOnPrimaryMonitor(w fyne.Window) bool {
+	return withNativeHWND(w, func(hwnd uintptr) bool {
+		var win, work winRect
+		if ret, _, err := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&win))); ret == 0 {
+			if err != syscall.Errno(0) {
+				fyne.LogError("GetWindowRect failed", err)
+			}
+			return false
+		}
+		if ret, _, err := procSystemParams.Call(spiGETWORKAREA, 0, uintptr(unsafe.Pointer(&work)), 0); ret == 0 {
+			if err != syscall.Errno(0) {
+				fyne.LogError("SystemParametersInfo failed", err)
+			}
+			return false
+		}
+		width := win.Right - win.Left
+		height := win.Bottom - win.Top
+		x := work.Left + (work.Right-work.Left-width)/2
+		y := work.Top + (work.Bottom-work.Top-height)/2
+		if x < work.Left {
+			x = work.Left
+		}
+		if y < work.Top {
+			y = work.Top
+		}
+		ret, _, err := procSetWindowPos.Call(hwnd, 0, uintptr(x), uintptr(y), 0, 0, swpNOSIZE|swpNOZORDER|swpNOACTIVATE)
+		if ret == 0 {
+			if err != syscall.Errno(0) {
+				fyne.LogError("SetWindowPos failed", err)
+			}
+			return false
+		}
+		return true
+	})
+}
+
 // withNativeHWND obtains the underlying Windows HWND for the provided fyne
 // window and executes fn on the GUI thread. It waits for completion before
 // returning and passes through the handler's boolean result.
@@ -92,3 +202,11 @@ func withNativeHWND(w fyne.Window, fn func(hwnd uintptr) bool) bool {
 	wg.Wait()
 	return success
 }
+
+// WithNativeHWND runs fn with the window's HWND on the GUI thread and reports
+// its result. It lets sibling platform packages reach the native handle
+// without duplicating the driver plumbing; it must not be called from the UI
+// thread.
+func WithNativeHWND(w fyne.Window, fn func(hwnd uintptr) bool) bool {
+	return withNativeHWND(w, fn)
+}
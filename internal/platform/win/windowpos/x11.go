@@ -0,0 +1,195 @@
+//go:build linux && !wayland
+
+// Package windowpos provides helpers to persist and restore native window
+// coordinates on X11 builds where fyne does not expose them directly.
+package windowpos
+
+/*
+#cgo LDFLAGS: -lX11
+#include <X11/Xlib.h>
+#include <X11/Xatom.h>
+
+// cardinals reads up to max CARDINAL values of prop on w into out and returns
+// how many were read.
+static int cardinals(Display *d, Window w, const char *prop, long *out, int max) {
+	Atom atom = XInternAtom(d, prop, True);
+	if (atom == None) {
+		return 0;
+	}
+	Atom type;
+	int format;
+	unsigned long n, after;
+	unsigned char *data = NULL;
+	if (XGetWindowProperty(d, w, atom, 0, max, False, XA_CARDINAL, &type, &format, &n, &after, &data) != Success || data == NULL) {
+		return 0;
+	}
+	int got = 0;
+	if (format == 32) {
+		for (; got < (int)n && got < max; got++) {
+			out[got] = ((long *)data)[got];
+		}
+	}
+	XFree(data);
+	return got;
+}
+
+// clientOrigin stores the root-relative top-left corner of w's client area.
+static int clientOrigin(Display *d, Window w, int *x, int *y) {
+	Window child;
+	return XTranslateCoordinates(d, w, DefaultRootWindow(d), 0, 0, x, y, &child);
+}
+
+// outerSize stores w's size including the decorations the window manager
+// reports in _NET_FRAME_EXTENTS.
+static int outerSize(Display *d, Window w, int *width, int *height) {
+	XWindowAttributes attrs;
+	if (!XGetWindowAttributes(d, w, &attrs)) {
+		return 0;
+	}
+	long ext[4] = {0, 0, 0, 0};
+	cardinals(d, w, "_NET_FRAME_EXTENTS", ext, 4);
+	*width = attrs.width + (int)(ext[0] + ext[1]);
+	*height = attrs.height + (int)(ext[2] + ext[3]);
+	return 1;
+}
+
+// workArea stores the current desktop's _NET_WORKAREA, or the whole root
+// window when the window manager does not publish one.
+static void workArea(Display *d, long out[4]) {
+	Window root = DefaultRootWindow(d);
+	long desktop = 0;
+	cardinals(d, root, "_NET_CURRENT_DESKTOP", &desktop, 1);
+	long areas[4 * 32];
+	int n = cardinals(d, root, "_NET_WORKAREA", areas, 4 * 32);
+	if (desktop >= 0 && (desktop+1)*4 <= n) {
+		for (int i = 0; i < 4; i++) {
+			out[i] = areas[desktop*4 + i];
+		}
+		return;
+	}
+	out[0] = 0;
+	out[1] = 0;
+	out[2] = DisplayWidth(d, DefaultScreen(d));
+	out[3] = DisplayHeight(d, DefaultScreen(d));
+}
+*/
+import "C"
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+)
+
+// GetWindowPosition returns the top-left corner of the client area of the X11
+// window behind the provided fyne window. GLFW marks its windows with
+// StaticGravity, so this is also the point ApplyWindowPosition moves to and
+// a saved position round-trips regardless of the window manager's frame. The
+// bool indicates whether a coordinate was successfully retrieved.
+func GetWindowPosition(w fyne.Window) (int, int, bool) {
+	var px, py int
+	ok := withX11Window(w, func(d *C.Display, win C.Window) bool {
+		var x, y C.int
+		if C.clientOrigin(d, win, &x, &y) == 0 {
+			fyne.LogError("XTranslateCoordinates failed", nil)
+			return false
+		}
+		px, py = int(x), int(y)
+		return true
+	})
+	return px, py, ok
+}
+
+// ApplyWindowPosition asks the window manager to move the X11 window to the
+// given coordinates without resizing it. Returns true once the request has
+// been sent; window managers are free to adjust it.
+func ApplyWindowPosition(w fyne.Window, x, y int) bool {
+	return withX11Window(w, func(d *C.Display, win C.Window) bool {
+		C.XMoveWindow(d, win, C.int(x), C.int(y))
+		C.XSync(d, C.False)
+		return true
+	})
+}
+
+// ApplyWindowPositionVisible is ApplyWindowPosition for saved coordinates: if
+// (x, y) is outside the desktop's work area, for example after a monitor was
+// disconnected, the window is moved back inside it instead.
+func ApplyWindowPositionVisible(w fyne.Window, x, y int) bool {
+	return withX11Window(w, func(d *C.Display, win C.Window) bool {
+		var width, height C.int
+		if C.outerSize(d, win, &width, &height) == 0 {
+			fyne.LogError("XGetWindowAttributes failed", nil)
+			return false
+		}
+		x, y := ClampToWorkAreas(x, y, int(width), int(height), []Rect{displayWorkArea(d)})
+		C.XMoveWindow(d, win, C.int(x), C.int(y))
+		C.XSync(d, C.False)
+		return true
+	})
+}
+
+// MonitorWorkAreas returns the work area of the current desktop. X11 window
+// managers publish a single _NET_WORKAREA spanning all monitors, so the list
+// holds one rectangle, or none when no display can be opened.
+func MonitorWorkAreas() []Rect {
+	d := C.XOpenDisplay(nil)
+	if d == nil {
+		return nil
+	}
+	defer C.XCloseDisplay(d)
+	return []Rect{displayWorkArea(d)}
+}
+
+func displayWorkArea(d *C.Display) Rect {
+	var area [4]C.long
+	C.workArea(d, &area[0])
+	return Rect{
+		Left: int(area[0]), Top: int(area[1]),
+		Right: int(area[0] + area[2]), Bottom: int(area[1] + area[3]),
+	}
+}
+
+// CenterOnPrimaryMonitor returns false on X11; callers fall back to fyne's
+// CenterOnScreen, which the window manager already honours there.
+func CenterOnPrimaryMonitor(fw fyne.Window) bool {
+	return false
+}
+
+// WithNativeHWND returns false on X11, which has no HWND; the Windows media
+// key and SMTC integrations stay inactive.
+func WithNativeHWND(fw fyne.Window, fn func(hwnd uintptr) bool) bool {
+	return false
+}
+
+// withX11Window obtains the X11 window handle for the provided fyne window and
+// executes fn on the GUI thread with a private display connection, so Xlib is
+// never shared with GLFW's own connection. It waits for completion and passes
+// through fn's result. Under Wayland the driver hands out no X11 context and
+// the call reports failure.
+func withX11Window(w fyne.Window, fn func(d *C.Display, win C.Window) bool) bool {
+	nw, ok := w.(driver.NativeWindow)
+	if !ok {
+		return false
+	}
+	var (
+		success bool
+		wg      sync.WaitGroup
+	)
+	wg.Add(1)
+	nw.RunNative(func(ctx any) {
+		defer wg.Done()
+		xCtx, ok := ctx.(driver.X11WindowContext)
+		if !ok || xCtx.WindowHandle == 0 {
+			return
+		}
+		d := C.XOpenDisplay(nil)
+		if d == nil {
+			return
+		}
+		defer C.XCloseDisplay(d)
+		success = fn(d, C.Window(xCtx.WindowHandle))
+	})
+	wg.Wait()
+	return success
+}
@@ -0,0 +1,78 @@
+package player
+
+import (
+	"context"
+
+	metadata "github.com/edward-ap/miniradio/internal/metadata"
+)
+
+// SetCoverArtLookup turns MusicBrainz / Cover Art Archive lookups on or off.
+// When on, "Artist - Title" tracks whose stream sends no artwork are looked up
+// in the background; the result reaches OnArtwork and CurrentAlbum once found.
+// It applies from the next title.
+func (pl *Player) SetCoverArtLookup(on bool) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if on == (pl.enricher != nil) {
+		return
+	}
+	pl.enricher = nil
+	if on {
+		pl.enricher = metadata.NewEnricher(pl.metaOpts)
+	}
+}
+
+// CurrentArtwork returns the cover art URL of the current track, from the
+// stream itself or a cover art lookup, or "".
+func (pl *Player) CurrentArtwork() string {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.artwork
+}
+
+// CurrentAlbum returns the album a cover art lookup matched the current track
+// to, or "".
+func (pl *Player) CurrentAlbum() string {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.album
+}
+
+// trackArtwork records the artwork for a title update: the stream's own when
+// it sends one, otherwise whatever e (nil when lookups are off) finds in the
+// background. Repeated updates of the same title reuse the earlier lookup.
+func (pl *Player) trackArtwork(ctx context.Context, title, streamArt string, e *metadata.Enricher, onArtwork func(string)) {
+	pl.mu.Lock()
+	repeat := title == pl.artTitle
+	pl.artTitle = title
+	pl.mu.Unlock()
+	if streamArt != "" || e == nil {
+		pl.setArtwork(title, streamArt, "", onArtwork)
+		return
+	}
+	if repeat {
+		return
+	}
+	pl.setArtwork(title, "", "", onArtwork)
+	go func() {
+		if t, ok := e.Lookup(ctx, title); ok && ctx.Err() == nil {
+			pl.setArtwork(title, t.ArtworkURL, t.Album, onArtwork)
+		}
+	}()
+}
+
+// setArtwork stores art and album unless another title has arrived since,
+// and reports a changed URL to onArtwork.
+func (pl *Player) setArtwork(title, art, album string, onArtwork func(string)) {
+	pl.mu.Lock()
+	if title != pl.artTitle {
+		pl.mu.Unlock()
+		return
+	}
+	changed := art != pl.artwork
+	pl.artwork, pl.album = art, album
+	pl.mu.Unlock()
+	if changed && onArtwork != nil {
+		onArtwork(art)
+	}
+}
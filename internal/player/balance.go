@@ -0,0 +1,91 @@
+package player
+
+import (
+	"fmt"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// balanceRouteAt is how far from centre the balance must be before playback
+// is routed to one side on runtimes without the stereo_pan filter.
+const balanceRouteAt = 0.5
+
+// SetBalance sets the stereo balance, from -1 (full left) through 0 (centre)
+// to +1 (full right); values outside that range are clamped. It is applied
+// with libVLC's stereo_pan filter, which like normalization is a media
+// option: the result reports whether a playing stream has to be loaded again
+// to hear the change. Runtimes without the filter fall back to libVLC's
+// stereo routing at once: past balanceRouteAt either way only that side's
+// channel is played on both speakers.
+func (pl *Player) SetBalance(pan float64) bool {
+	pan = clampBalance(pan)
+	pl.mu.Lock()
+	changed := pl.balance != pan
+	pl.balance = pan
+	panFilter := pl.stereoPan
+	playing := pl.isPlaying
+	pl.mu.Unlock()
+	if !changed {
+		return false
+	}
+	if panFilter {
+		return true
+	}
+	if playing {
+		_ = pl.applyBalance()
+	}
+	return false
+}
+
+// applyBalance pushes the stereo routing for the remembered balance to
+// libVLC; with the stereo_pan filter that is always normal stereo.
+func (pl *Player) applyBalance() error {
+	pl.mu.Lock()
+	mode := vlc.StereoModeNormal
+	if !pl.stereoPan {
+		mode = stereoModeFor(pl.balance)
+	}
+	pl.mu.Unlock()
+	pl.vlcMu.Lock()
+	defer pl.vlcMu.Unlock()
+	if pl.p == nil {
+		return fmt.Errorf("vlc player not initialized")
+	}
+	return pl.p.SetStereoMode(mode)
+}
+
+// stereoModeFor maps a balance value onto libVLC's stereo routing.
+func stereoModeFor(pan float64) vlc.StereoMode {
+	switch {
+	case pan <= -balanceRouteAt:
+		return vlc.StereoModeLeft
+	case pan >= balanceRouteAt:
+		return vlc.StereoModeRight
+	}
+	return vlc.StereoModeNormal
+}
+
+// hasAudioFilter reports whether the libVLC runtime ships the audio filter
+// module name; vlc must be initialised.
+func hasAudioFilter(name string) bool {
+	filters, err := vlc.ListAudioFilters()
+	if err != nil {
+		return false
+	}
+	for _, f := range filters {
+		if f != nil && f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func clampBalance(pan float64) float64 {
+	if pan < -1 {
+		return -1
+	}
+	if pan > 1 {
+		return 1
+	}
+	return pan
+}
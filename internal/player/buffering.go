@@ -0,0 +1,110 @@
+package player
+
+import (
+	"log"
+	"math"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+const (
+	// cacheWindow is the network cache libVLC fills before playing; it
+	// matches the network-caching option set in Init and newStreamMedia.
+	cacheWindow = 1500 * time.Millisecond
+	// bufferingQuiet is how long after the last MediaPlayerBuffering event
+	// the cache counts as full; libVLC raises them until it is.
+	bufferingQuiet = 300 * time.Millisecond
+	// bufferingPoll is how often buffering progress is reported.
+	bufferingPoll = 100 * time.Millisecond
+)
+
+// SetOnBuffering registers a callback for buffering progress in percent while
+// a stream connects or rebuffers; 100 means the cache is full again.
+func (pl *Player) SetOnBuffering(fn func(float64)) {
+	pl.mu.Lock()
+	pl.onBuffering = fn
+	pl.mu.Unlock()
+}
+
+// bufferMeter turns MediaPlayerBuffering events into progress. libvlc-go
+// hands event callbacks no payload, so libVLC's own cache percentage is out of
+// reach; progress is estimated from how long buffering has run against
+// cacheWindow, and the spell ends once the events stop.
+type bufferMeter struct {
+	start, last time.Time // first and latest event; zero when idle
+	reporting   bool      // a reportBuffering goroutine is running
+}
+
+// note records a buffering event at now and reports whether a reporter
+// needs to be started.
+func (b *bufferMeter) note(now time.Time) bool {
+	if b.start.IsZero() {
+		b.start = now
+	}
+	b.last = now
+	if b.reporting {
+		return false
+	}
+	b.reporting = true
+	return true
+}
+
+// percent estimates progress at now, below 100 until the spell is done.
+func (b *bufferMeter) percent(now time.Time) (pct float64, done bool) {
+	if b.start.IsZero() || now.Sub(b.last) >= bufferingQuiet {
+		*b = bufferMeter{}
+		return 100, true
+	}
+	pct = math.Floor(float64(now.Sub(b.start)) / float64(cacheWindow) * 100)
+	return math.Min(pct, 99), false
+}
+
+// idle ends the current spell, e.g. when playback stops; a running reporter
+// then sends 100 and exits.
+func (b *bufferMeter) idle() {
+	b.start, b.last = time.Time{}, time.Time{}
+}
+
+// attachBufferingEvents subscribes to p's buffering events. libVLC raises
+// them on its own thread, which must not block or call back into libVLC, so
+// the handler only records the time and reportBuffering does the rest.
+func (pl *Player) attachBufferingEvents(p *vlc.Player) {
+	em, err := p.EventManager()
+	if err != nil {
+		log.Printf("buffering events unavailable: %v", err)
+		return
+	}
+	if _, err := em.Attach(vlc.MediaPlayerBuffering, func(vlc.Event, interface{}) {
+		pl.mu.Lock()
+		start := p == pl.eventPlayer && pl.buffer.note(time.Now())
+		pl.mu.Unlock()
+		if start {
+			go pl.reportBuffering()
+		}
+	}, nil); err != nil {
+		log.Printf("buffering events unavailable: %v", err)
+	}
+}
+
+// reportBuffering sends progress to the buffering callback until the spell
+// ends, skipping repeated values.
+func (pl *Player) reportBuffering() {
+	t := time.NewTicker(bufferingPoll)
+	defer t.Stop()
+	last := -1.0
+	for {
+		pl.mu.Lock()
+		pct, done := pl.buffer.percent(time.Now())
+		cb := pl.onBuffering
+		pl.mu.Unlock()
+		if cb != nil && pct != last {
+			cb(pct)
+		}
+		if done {
+			return
+		}
+		last = pct
+		<-t.C
+	}
+}
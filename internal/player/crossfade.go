@@ -0,0 +1,144 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// CrossfadeTo switches to url without a gap: the new stream starts on a
+// second libVLC player at silence and is faded up across d while the current
+// one fades down, after which the old player is released. Only the new
+// stream's ICY watcher runs. When d is zero or nothing is playing it is a
+// plain Load followed by Play.
+func (pl *Player) CrossfadeTo(url string, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if d <= 0 || !pl.IsPlaying() {
+		if err := pl.Load(ctx, url); err != nil {
+			return err
+		}
+		return pl.Play()
+	}
+
+	pl.resetStreamState()
+	u := pl.streamURL(ctx, url)
+
+	pl.mu.Lock()
+	muted := pl.muted
+	pl.mu.Unlock()
+
+	pl.vlcMu.Lock()
+	next, err := vlc.NewPlayer()
+	if err != nil {
+		pl.vlcMu.Unlock()
+		return fmt.Errorf("new vlc player failed: %w", err)
+	}
+	pl.attachBufferingEvents(next)
+	pl.attachErrorEvents(next)
+	m, err := pl.newStreamMedia(u)
+	if err != nil {
+		next.Release()
+		pl.vlcMu.Unlock()
+		return err
+	}
+	if err := next.SetMedia(m); err != nil {
+		m.Release()
+		next.Release()
+		pl.vlcMu.Unlock()
+		return fmt.Errorf("set media failed: %w", err)
+	}
+	if pl.eq != nil {
+		_ = next.SetEqualizer(pl.eq)
+	}
+	_ = next.SetVolume(0)
+	if muted {
+		next.ToggleMute()
+	}
+	if err := next.Play(); err != nil {
+		next.Release()
+		m.Release()
+		pl.vlcMu.Unlock()
+		return fmt.Errorf("play failed: %w", err)
+	}
+	old, oldMedia := pl.p, pl.media
+	pl.p, pl.media, pl.stream = next, m, u
+	pl.vlcMuted = muted
+	pl.vlcMu.Unlock()
+
+	pl.mu.Lock()
+	pl.source = url
+	pl.eventPlayer = next
+	fctx := pl.startFadeLocked()
+	from, target := pl.outVolume, pl.volume
+	pl.outVolume = 0
+	device := pl.audioDevice
+	balance := pl.balance
+	cb := pl.onNow
+	pl.mu.Unlock()
+	if device != "" {
+		_ = pl.applyAudioDevice()
+	}
+	if balance != 0 {
+		_ = pl.applyBalance()
+	}
+
+	pl.xfadeWG.Add(1)
+	go pl.runCrossfade(fctx, old, oldMedia, from, target, d)
+
+	pl.parseInBackground(m)
+	if cb != nil {
+		cb("Streaming…")
+	}
+	pl.startICYWatcher(u)
+	pl.startMetaPoll()
+	pl.startSilenceWatch()
+	return nil
+}
+
+// runCrossfade moves the outgoing player from -> 0 and the current one
+// 0 -> to across d, then stops and releases the outgoing player. A cancelled
+// fade (new volume, Stop, another switch) retires the old player at once and
+// leaves the current level to whoever cancelled it.
+func (pl *Player) runCrossfade(ctx context.Context, old *vlc.Player, oldMedia *vlc.Media, from, to int, d time.Duration) {
+	defer pl.xfadeWG.Done()
+	defer func() {
+		pl.vlcMu.Lock()
+		_ = old.Stop()
+		old.Release()
+		if oldMedia != nil {
+			oldMedia.Release()
+		}
+		pl.vlcMu.Unlock()
+	}()
+	steps := int(d / fadeStep)
+	if steps < 1 {
+		steps = 1
+	}
+	for i := 1; i <= steps; i++ {
+		v := to * i / steps
+		pl.vlcMu.Lock()
+		if ctx.Err() != nil {
+			pl.vlcMu.Unlock()
+			return
+		}
+		_ = old.SetVolume(from - from*i/steps)
+		_ = pl.p.SetVolume(v)
+		pl.vlcMu.Unlock()
+		pl.mu.Lock()
+		pl.outVolume = v
+		pl.mu.Unlock()
+		if i == steps {
+			return
+		}
+		t := time.NewTimer(fadeStep)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
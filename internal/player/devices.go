@@ -0,0 +1,63 @@
+package player
+
+import "fmt"
+
+// AudioDevice is an output device libVLC can route playback to.
+type AudioDevice struct {
+	ID   string
+	Name string
+}
+
+// AudioDevices lists the output devices of the player's audio output module.
+// Some modules only enumerate once playback has started, so an empty list is
+// not an error.
+func (pl *Player) AudioDevices() ([]AudioDevice, error) {
+	pl.vlcMu.Lock()
+	defer pl.vlcMu.Unlock()
+	if pl.p == nil {
+		return nil, fmt.Errorf("vlc player not initialized")
+	}
+	list, err := pl.p.AudioOutputDevices()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AudioDevice, 0, len(list))
+	for _, d := range list {
+		if d == nil || d.Name == "" {
+			continue
+		}
+		name := d.Description
+		if name == "" {
+			name = d.Name
+		}
+		out = append(out, AudioDevice{ID: d.Name, Name: name})
+	}
+	return out, nil
+}
+
+// SetAudioDevice routes playback to the device with the given ID ("" = system
+// default). The choice is remembered and reapplied whenever playback starts,
+// since libVLC only creates its audio output once something plays.
+func (pl *Player) SetAudioDevice(id string) error {
+	pl.mu.Lock()
+	pl.audioDevice = id
+	playing := pl.isPlaying
+	pl.mu.Unlock()
+	if !playing {
+		return nil
+	}
+	return pl.applyAudioDevice()
+}
+
+// applyAudioDevice pushes the remembered device to libVLC.
+func (pl *Player) applyAudioDevice() error {
+	pl.mu.Lock()
+	id := pl.audioDevice
+	pl.mu.Unlock()
+	pl.vlcMu.Lock()
+	defer pl.vlcMu.Unlock()
+	if pl.p == nil {
+		return fmt.Errorf("vlc player not initialized")
+	}
+	return pl.p.SetAudioOutputDevice(id, "")
+}
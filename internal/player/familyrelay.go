@@ -0,0 +1,203 @@
+package player
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// relayTarget is a stream the family relay forwards to, and over which IP
+// version.
+type relayTarget struct {
+	url string
+	v   int
+}
+
+// familyRelay is a loopback HTTP relay libVLC opens streams through when an
+// IP version is forced: libVLC 3 has no option for it, and handing it a
+// resolved address would lose the Host header and, on https, SNI and the
+// certificate check. The relay dials the station over tcp4 or tcp6 with
+// the original URL and passes the response back untouched.
+type familyRelay struct {
+	ln    net.Listener
+	srv   *http.Server
+	proxy *httputil.ReverseProxy
+	// transports holds one transport per IP version
+	transports map[int]*http.Transport
+
+	mu      sync.Mutex
+	targets map[string]relayTarget // token -> target
+	tokens  map[relayTarget]string // target -> token
+}
+
+// newFamilyRelay starts a relay on a random loopback port.
+func newFamilyRelay() (*familyRelay, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	r := &familyRelay{
+		ln:         ln,
+		transports: map[int]*http.Transport{4: relayTransport(4), 6: relayTransport(6)},
+		targets:    make(map[string]relayTarget),
+		tokens:     make(map[relayTarget]string),
+	}
+	r.proxy = &httputil.ReverseProxy{
+		Rewrite:        r.rewrite,
+		Transport:      r,
+		ModifyResponse: r.modifyResponse,
+		// audio is passed on as it arrives
+		FlushInterval: -1,
+		ErrorLog:      log.Default(),
+	}
+	r.srv = &http.Server{Handler: r, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = r.srv.Serve(ln) }()
+	return r, nil
+}
+
+// relayTransport dials stations over IP version v only. Shoutcast v1 servers
+// answer "ICY 200 OK", which net/http does not parse, so the status line is
+// rewritten to HTTP/1.0 on the way in.
+func relayTransport(v int) *http.Transport {
+	network := "tcp4"
+	if v == 6 {
+		network = "tcp6"
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			c, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &icyStatusConn{Conn: c}, nil
+		},
+		ForceAttemptHTTP2:     false,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+		// libVLC asks for the encoding it wants itself
+		DisableCompression: true,
+	}
+}
+
+// register returns the relay URL libVLC should open to play u over IP
+// version v; the same stream always gets the same URL.
+func (r *familyRelay) register(u string, v int) string {
+	t := relayTarget{url: u, v: v}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[t]
+	if !ok {
+		var b [12]byte
+		_, _ = rand.Read(b[:])
+		token = hex.EncodeToString(b[:])
+		r.tokens[t] = token
+		r.targets[token] = t
+	}
+	return "http://" + r.ln.Addr().String() + "/" + token
+}
+
+// relayVersionKey keys the IP version of a relayed request in its context.
+type relayVersionKey struct{}
+
+// errUnknownRelayTarget is returned for a request rewrite did not aim at a
+// registered stream.
+var errUnknownRelayTarget = errors.New("relay: unknown stream")
+
+// target looks up the stream a relay request is for.
+func (r *familyRelay) target(req *http.Request) (relayTarget, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.targets[strings.TrimPrefix(req.URL.Path, "/")]
+	return t, ok
+}
+
+// ServeHTTP relays requests for registered streams only, so the port is no
+// open proxy for anything else on the machine.
+func (r *familyRelay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if _, ok := r.target(req); !ok {
+		http.NotFound(w, req)
+		return
+	}
+	r.proxy.ServeHTTP(w, req)
+}
+
+// rewrite points a relay request at its station, keeping libVLC's headers
+// but not the relay's Host.
+func (r *familyRelay) rewrite(pr *httputil.ProxyRequest) {
+	t, ok := r.target(pr.In)
+	if !ok {
+		return
+	}
+	u, err := url.Parse(t.url)
+	if err != nil {
+		return
+	}
+	pr.Out.URL = u
+	pr.Out.Host = ""
+	pr.Out = pr.Out.WithContext(context.WithValue(pr.Out.Context(), relayVersionKey{}, t.v))
+}
+
+// RoundTrip sends a rewritten request over the transport for its IP version.
+func (r *familyRelay) RoundTrip(req *http.Request) (*http.Response, error) {
+	v, _ := req.Context().Value(relayVersionKey{}).(int)
+	t, ok := r.transports[v]
+	if !ok {
+		return nil, errUnknownRelayTarget
+	}
+	return t.RoundTrip(req)
+}
+
+// modifyResponse sends redirects back through the relay, so the next hop is
+// dialed over the same IP version.
+func (r *familyRelay) modifyResponse(resp *http.Response) error {
+	if resp.Header.Get("Location") == "" || resp.Request == nil {
+		return nil
+	}
+	v, _ := resp.Request.Context().Value(relayVersionKey{}).(int)
+	loc, err := resp.Location()
+	if err != nil || (loc.Scheme != "http" && loc.Scheme != "https") {
+		return nil
+	}
+	resp.Header.Set("Location", r.register(loc.String(), v))
+	return nil
+}
+
+// close stops the relay and drops its connections.
+func (r *familyRelay) close() {
+	_ = r.srv.Close()
+	for _, t := range r.transports {
+		t.CloseIdleConnections()
+	}
+}
+
+// icyStatusConn turns a leading "ICY " status line into "HTTP/1.0 ".
+type icyStatusConn struct {
+	net.Conn
+	once sync.Once
+	r    io.Reader
+}
+
+func (c *icyStatusConn) Read(p []byte) (int, error) {
+	c.once.Do(func() {
+		br := bufio.NewReader(c.Conn)
+		c.r = br
+		if head, err := br.Peek(4); err == nil && string(head) == "ICY " {
+			_, _ = br.Discard(4)
+			c.r = io.MultiReader(strings.NewReader("HTTP/1.0 "), br)
+		}
+	})
+	return c.r.Read(p)
+}
@@ -0,0 +1,59 @@
+package player
+
+import "time"
+
+const (
+	// flapWindow is how long a title that was just replaced stays blocked;
+	// one that returns sooner is applied once the block runs out.
+	flapWindow = 3 * time.Second
+	// flapHistory is how many replaced titles flapGuard remembers.
+	flapHistory = 4
+)
+
+// flapGuard adds hysteresis to title stabilization. Some stations alternate
+// between two titles (a song and a slogan, say) every few seconds; each one
+// would pass the stable window in turn and the ticker would thrash. The guard
+// remembers when the last few distinct titles stopped being current and
+// holds one back until flapWindow has passed since it was replaced.
+type flapGuard struct {
+	recent []replacedTitle
+}
+
+// replacedTitle is a title and when another one took its place.
+type replacedTitle struct {
+	title string
+	at    time.Time
+}
+
+// wait returns how long title must still be held back at now, given window;
+// zero means it may become current.
+func (g *flapGuard) wait(title string, now time.Time, window time.Duration) time.Duration {
+	for _, r := range g.recent {
+		if r.title == title {
+			return max(window-now.Sub(r.at), 0)
+		}
+	}
+	return 0
+}
+
+// replaced notes that title stopped being current at now.
+func (g *flapGuard) replaced(title string, now time.Time) {
+	if title == "" {
+		return
+	}
+	for i, r := range g.recent {
+		if r.title == title {
+			g.recent = append(g.recent[:i], g.recent[i+1:]...)
+			break
+		}
+	}
+	if len(g.recent) >= flapHistory {
+		g.recent = g.recent[1:]
+	}
+	g.recent = append(g.recent, replacedTitle{title: title, at: now})
+}
+
+// reset forgets every title, e.g. when another stream starts.
+func (g *flapGuard) reset() {
+	g.recent = nil
+}
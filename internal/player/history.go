@@ -0,0 +1,117 @@
+package player
+
+import "time"
+
+// DefaultHistorySize is how many titles the player remembers unless
+// SetHistorySize says otherwise.
+const DefaultHistorySize = 50
+
+// TrackEntry is one stabilized title from the playback history.
+type TrackEntry struct {
+	Title   string    `json:"title"`
+	Station string    `json:"station,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// trackHistory is a fixed-capacity ring buffer of titles; once full the
+// oldest entry is overwritten.
+type trackHistory struct {
+	buf   []TrackEntry
+	start int
+	n     int
+}
+
+// add appends e, skipping an immediate repeat of the newest title (as sent
+// again after a reconnect).
+func (h *trackHistory) add(e TrackEntry) {
+	if len(h.buf) == 0 {
+		h.buf = make([]TrackEntry, DefaultHistorySize)
+	}
+	if h.n > 0 && h.buf[(h.start+h.n-1)%len(h.buf)].Title == e.Title {
+		return
+	}
+	if h.n < len(h.buf) {
+		h.buf[(h.start+h.n)%len(h.buf)] = e
+		h.n++
+		return
+	}
+	h.buf[h.start] = e
+	h.start = (h.start + 1) % len(h.buf)
+}
+
+// newestFirst returns a copy of the entries, most recent first.
+func (h *trackHistory) newestFirst() []TrackEntry {
+	out := make([]TrackEntry, h.n)
+	for i := 0; i < h.n; i++ {
+		out[i] = h.buf[(h.start+h.n-1-i)%len(h.buf)]
+	}
+	return out
+}
+
+// reset drops every entry, keeping the capacity.
+func (h *trackHistory) reset() {
+	clear(h.buf)
+	h.start, h.n = 0, 0
+}
+
+// resize changes the capacity, keeping the newest entries.
+func (h *trackHistory) resize(size int) {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	entries := h.newestFirst()
+	if len(entries) > size {
+		entries = entries[:size]
+	}
+	h.buf = make([]TrackEntry, size)
+	h.start, h.n = 0, 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		h.add(entries[i])
+	}
+}
+
+// History returns the recently played titles, most recent first.
+func (pl *Player) History() []TrackEntry {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.history.newestFirst()
+}
+
+// SetHistorySize sets how many titles History keeps (0 = DefaultHistorySize).
+func (pl *Player) SetHistorySize(n int) {
+	pl.mu.Lock()
+	pl.history.resize(n)
+	pl.mu.Unlock()
+}
+
+// RestoreHistory seeds the history with previously saved entries, given most
+// recent first as History returns them. Titles recorded since start-up stay
+// newer than the restored ones.
+func (pl *Player) RestoreHistory(entries []TrackEntry) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	current := pl.history.newestFirst()
+	pl.history.reset()
+	for i := len(entries) - 1; i >= 0; i-- {
+		pl.history.add(entries[i])
+	}
+	for i := len(current) - 1; i >= 0; i-- {
+		pl.history.add(current[i])
+	}
+}
+
+// ClearHistory forgets every recorded title.
+func (pl *Player) ClearHistory() {
+	pl.mu.Lock()
+	pl.history.reset()
+	pl.mu.Unlock()
+}
+
+// recordTitleLocked adds an applied title to the history, unless it was only
+// previewed. Callers hold pl.mu.
+func (pl *Player) recordTitleLocked(title string) {
+	if pl.previewing {
+		return
+	}
+	pl.history.add(TrackEntry{Title: title, Station: pl.station, At: time.Now()})
+}
@@ -0,0 +1,129 @@
+package player
+
+/*
+#cgo LDFLAGS: -lvlc
+#include <stdint.h>
+#include <stdlib.h>
+#include <vlc/vlc.h>
+
+extern void goLevelPlay(uintptr_t id, void *samples, unsigned count);
+
+static void level_play(void *opaque, const void *samples, unsigned count, int64_t pts) {
+	(void)pts;
+	goLevelPlay((uintptr_t)opaque, (void *)samples, count);
+}
+
+// level_instance opens the libVLC instance a level meter decodes with; it
+// never touches an output device, the samples come back to Go instead.
+static libvlc_instance_t *level_instance(void) {
+	const char *args[] = {"--quiet", "--no-video", "--no-stats"};
+	return libvlc_new(3, args);
+}
+
+// level_player plays media into level_play as mono 16-bit samples.
+static libvlc_media_player_t *level_player(libvlc_media_t *m, uintptr_t id, unsigned rate) {
+	libvlc_media_player_t *mp = libvlc_media_player_new_from_media(m);
+	if (mp == NULL) {
+		return NULL;
+	}
+	libvlc_audio_set_callbacks(mp, level_play, NULL, NULL, NULL, NULL, (void *)id);
+	libvlc_audio_set_format(mp, "S16N", rate, 1);
+	return mp;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// levelRate is the sample rate a level meter decodes at; a level does not
+// need more, and it keeps the resampling cheap.
+const levelRate = 8000
+
+// levelSinks maps the id a meter hands libVLC to its sink, so no Go pointer
+// is kept in C.
+var levelSinks struct {
+	sync.Mutex
+	next uintptr
+	m    map[uintptr]*levelSink
+}
+
+// levelMeter plays a stream on its own libVLC instance without output and
+// feeds the decoded audio to levels. libvlc-go has no audio callbacks, so
+// it talks to libVLC directly.
+type levelMeter struct {
+	id     uintptr
+	inst   *C.libvlc_instance_t
+	mp     *C.libvlc_media_player_t
+	levels *levelSink
+}
+
+// startLevelMeter starts metering u opened with the media options opts.
+func startLevelMeter(u string, opts []string) (*levelMeter, error) {
+	inst := C.level_instance()
+	if inst == nil {
+		return nil, errors.New("cannot create libVLC instance")
+	}
+	cu := C.CString(u)
+	m := C.libvlc_media_new_location(inst, cu)
+	C.free(unsafe.Pointer(cu))
+	if m == nil {
+		C.libvlc_release(inst)
+		return nil, errors.New("cannot create media")
+	}
+	for _, o := range opts {
+		co := C.CString(o)
+		C.libvlc_media_add_option(m, co)
+		C.free(unsafe.Pointer(co))
+	}
+
+	lm := &levelMeter{inst: inst, levels: &levelSink{}}
+	levelSinks.Lock()
+	if levelSinks.m == nil {
+		levelSinks.m = make(map[uintptr]*levelSink)
+	}
+	levelSinks.next++
+	lm.id = levelSinks.next
+	levelSinks.m[lm.id] = lm.levels
+	levelSinks.Unlock()
+
+	lm.mp = C.level_player(m, C.uintptr_t(lm.id), levelRate)
+	C.libvlc_media_release(m)
+	if lm.mp == nil {
+		lm.close()
+		return nil, errors.New("cannot create media player")
+	}
+	if C.libvlc_media_player_play(lm.mp) != 0 {
+		lm.close()
+		return nil, errors.New("cannot start playback")
+	}
+	return lm, nil
+}
+
+// close stops the meter and frees its libVLC instance.
+func (lm *levelMeter) close() {
+	levelSinks.Lock()
+	delete(levelSinks.m, lm.id)
+	levelSinks.Unlock()
+	if lm.mp != nil {
+		C.libvlc_media_player_stop(lm.mp)
+		C.libvlc_media_player_release(lm.mp)
+		lm.mp = nil
+	}
+	C.libvlc_release(lm.inst)
+}
+
+//export goLevelPlay
+func goLevelPlay(id C.uintptr_t, samples unsafe.Pointer, count C.unsigned) {
+	levelSinks.Lock()
+	s := levelSinks.m[uintptr(id)]
+	levelSinks.Unlock()
+	if s == nil || samples == nil || count == 0 {
+		return
+	}
+	s.feed(unsafe.Slice((*int16)(samples), int(count)), time.Now())
+}
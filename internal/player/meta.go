@@ -0,0 +1,40 @@
+package player
+
+// metaSource says how libVLC metadata can be read safely on a given runtime.
+type metaSource int
+
+const (
+	// metaNone: a runtime too old to read Meta safely; titles come from
+	// the ICY watcher alone.
+	metaNone metaSource = iota
+	// metaAfterParse: VLC 3 fills Meta through an explicit network parse and
+	// the strings are only reliable once the parse reports done. Runtimes
+	// that report no version get it too, as it never reads Meta early.
+	metaAfterParse
+	// metaWhilePlaying: VLC 4 moved network parsing into a parser service
+	// bound to the instance, so an explicit parse of a live stream never
+	// completes; the playing input updates Meta (e.g. NowPlaying) instead.
+	metaWhilePlaying
+)
+
+// metaSourceFor picks the metadata path for a libVLC major version.
+func metaSourceFor(vlcMajor int) metaSource {
+	switch {
+	case vlcMajor >= 4:
+		return metaWhilePlaying
+	case vlcMajor == 3, vlcMajor == 0:
+		return metaAfterParse
+	}
+	return metaNone
+}
+
+// metaReadable reports whether Meta may be read right now for src.
+func metaReadable(src metaSource, parseDone, playing bool) bool {
+	switch src {
+	case metaAfterParse:
+		return parseDone
+	case metaWhilePlaying:
+		return playing
+	}
+	return false
+}
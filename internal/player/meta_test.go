@@ -0,0 +1,655 @@
+package player
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edward-ap/miniradio/internal/metadata"
+)
+
+func TestMetaSourceFor(t *testing.T) {
+	tests := []struct {
+		major int
+		want  metaSource
+	}{
+		{major: 0, want: metaAfterParse},
+		{major: 1, want: metaNone},
+		{major: 2, want: metaNone},
+		{major: 3, want: metaAfterParse},
+		{major: 4, want: metaWhilePlaying},
+		{major: 5, want: metaWhilePlaying},
+	}
+	for _, tt := range tests {
+		if got := metaSourceFor(tt.major); got != tt.want {
+			t.Fatalf("metaSourceFor(%d) = %v, want %v", tt.major, got, tt.want)
+		}
+	}
+}
+
+func TestMetaReadable(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       metaSource
+		parseDone bool
+		playing   bool
+		want      bool
+	}{
+		{name: "v3 parsed", src: metaAfterParse, parseDone: true, want: true},
+		{name: "v3 playing but unparsed", src: metaAfterParse, playing: true, want: false},
+		{name: "v4 playing", src: metaWhilePlaying, playing: true, want: true},
+		{name: "v4 parsed but stopped", src: metaWhilePlaying, parseDone: true, want: false},
+		{name: "unknown", src: metaNone, parseDone: true, playing: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metaReadable(tt.src, tt.parseDone, tt.playing); got != tt.want {
+				t.Fatalf("metaReadable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVlcMajor(t *testing.T) {
+	cases := map[string]int{
+		"3.0.20 Vetinari":       3,
+		"4.0.0-dev Otto Chriek": 4,
+		"":                      0,
+		"garbage":               0,
+	}
+	for in, want := range cases {
+		if got := parseVlcMajor(in); got != want {
+			t.Fatalf("parseVlcMajor(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestAudioFilterOptions(t *testing.T) {
+	pl := &Player{}
+	if got := pl.audioFilterOptions(); got != nil {
+		t.Fatalf("no filters = %v", got)
+	}
+	pl.mono = true
+	if got := pl.audioFilterOptions(); len(got) != 1 || got[0] != ":audio-filter=mono" {
+		t.Fatalf("mono = %v", got)
+	}
+	// every new media (preset switch, crossfade) gets the whole chain
+	pl.normalize, pl.normLevel = true, 3
+	got := pl.audioFilterOptions()
+	if len(got) != 2 || got[0] != ":audio-filter=normvol:mono" || got[1] != ":norm-max-level=3.0" {
+		t.Fatalf("normalize+mono = %v", got)
+	}
+	// the balance joins the chain only where libVLC has stereo_pan
+	pl.normalize, pl.balance = false, -0.5
+	if got := pl.audioFilterOptions(); len(got) != 1 || got[0] != ":audio-filter=mono" {
+		t.Fatalf("balance without stereo_pan = %v", got)
+	}
+	pl.stereoPan = true
+	got = pl.audioFilterOptions()
+	if len(got) != 2 || got[0] != ":audio-filter=mono:stereo_pan" || got[1] != ":pan-control=0.25" {
+		t.Fatalf("mono+balance = %v", got)
+	}
+}
+
+func TestSetBalanceReportsReload(t *testing.T) {
+	pl := &Player{}
+	// without stereo_pan routing applies at once, stopped here, no reload
+	if pl.SetBalance(-0.7) || pl.balance != -0.7 {
+		t.Fatalf("routing fallback asked for a reload, balance %v", pl.balance)
+	}
+	pl.stereoPan = true
+	if !pl.SetBalance(3) || pl.balance != 1 {
+		t.Fatalf("pan filter change not reported, balance %v", pl.balance)
+	}
+	if pl.SetBalance(1) {
+		t.Fatal("unchanged balance reported")
+	}
+}
+
+func TestSetAudioFiltersReportsChange(t *testing.T) {
+	pl := &Player{}
+	if pl.SetAudioFilters(AudioFilters{}) {
+		t.Fatal("no filters reported as a change")
+	}
+	// both filters at once: one change, so callers reload once
+	if !pl.SetAudioFilters(AudioFilters{Normalize: true, Mono: true}) {
+		t.Fatal("normalize+mono not reported")
+	}
+	if pl.SetAudioFilters(AudioFilters{Normalize: true, NormalizeLevel: DefaultNormalizeLevel, Mono: true}) {
+		t.Fatal("default level reported as a change")
+	}
+	if !pl.SetAudioFilters(AudioFilters{Normalize: true, NormalizeLevel: 4, Mono: true}) {
+		t.Fatal("new level not reported")
+	}
+}
+
+func TestHandleICYTitleFlapping(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		emitted []string
+	)
+	pl := &Player{stableWindow: 20 * time.Millisecond, flapAfter: 200 * time.Millisecond}
+	pl.onNow = func(title string) {
+		mu.Lock()
+		emitted = append(emitted, title)
+		mu.Unlock()
+	}
+
+	// a normal change is applied once, after the stable window
+	pl.handleICYTitle("Artist - Song")
+	time.Sleep(60 * time.Millisecond)
+	pl.handleICYTitle("Artist - Next")
+	time.Sleep(60 * time.Millisecond)
+	mu.Lock()
+	if len(emitted) != 2 || emitted[0] != "Artist - Song" || emitted[1] != "Artist - Next" {
+		t.Fatalf("normal changes emitted %q", emitted)
+	}
+	emitted = nil
+	mu.Unlock()
+
+	// A, B, A, B... each held longer than the stable window
+	const changes = 30
+	for i := 0; i < changes; i++ {
+		if i%2 == 0 {
+			pl.handleICYTitle("Ad break")
+		} else {
+			pl.handleICYTitle("Artist - Next")
+		}
+		time.Sleep(40 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if n := len(emitted); n == 0 || n > changes/3 {
+		t.Fatalf("flapping titles emitted %d times over %d changes: %q", n, changes, emitted)
+	}
+}
+
+func TestFlapGuardAppliesReturningTitleLater(t *testing.T) {
+	emitted := make(chan string, 8)
+	pl := &Player{stableWindow: 20 * time.Millisecond, flapAfter: 150 * time.Millisecond}
+	pl.onNow = func(title string) { emitted <- title }
+
+	// A, B, then A sent once: A is held back, then applied
+	for _, title := range []string{"Artist - A", "Artist - B", "Artist - A"} {
+		pl.handleICYTitle(title)
+		select {
+		case got := <-emitted:
+			if got != title {
+				t.Fatalf("applied %q, want %q", got, title)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%q never applied", title)
+		}
+	}
+
+	// with no stable window titles apply at once
+	pl = &Player{flapAfter: time.Hour}
+	pl.onNow = func(title string) { emitted <- title }
+	for _, title := range []string{"Artist - A", "Artist - B", "Artist - A"} {
+		pl.handleICYTitle(title)
+		if got := <-emitted; got != title {
+			t.Fatalf("applied %q, want %q", got, title)
+		}
+	}
+}
+
+func TestStaleTitleClears(t *testing.T) {
+	emitted := make(chan string, 8)
+	pl := &Player{staleAfter: 80 * time.Millisecond}
+	pl.onNow = func(title string) { emitted <- title }
+
+	pl.handleICYTitle("Artist - Song")
+	if got := <-emitted; got != "Artist - Song" {
+		t.Fatalf("applied %q", got)
+	}
+	// repeats of the title keep it fresh
+	for i := 0; i < 4; i++ {
+		time.Sleep(40 * time.Millisecond)
+		pl.handleICYTitle("Artist - Song")
+	}
+	if pl.CurrentTitle() != "Artist - Song" {
+		t.Fatal("repeated title went stale")
+	}
+	select {
+	case got := <-emitted:
+		if got != "Streaming…" || pl.CurrentTitle() != "" {
+			t.Fatalf("stale title replaced by %q, current %q", got, pl.CurrentTitle())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stale title never cleared")
+	}
+
+	// the station name takes its place once known; the same song can return
+	pl.mu.Lock()
+	pl.station = "Radio X"
+	pl.mu.Unlock()
+	pl.handleICYTitle("Artist - Song")
+	if got := <-emitted; got != "Artist - Song" {
+		t.Fatalf("returning title %q", got)
+	}
+	if got := <-emitted; got != "Radio X" {
+		t.Fatalf("stale title replaced by %q, want station name", got)
+	}
+}
+
+func TestStallMeter(t *testing.T) {
+	var m stallMeter
+	start := time.Unix(0, 0)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+	timeout := 5 * time.Second
+
+	samples := []struct {
+		sec, played int
+		want        bool
+	}{
+		{0, 10, false},
+		{1, 20, false},
+		{3, 20, false},
+		{6, 20, true}, // no buffers played since second 1
+		{7, 20, false},
+		{8, 25, false}, // audio back: re-armed
+		{12, 25, false},
+		{13, 25, true},
+	}
+	for _, s := range samples {
+		if got := m.observe(s.played, at(s.sec), timeout); got != s.want {
+			t.Fatalf("observe(%d) at %ds = %v, want %v", s.played, s.sec, got, s.want)
+		}
+	}
+}
+
+func TestLevelSink(t *testing.T) {
+	var s levelSink
+	start := time.Unix(0, 0)
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+	timeout := 5 * time.Second
+	loud := make([]int16, 800)
+	for i := range loud {
+		loud[i] = 8000
+		if i%2 == 1 {
+			loud[i] = -8000
+		}
+	}
+	quiet := make([]int16, 800)       // digital silence
+	hiss := []int16{20, -20, 20, -20} // about -64 dBFS
+
+	if s.silent(at(10), timeout) {
+		t.Fatal("silent before any samples arrived")
+	}
+	steps := []struct {
+		sec     int
+		samples []int16
+		want    bool
+	}{
+		{0, loud, false},
+		{1, quiet, false},
+		{4, hiss, false},
+		{5, quiet, true}, // below the floor since the loud block at second 0
+		{6, quiet, false},
+		{8, loud, false}, // level back: re-armed
+		{12, hiss, false},
+		{13, hiss, true},
+	}
+	for _, st := range steps {
+		s.feed(st.samples, at(st.sec))
+		if got := s.silent(at(st.sec), timeout); got != st.want {
+			t.Fatalf("silent at %ds = %v, want %v", st.sec, got, st.want)
+		}
+	}
+
+	// a meter that stopped hearing samples cannot tell silence from a stall
+	s.feed(loud, at(20))
+	s.feed(quiet, at(21))
+	if s.silent(at(30), timeout) {
+		t.Fatal("silent although no samples arrived since second 21")
+	}
+}
+
+func TestRMSDBFS(t *testing.T) {
+	if db := rmsDBFS(make([]int16, 10)); !math.IsInf(db, -1) {
+		t.Fatalf("digital silence = %v dBFS, want -Inf", db)
+	}
+	if db := rmsDBFS([]int16{32767, -32767}); db < -0.01 || db > 0 {
+		t.Fatalf("full scale = %v dBFS, want 0", db)
+	}
+	if db := rmsDBFS([]int16{16384, -16384}); math.Abs(db+6.02) > 0.01 {
+		t.Fatalf("half scale = %v dBFS, want -6.02", db)
+	}
+}
+
+func TestBufferMeter(t *testing.T) {
+	var b bufferMeter
+	start := time.Unix(0, 0)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	if !b.note(at(0)) {
+		t.Fatal("first event should start a reporter")
+	}
+	if b.note(at(100)) {
+		t.Fatal("a second reporter was started")
+	}
+	if pct, done := b.percent(at(150)); done || pct != 10 {
+		t.Fatalf("percent at 150ms = %v, %v; want 10, false", pct, done)
+	}
+	for ms := 200; ms <= 3000; ms += 200 {
+		b.note(at(ms))
+	}
+	if pct, done := b.percent(at(3100)); done || pct != 99 {
+		t.Fatalf("long buffering = %v, %v; want capped at 99", pct, done)
+	}
+	if pct, done := b.percent(at(3000 + int(bufferingQuiet/time.Millisecond))); !done || pct != 100 {
+		t.Fatalf("quiet events = %v, %v; want 100, done", pct, done)
+	}
+	if !b.note(at(5000)) {
+		t.Fatal("a new spell should start a reporter")
+	}
+	b.idle()
+	if _, done := b.percent(at(5010)); !done {
+		t.Fatal("idle meter should report done")
+	}
+}
+
+func TestRememberedHint(t *testing.T) {
+	const stream = "http://host/live"
+	cases := []struct {
+		in, want metadata.StrategyHint
+	}{
+		{metadata.StrategyHint{Type: metadata.MetadataTypeICY, URL: stream}, metadata.StrategyHint{Type: metadata.MetadataTypeICY}},
+		{metadata.StrategyHint{Type: metadata.MetadataTypeICY, URL: "http://host/live-320"}, metadata.StrategyHint{Type: metadata.MetadataTypeICY, URL: "http://host/live-320"}},
+		{metadata.StrategyHint{Type: metadata.MetadataTypeHLS, URL: stream}, metadata.StrategyHint{Type: metadata.MetadataTypeHLS}},
+		{metadata.StrategyHint{Type: metadata.MetadataTypeJSON, URL: stream}, metadata.StrategyHint{Type: metadata.MetadataTypeJSON, URL: stream}},
+	}
+	for _, c := range cases {
+		if got := rememberedHint(c.in, stream); got != c.want {
+			t.Errorf("rememberedHint(%+v) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestPreviewReportsTitlesWithoutHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("icy-metaint", "1")
+		meta := "StreamTitle='Artist - Song';"
+		for len(meta)%16 != 0 {
+			meta += "\x00"
+		}
+		w.Write(append([]byte{0, byte(len(meta) / 16)}, meta...))
+	}))
+	defer srv.Close()
+
+	pl := NewPlayer()
+	pl.SetStableWindow(0)
+	titles := make(chan string, 8)
+	pl.SetOnNow(func(s string) { titles <- s })
+	if err := pl.Preview(context.Background(), srv.URL+"/live"); err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	defer pl.StopPreview()
+	if !pl.IsPreviewing() {
+		t.Fatal("not previewing after Preview")
+	}
+	select {
+	case got := <-titles:
+		if got != "Artist - Song" {
+			t.Fatalf("previewed title %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no title reported while previewing")
+	}
+	if h := pl.History(); len(h) != 0 {
+		t.Fatalf("previewed titles went into history: %+v", h)
+	}
+	pl.StopPreview()
+	if pl.IsPreviewing() || pl.CurrentTitle() != "" {
+		t.Fatalf("after StopPreview: previewing %v, title %q", pl.IsPreviewing(), pl.CurrentTitle())
+	}
+}
+
+func TestPreviewReportsMetadataUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	pl := NewPlayer()
+	pl.ConfigureMetadata(metadata.ProviderOptions{DiscoveryTimeout: 2 * time.Second})
+	unavailable := make(chan struct{}, 1)
+	pl.SetOnMetadataUnavailable(func() { unavailable <- struct{}{} })
+	if err := pl.Preview(context.Background(), srv.URL+"/live"); err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	defer pl.StopPreview()
+	select {
+	case <-unavailable:
+	case <-time.After(8 * time.Second):
+		t.Fatal("no metadata-unavailable signal for a stream without metadata")
+	}
+}
+
+func TestSecurityErrorOncePerStream(t *testing.T) {
+	pl := NewPlayer()
+	var got []error
+	pl.SetOnSecurityError(func(err error) { got = append(got, err) })
+	first, second := errors.New("first"), errors.New("second")
+	pl.securityError(first)
+	pl.securityError(second)
+	if len(got) != 1 || got[0] != first {
+		t.Fatalf("reported %v, want only the first refusal", got)
+	}
+	pl.resetStreamState()
+	pl.securityError(second)
+	if len(got) != 2 || got[1] != second {
+		t.Fatalf("after a new stream reported %v, want the next refusal again", got)
+	}
+}
+
+func TestRescanReportsUnavailableDespiteTitle(t *testing.T) {
+	pl := NewPlayer()
+	calls := 0
+	pl.SetOnMetadataUnavailable(func() { calls++ })
+	pl.watchURL, pl.currentTitle = "http://radio.example/live", "Old - Title"
+	pl.metadataUnavailable(pl.watchURL)
+	if calls != 0 {
+		t.Fatalf("unavailable reported while a title shows")
+	}
+	pl.rescanning = true
+	pl.metadataUnavailable(pl.watchURL)
+	pl.metadataUnavailable(pl.watchURL)
+	if calls != 1 {
+		t.Fatalf("after a rescan reported %d times, want once", calls)
+	}
+}
+
+func TestClearHistoryThenRestore(t *testing.T) {
+	pl := NewPlayer()
+	pl.mu.Lock()
+	pl.recordTitleLocked("A - One")
+	pl.recordTitleLocked("B - Two")
+	pl.mu.Unlock()
+	saved := pl.History()
+
+	pl.ClearHistory()
+	if h := pl.History(); len(h) != 0 {
+		t.Fatalf("history after ClearHistory: %+v", h)
+	}
+	pl.RestoreHistory(saved)
+	h := pl.History()
+	if len(h) != 2 || h[0].Title != "B - Two" || h[1].Title != "A - One" {
+		t.Fatalf("history after clear and reload: %+v", h)
+	}
+}
+
+// keepAliveProvider reports one title, then only keep-alives until quiet is
+// closed.
+type keepAliveProvider struct{ quiet chan struct{} }
+
+func (p keepAliveProvider) Watch(ctx context.Context, u string, h metadata.StrategyHint, onUpdate func(metadata.Info), onStrategy func(metadata.StrategyHint)) {
+	go p.Run(ctx, u, h, onUpdate, onStrategy)
+}
+
+func (p keepAliveProvider) Run(ctx context.Context, _ string, _ metadata.StrategyHint, onUpdate func(metadata.Info), _ func(metadata.StrategyHint)) {
+	onUpdate(metadata.Info{Title: "DJ - Two Hour Mix"})
+	tick := time.NewTicker(30 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.quiet:
+			<-ctx.Done()
+			return
+		case <-tick.C:
+			onUpdate(metadata.Info{})
+		}
+	}
+}
+
+func TestKeepAlivesKeepTitleFresh(t *testing.T) {
+	quiet := make(chan struct{})
+	pl := NewPlayer()
+	pl.SetStableWindow(0)
+	pl.SetStaleTitleTimeout(150 * time.Millisecond)
+	pl.onNow = func(string) {}
+	pl.metaProvider = keepAliveProvider{quiet: quiet}
+	pl.startICYWatcher("http://radio.example/live")
+	defer pl.stopICYWatcher()
+
+	// a long run of empty metadata blocks keeps the title up
+	time.Sleep(600 * time.Millisecond)
+	if got := pl.CurrentTitle(); got != "DJ - Two Hour Mix" {
+		t.Fatalf("title %q after keep-alives, want it kept", got)
+	}
+	close(quiet)
+	deadline := time.Now().Add(2 * time.Second)
+	for pl.CurrentTitle() != "" {
+		if time.Now().After(deadline) {
+			t.Fatal("title kept after the source went quiet")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestResolvedStreamsExpire(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/short" {
+			http.Redirect(w, r, "/live", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	short := srv.URL + "/short"
+	pl := NewPlayer()
+	pl.resolveClient = srv.Client()
+	pl.resolved = map[string]resolvedStream{short: {url: "http://moved.example/live", at: time.Now()}}
+	if got := pl.resolvePlaylist(context.Background(), short); got != "http://moved.example/live" {
+		t.Fatalf("fresh lookup not reused: %q", got)
+	}
+	pl.resolved[short] = resolvedStream{url: "http://moved.example/live", at: time.Now().Add(-resolveTTL)}
+	if got := pl.resolvePlaylist(context.Background(), short); got != srv.URL+"/live" {
+		t.Fatalf("expired lookup reused: %q", got)
+	}
+
+	// a stream that fails to play is looked up afresh
+	pl.source = short
+	pl.forgetResolved()
+	if _, ok := pl.resolved[short]; ok {
+		t.Fatal("lookup kept after forgetResolved")
+	}
+}
+
+func TestFamilyRelay(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/short" {
+			http.Redirect(w, r, "/live", http.StatusFound)
+			return
+		}
+		gotHost = r.Host
+		w.Header().Set("icy-name", "Radio X")
+		_, _ = io.WriteString(w, "audio")
+	}))
+	defer srv.Close()
+
+	r, err := newFamilyRelay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.close()
+	client := &http.Client{}
+
+	// the station sees its own Host; redirects stay on the relay
+	resp, err := client.Get(r.register(srv.URL+"/short", 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "audio" || resp.Header.Get("icy-name") != "Radio X" {
+		t.Fatalf("relayed %q, icy-name %q", body, resp.Header.Get("icy-name"))
+	}
+	if want := strings.TrimPrefix(srv.URL, "http://"); gotHost != want {
+		t.Fatalf("station saw Host %q, want %q", gotHost, want)
+	}
+	if !strings.HasPrefix(resp.Request.URL.String(), "http://"+r.ln.Addr().String()+"/") {
+		t.Fatalf("redirect left the relay: %s", resp.Request.URL)
+	}
+
+	// the httptest server listens on IPv4 only
+	resp, err = client.Get(r.register(srv.URL+"/live", 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("IPv6-only dial to an IPv4 server = %d, want 502", resp.StatusCode)
+	}
+
+	// nothing but registered streams is relayed
+	resp, err = client.Get("http://" + r.ln.Addr().String() + "/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown token = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestFamilyRelayICYStatus(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, _ = bufio.NewReader(c).ReadString('\n')
+		_, _ = io.WriteString(c, "ICY 200 OK\r\nicy-metaint: 8192\r\n\r\naudio")
+	}()
+
+	r, err := newFamilyRelay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.close()
+	resp, err := http.Get(r.register("http://"+ln.Addr().String()+"/", 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("icy-metaint") != "8192" || string(body) != "audio" {
+		t.Fatalf("Shoutcast v1 reply relayed as %d %q, metaint %q", resp.StatusCode, body, resp.Header.Get("icy-metaint"))
+	}
+}
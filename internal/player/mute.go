@@ -0,0 +1,53 @@
+package player
+
+import "time"
+
+// SetMuteFaded mutes or unmutes with a fade of d: muting ramps the output
+// down to silence before libVLC is muted, unmuting releases the mute at
+// silence and ramps back up to the set volume. The mute state changes at
+// once; SetMute, SetVolume or another fade cut the ramp short.
+func (pl *Player) SetMuteFaded(m bool, d time.Duration) {
+	pl.mu.Lock()
+	if d <= 0 || m == pl.muted || !pl.isPlaying {
+		pl.mu.Unlock()
+		_ = pl.SetMute(m)
+		return
+	}
+	pl.muted = m
+	ctx := pl.startFadeLocked()
+	from, target := pl.outVolume, pl.volume
+	pl.mu.Unlock()
+
+	if !m {
+		if !pl.applyOutVolume(ctx, 0) {
+			return
+		}
+		pl.vlcMu.Lock()
+		pl.setVLCMuteLocked(false)
+		pl.vlcMu.Unlock()
+		go pl.runFade(ctx, 0, target, d)
+		return
+	}
+	go func() {
+		pl.runFade(ctx, from, 0, d)
+		pl.vlcMu.Lock()
+		defer pl.vlcMu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		// mute, then put the level back so unmuting without a fade is not silent
+		pl.setVLCMuteLocked(true)
+		_ = pl.p.SetVolume(target)
+		pl.mu.Lock()
+		pl.outVolume = target
+		pl.mu.Unlock()
+	}()
+}
+
+// setVLCMuteLocked brings libVLC's mute flag to m. Callers hold vlcMu.
+func (pl *Player) setVLCMuteLocked(m bool) {
+	if m != pl.vlcMuted {
+		pl.p.ToggleMute()
+		pl.vlcMuted = m
+	}
+}
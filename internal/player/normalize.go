@@ -0,0 +1,66 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultNormalizeLevel is libVLC's own norm-max-level default.
+const DefaultNormalizeLevel = 2.0
+
+// AudioFilters are the libVLC audio filters attached to every stream.
+type AudioFilters struct {
+	// Normalize enables libVLC's normvol filter, which evens out loudness
+	// between stations and tracks; NormalizeLevel is its maximum gain (0 =
+	// DefaultNormalizeLevel).
+	Normalize      bool
+	NormalizeLevel float64
+	// Mono downmixes playback with libVLC's mono channel mixer. The
+	// equalizer and balance keep working; with mono on, both channels carry
+	// the same signal, so the balance only sets how loud each speaker is.
+	Mono bool
+}
+
+// SetAudioFilters sets the filters the next Load attaches and reports whether
+// they changed. They are media options rather than global Init arguments so
+// they can be toggled at runtime, at the cost of a reload: a stream that is
+// playing keeps its old filters until the caller loads it again.
+func (pl *Player) SetAudioFilters(f AudioFilters) bool {
+	if f.NormalizeLevel <= 0 {
+		f.NormalizeLevel = DefaultNormalizeLevel
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	changed := pl.normalize != f.Normalize || pl.mono != f.Mono ||
+		(f.Normalize && pl.normLevel != f.NormalizeLevel)
+	pl.normalize, pl.normLevel, pl.mono = f.Normalize, f.NormalizeLevel, f.Mono
+	return changed
+}
+
+// audioFilterOptions returns the media options for the normalization, mono
+// and balance settings, chained into a single audio-filter list.
+func (pl *Player) audioFilterOptions() []string {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	var filters, opts []string
+	if pl.normalize {
+		level := pl.normLevel
+		if level <= 0 {
+			level = DefaultNormalizeLevel
+		}
+		filters = append(filters, "normvol")
+		opts = append(opts, fmt.Sprintf(":norm-max-level=%.1f", level))
+	}
+	if pl.mono {
+		filters = append(filters, "mono")
+	}
+	if pl.stereoPan && pl.balance != 0 {
+		// pan-control runs from 0 (left) through 0.5 to 1 (right)
+		filters = append(filters, "stereo_pan")
+		opts = append(opts, fmt.Sprintf(":pan-control=%.2f", (pl.balance+1)/2))
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	return append([]string{":audio-filter=" + strings.Join(filters, ":")}, opts...)
+}
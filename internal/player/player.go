@@ -4,9 +4,11 @@ package player
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"html"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -20,12 +22,23 @@ import (
 )
 
 const (
-	// stableWindow smooths metadata updates by waiting for repeated values.
-	stableWindow = 6 * time.Second
+	// DefaultStableWindow smooths metadata updates by waiting for repeated
+	// values; see SetStableWindow.
+	DefaultStableWindow = 6 * time.Second
 	// nearEndThreshold is the remaining duration that counts as "almost done".
 	nearEndThreshold = 10 * time.Second
+	// resolveTTL is how long a playlist or redirect lookup is reused; CDNs
+	// move their targets, and a stream that fails to play is looked up
+	// afresh at once.
+	resolveTTL = 10 * time.Minute
 )
 
+// resolvedStream is a cached resolvePlaylist answer.
+type resolvedStream struct {
+	url string
+	at  time.Time
+}
+
 // Player is a thread-safe wrapper around libVLC that serializes every call,
 // stabilizes "Now Playing" metadata, and manages optional ICY watchers.
 type Player struct {
@@ -33,22 +46,90 @@ type Player struct {
 	media  *vlc.Media
 	volume int
 	muted  bool
+	// vlcMuted is libVLC's own mute flag, guarded by vlcMu; it trails muted
+	// while SetMuteFaded fades out
+	vlcMuted bool
 
 	stream    string
 	isPlaying bool
+	// source is the URL passed to Load, before playlist resolution
+	source string
+	// resolved caches preset URL -> final stream URL lookups for resolveTTL
+	resolved map[string]resolvedStream
+	// resolveClient fetches playlists and redirects with the metadata
+	// options (proxy, dial timeout); nil uses the metadata defaults.
+	resolveClient *http.Client
 
 	onNow     func(string)
 	onStation func(string)
+	onArtwork func(string)
+	onError   func(error)
+
+	// buffering progress, see SetOnBuffering; eventPlayer is the libVLC
+	// player whose events count (the outgoing one is ignored mid-crossfade)
+	onBuffering func(float64)
+	buffer      bufferMeter
+	eventPlayer *vlc.Player
+
+	// dead-air watchdog, see SetSilenceTimeout
+	silenceTimeout time.Duration
+	silenceCancel  context.CancelFunc
+	silenceWG      sync.WaitGroup
+
+	// enricher looks up cover art when SetCoverArtLookup is on (nil = off);
+	// artTitle is the title artwork and album belong to, see trackArtwork
+	enricher *metadata.Enricher
+	// titleFilter cleans titles of the current station, see SetTitleFilters
+	titleFilter *metadata.TitleFilter
+	artTitle    string
+	artwork     string
+	album       string
 
 	// title stabilization state
 	currentTitle     string
 	pendingTitle     string
 	firstSeenPending time.Time
+	// flaps blocks titles that were replaced moments ago, see flapGuard
+	flaps flapGuard
+	// stableWindow is how long a new title must hold before it is applied
+	stableWindow time.Duration
+	// stale-title watchdog, see SetStaleTitleTimeout; staleGen invalidates
+	// a timer that fires after being stopped
+	staleAfter time.Duration
+	staleTimer *time.Timer
+	staleGen   int
+	// flapAfter overrides flapWindow when set
+	flapAfter time.Duration
+
+	// history of applied titles; station is the ICY name they are tagged with
+	history trackHistory
+	station string
+
+	// icy-br / icy-genre of the current stream as reported by the metadata
+	// provider; see StreamInfo. Listener count and public URL come from
+	// Icecast status-json only; see StationDetails
+	icyBitrate   int
+	icyGenre     string
+	icyListeners int
+	icyListenURL string
+
+	// audioDevice is the output device chosen via SetAudioDevice
+	audioDevice string
+	// balance is the stereo balance chosen via SetBalance (-1 left … +1
+	// right); stereoPan is whether libVLC has the stereo_pan filter for it
+	balance   float64
+	stereoPan bool
+
+	// loudness normalization (normvol filter), see SetAudioFilters
+	normalize bool
+	normLevel float64
+	// mono downmix (mono channel mixer), see SetAudioFilters
+	mono bool
 
 	// single lock guarding all C/libVLC invocations
 	vlcMu sync.Mutex
 
-	// metadata polling management (VLC-safe ticker)
+	// libVLC Meta poller, see startMetaPoll
 	metaCancel context.CancelFunc
 	metaWG     sync.WaitGroup
 
@@ -59,14 +140,70 @@ type Player struct {
 	metadataHintType   string
 	metadataHintURL    string
 	onMetadataResolved func(string, string)
+	// previewing is set while the watcher runs without playback, see Preview
+	previewing bool
 
 	// internal lock for Player fields (not for libVLC)
 	mu sync.Mutex
 
 	vlcMajor     int
 	parseTimeout int // ms
+
+	// volume fades: volume above is always the target level, outVolume is
+	// what libVLC currently plays at (differs only while a fade runs)
+	outVolume  int
+	fadeCancel context.CancelFunc
+	fadeIn     time.Duration
+	fadeOut    time.Duration
+	// stopPending is set while Stop's fade-out runs on stopWG's goroutine,
+	// which stops libVLC when the fade ends; see finishPendingStop
+	stopPending bool
+	stopWG      sync.WaitGroup
+
+	// eq is the equalizer last applied, reapplied to the player that takes
+	// over during a crossfade; xfadeWG tracks the goroutine that retires the
+	// outgoing player
+	eq      *vlc.Equalizer
+	xfadeWG sync.WaitGroup
+
+	// metaOpts are the options the provider was last built with; userAgent
+	// and referer are the stream headers set by SetRequestHeaders
+	metaOpts  metadata.ProviderOptions
+	userAgent string
+	referer   string
+	// requireTLS and verifyConn are the station's TLS policy set by
+	// SetStationTLS; onSecurityError hears about the first request it
+	// refused for each stream, securityReported notes that it has
+	requireTLS       bool
+	verifyConn       func(tls.ConnectionState) error
+	onSecurityError  func(error)
+	securityReported bool
+	// ipVersion is 4 or 6 to keep the stream and metadata clients on one
+	// address family, 0 for either; relay carries libVLC's connection then,
+	// see SetIPVersion
+	ipVersion int
+	relay     *familyRelay
+	// jsonFields map the station's API/WS/SSE JSON, see SetJSONFields
+	jsonFields metadata.JSONFields
+	// watchURL is the stream the ICY watcher reads; onMetaUnavailable
+	// hears when discovery found no metadata source for it, which after
+	// RescanMetadata (rescanning) is reported even while a title shows
+	watchURL          string
+	onMetaUnavailable func()
+	rescanning        bool
 }
 
+const (
+	// DefaultUserAgent and DefaultReferer are sent to stream servers when no
+	// override is configured.
+	DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
+	DefaultReferer   = "https://www.bbc.co.uk/sounds"
+	// DefaultFadeIn is how long Play ramps from silence to the set volume.
+	DefaultFadeIn = 600 * time.Millisecond
+	// fadeStep is the interval between volume updates during a fade.
+	fadeStep = 25 * time.Millisecond
+)
+
 // stdLogger adapts the standard log package to the metadata.Logger interface.
 type stdLogger struct{}
 
@@ -80,13 +217,182 @@ func NewPlayer() *Player {
 	pm := 70
 	return &Player{
 		volume:       pm,
+		outVolume:    pm,
+		fadeIn:       DefaultFadeIn,
+		stableWindow: DefaultStableWindow,
+		staleAfter:   DefaultStaleTitleTimeout,
 		parseTimeout: 4000,
-		metaProvider: metadata.NewProvider(nil, stdLogger{}),
+		metaProvider: metadata.NewProvider(nil, stdLogger{}, metadata.ProviderOptions{}),
+	}
+}
+
+// ConfigureMetadata rebuilds the metadata provider with the given options. It
+// takes effect the next time the ICY watcher starts.
+func (pl *Player) ConfigureMetadata(opts metadata.ProviderOptions) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.metaOpts = opts
+	pl.rebuildProviderLocked()
+	if pl.enricher != nil {
+		pl.enricher = metadata.NewEnricher(opts)
 	}
 }
 
+// SetRequestHeaders overrides the User-Agent and Referer sent for the next
+// Load, both to libVLC and with metadata requests; "" restores the defaults.
+// Callers set them per station before loading it.
+func (pl *Player) SetRequestHeaders(userAgent, referer string) {
+	userAgent, referer = strings.TrimSpace(userAgent), strings.TrimSpace(referer)
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if userAgent == pl.userAgent && referer == pl.referer {
+		return
+	}
+	pl.userAgent, pl.referer = userAgent, referer
+	// cached resolutions may depend on the headers a server saw
+	pl.resolved = nil
+	pl.rebuildProviderLocked()
+}
+
+// SetStationTLS sets the TLS policy for metadata and playlist requests of the
+// next loaded station: requireTLS refuses plain HTTP, and pins, when given,
+// accept only servers whose certificate has one of those public keys (see
+// metadata.PinSHA256). On an invalid pin no pinning is done and the error is
+// returned. Callers set it per station before loading it, like
+// SetRequestHeaders.
+func (pl *Player) SetStationTLS(requireTLS bool, pins []string) error {
+	var verify func(tls.ConnectionState) error
+	var err error
+	if len(pins) > 0 {
+		verify, err = metadata.PinSHA256(pins)
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if requireTLS == pl.requireTLS && verify == nil && pl.verifyConn == nil {
+		return err
+	}
+	pl.requireTLS, pl.verifyConn = requireTLS, verify
+	pl.resolved = nil
+	pl.rebuildProviderLocked()
+	return err
+}
+
+// SetIPVersion makes stream and metadata connections, including playlist and
+// redirect resolution, use IPv4 only (4) or IPv6 only (6); 0 lets the system
+// choose. libVLC 3 has no per-media switch for this, so http(s) streams are
+// played through a loopback relay that dials the station itself; other
+// schemes connect however the system prefers. It takes effect with the next
+// Load.
+func (pl *Player) SetIPVersion(v int) {
+	if v != 4 && v != 6 {
+		v = 0
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if v == pl.ipVersion {
+		return
+	}
+	pl.ipVersion = v
+	pl.resolved = nil
+	pl.rebuildProviderLocked()
+}
+
+// SetJSONFields sets where the JSON of the next loaded station's API,
+// WebSocket or SSE metadata source keeps its now-playing fields. On an
+// invalid path the default layouts are used and the error is returned.
+func (pl *Player) SetJSONFields(f metadata.JSONFields) error {
+	err := f.Validate()
+	if err != nil {
+		f = metadata.JSONFields{}
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if f != pl.jsonFields {
+		pl.jsonFields = f
+		pl.rebuildProviderLocked()
+	}
+	return err
+}
+
+// SetOnSecurityError registers a callback for metadata and playlist requests
+// refused by the TLS settings, such as a downgrade to plain HTTP or a
+// certificate that matches no pin. It fires once per loaded stream, for the
+// first refusal, and may fire on any goroutine.
+func (pl *Player) SetOnSecurityError(fn func(error)) {
+	pl.mu.Lock()
+	pl.onSecurityError = fn
+	pl.mu.Unlock()
+}
+
+// SetOnMetadataUnavailable registers a callback for streams whose metadata
+// source could not be found within the provider's discovery timeout, so
+// "waiting for a title" can give way to a neutral text until the next play.
+// It does not fire once a title is showing, and may fire on any goroutine.
+func (pl *Player) SetOnMetadataUnavailable(fn func()) {
+	pl.mu.Lock()
+	pl.onMetaUnavailable = fn
+	pl.mu.Unlock()
+}
+
+// metadataUnavailable passes on the provider's OnUnavailable signal for url,
+// unless the watcher has moved to another stream since.
+func (pl *Player) metadataUnavailable(url string) {
+	pl.mu.Lock()
+	cb := pl.onMetaUnavailable
+	current := url == pl.watchURL && (pl.currentTitle == "" || pl.rescanning)
+	pl.rescanning = false
+	pl.mu.Unlock()
+	if current && cb != nil {
+		cb()
+	}
+}
+
+// securityError passes the first refused request of the current stream to
+// the security callback; the pollers retry, so later ones only repeat it.
+func (pl *Player) securityError(err error) {
+	pl.mu.Lock()
+	cb := pl.onSecurityError
+	first := !pl.securityReported
+	pl.securityReported = true
+	pl.mu.Unlock()
+	if first && cb != nil {
+		cb(err)
+	}
+}
+
+// rebuildProviderLocked builds the metadata provider and resolve client from
+// metaOpts, the header overrides and the station TLS policy. mu must be held.
+func (pl *Player) rebuildProviderLocked() {
+	opts := pl.metaOpts
+	opts.UserAgent, opts.Referer = pl.userAgent, pl.referer
+	opts.RequireTLS = opts.RequireTLS || pl.requireTLS
+	if pl.ipVersion != 0 {
+		opts.IPVersion = pl.ipVersion
+	}
+	if pl.verifyConn != nil {
+		opts.VerifyConnection = pl.verifyConn
+	}
+	opts.JSONFields = pl.jsonFields
+	opts.OnSecurityError = pl.securityError
+	opts.OnUnavailable = pl.metadataUnavailable
+	pl.metaProvider = metadata.NewProvider(nil, stdLogger{}, opts)
+	pl.resolveClient = metadata.NewHTTPClient(opts)
+}
+
+// SetTitleFilters installs the metadata.TitleFilter patterns applied to the
+// titles of the next loaded stream; nil or empty patterns show titles as
+// sent. On an invalid pattern no filtering is done and the error is returned.
+func (pl *Player) SetTitleFilters(patterns []string) error {
+	f, err := metadata.NewTitleFilter(patterns)
+	pl.mu.Lock()
+	pl.titleFilter = f
+	pl.mu.Unlock()
+	return err
+}
+
 // SetMetadataHint provides previously discovered metadata strategy information
 // and a callback that fires when the ICY watcher discovers a better source.
+// An ICY or HLS hint with an empty URL means the stream itself.
 func (pl *Player) SetMetadataHint(metaType, metaURL string, onResolved func(string, string)) {
 	pl.mu.Lock()
 	defer pl.mu.Unlock()
@@ -95,6 +401,19 @@ func (pl *Player) SetMetadataHint(metaType, metaURL string, onResolved func(stri
 	pl.onMetadataResolved = onResolved
 }
 
+// rememberedHint is sel as worth keeping for the next play of streamURL. When
+// metadata comes from the stream itself only the kind is kept: the resolved
+// URL may be a redirect target or a tokenized mount that differs next time.
+func rememberedHint(sel metadata.StrategyHint, streamURL string) metadata.StrategyHint {
+	switch sel.Type {
+	case metadata.MetadataTypeICY, metadata.MetadataTypeHLS:
+		if sel.URL == streamURL {
+			sel.URL = ""
+		}
+	}
+	return sel
+}
+
 func clamp(v, lo, hi int) int {
 	if v < lo {
 		return lo
@@ -161,6 +480,7 @@ func (pl *Player) Init(volume int, muted bool) error {
 	pl.vlcMajor = parseVlcMajor(ver)
 
 	pl.vlcMu.Lock()
+	stereoPan := hasAudioFilter("stereo_pan")
 	player, err := vlc.NewPlayer()
 	pl.vlcMu.Unlock()
 	if err != nil {
@@ -170,13 +490,20 @@ func (pl *Player) Init(volume int, muted bool) error {
 		return fmt.Errorf("new vlc player failed: %w", err)
 	}
 	pl.p = player
+	pl.mu.Lock()
+	pl.eventPlayer = player
+	pl.stereoPan = stereoPan
+	pl.mu.Unlock()
+	pl.attachBufferingEvents(player)
+	pl.attachErrorEvents(player)
 
 	// 3) Apply initial volume/mute
 	pl.volume = clamp(volume, 0, 100)
+	pl.outVolume = pl.volume
 	pl.vlcMu.Lock()
 	_ = pl.p.SetVolume(pl.volume)
 	if muted {
-		pl.p.ToggleMute()
+		pl.setVLCMuteLocked(true)
 		pl.muted = true
 	}
 	pl.vlcMu.Unlock()
@@ -188,14 +515,22 @@ func (pl *Player) Init(volume int, muted bool) error {
 func (pl *Player) Release() {
 	// stop ICY watcher
 	pl.stopICYWatcher()
+	pl.stopSilenceWatch()
 
-	// stop metadata ticker (safe ticker)
+	// stop the libVLC Meta poller
 	if pl.metaCancel != nil {
 		pl.metaCancel()
 		pl.metaWG.Wait()
 		pl.metaCancel = nil
 	}
 
+	// let a fade-out or crossfade finish with libVLC before it goes away
+	pl.finishPendingStop()
+	pl.mu.Lock()
+	pl.cancelFadeLocked()
+	pl.mu.Unlock()
+	pl.xfadeWG.Wait()
+
 	pl.vlcMu.Lock()
 	if pl.p != nil {
 		_ = pl.p.Stop()
@@ -209,6 +544,13 @@ func (pl *Player) Release() {
 	}
 	vlc.Release()
 	pl.vlcMu.Unlock()
+
+	pl.mu.Lock()
+	if pl.relay != nil {
+		pl.relay.close()
+		pl.relay = nil
+	}
+	pl.mu.Unlock()
 }
 
 // SetOnNow registers a callback that receives stabilized track titles.
@@ -225,6 +567,14 @@ func (pl *Player) SetOnStation(fn func(string)) {
 	pl.mu.Unlock()
 }
 
+// SetOnArtwork registers a callback for cover art URLs reported by the
+// metadata provider. It fires with "" when a track arrives without artwork.
+func (pl *Player) SetOnArtwork(fn func(string)) {
+	pl.mu.Lock()
+	pl.onArtwork = fn
+	pl.mu.Unlock()
+}
+
 // IsPlaying reports whether libVLC currently plays audio.
 func (pl *Player) IsPlaying() bool {
 	pl.mu.Lock()
@@ -232,6 +582,14 @@ func (pl *Player) IsPlaying() bool {
 	return pl.isPlaying
 }
 
+// CurrentTitle returns the now-playing title last reported to OnNow, or ""
+// before the stream has announced one.
+func (pl *Player) CurrentTitle() string {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.currentTitle
+}
+
 // SetAudioEqualizer applies the provided equalizer to the underlying VLC player in a thread-safe way.
 func (pl *Player) SetAudioEqualizer(eq *vlc.Equalizer) error {
 	pl.vlcMu.Lock()
@@ -239,6 +597,7 @@ func (pl *Player) SetAudioEqualizer(eq *vlc.Equalizer) error {
 	if pl.p == nil {
 		return fmt.Errorf("vlc player not initialized")
 	}
+	pl.eq = eq
 	return pl.p.SetEqualizer(eq)
 }
 
@@ -246,6 +605,44 @@ func (pl *Player) SetAudioEqualizer(eq *vlc.Equalizer) error {
 // playback. It also resets metadata trackers and begins parsing the stream in
 // the background so metadata reads are safe.
 func (pl *Player) Load(ctx context.Context, url string) error {
+	pl.resetStreamState()
+	u := pl.streamURL(ctx, url)
+
+	pl.vlcMu.Lock()
+	// release previous media
+	if pl.media != nil {
+		pl.media.Release()
+		pl.media = nil
+	}
+
+	m, err := pl.newStreamMedia(u)
+	if err != nil {
+		pl.vlcMu.Unlock()
+		return err
+	}
+
+	if err := pl.p.SetMedia(m); err != nil {
+		m.Release()
+		pl.vlcMu.Unlock()
+		return fmt.Errorf("set media failed: %w", err)
+	}
+
+	pl.media = m
+	pl.stream = u
+	pl.vlcMu.Unlock()
+	pl.mu.Lock()
+	pl.source = url
+	pl.mu.Unlock()
+
+	pl.parseInBackground(m)
+	return nil
+}
+
+// resetStreamState stops the watchers of the current stream and forgets its
+// titles before another stream takes over.
+func (pl *Player) resetStreamState() {
+	pl.finishPendingStop()
+	pl.StopPreview()
 	// if currently playing stop the previous ICY watcher before switching media
 	if pl.IsPlaying() {
 		pl.stopICYWatcher()
@@ -253,77 +650,299 @@ func (pl *Player) Load(ctx context.Context, url string) error {
 	// reset title stabilization
 	pl.currentTitle, pl.pendingTitle = "", ""
 	pl.firstSeenPending = time.Time{}
+	pl.flaps.reset()
+	pl.mu.Lock()
+	pl.stopStaleTimerLocked()
+	pl.station = ""
+	pl.icyBitrate, pl.icyGenre = 0, ""
+	pl.icyListeners, pl.icyListenURL = 0, ""
+	pl.artTitle, pl.artwork, pl.album = "", "", ""
+	pl.securityReported = false
+	pl.rescanning = false
+	pl.mu.Unlock()
 	// stop previous metadata poller
 	if pl.metaCancel != nil {
 		pl.metaCancel()
 		pl.metaWG.Wait()
 		pl.metaCancel = nil
 	}
+}
 
-	pl.vlcMu.Lock()
-	// release previous media
-	if pl.media != nil {
-		pl.media.Release()
-		pl.media = nil
-	}
+// ResolveStream looks up the stream url plays: the first entry of a PLS/M3U
+// playlist, after any redirects. The answer is cached, so a Load, Preview or
+// CrossfadeTo of url that follows does not touch the network; callers on a
+// UI thread run this in the background first.
+func (pl *Player) ResolveStream(ctx context.Context, url string) string {
+	return pl.streamURL(ctx, url)
+}
 
+// streamURL sanitizes a user-supplied URL and resolves playlist links.
+func (pl *Player) streamURL(ctx context.Context, url string) string {
 	// sanitize URL: trim spaces/CRLF/tabs that may sneak in from clipboard or inputs
 	u := strings.TrimSpace(url)
 	u = strings.TrimLeft(u, "\r\n\t ")
 	u = strings.TrimRight(u, "\r\n\t ")
+	// PLS/M3U links point at a playlist file and short links redirect; VLC
+	// and the ICY watcher both need the stream they end up at.
+	return pl.resolvePlaylist(ctx, u)
+}
 
-	m, err := vlc.NewMediaFromURL(u)
+// newStreamMedia creates a media for u with the player's stream options.
+// vlcMu must be held.
+func (pl *Player) newStreamMedia(u string) (*vlc.Media, error) {
+	m, err := vlc.NewMediaFromURL(pl.mediaURL(u))
 	if err != nil {
-		pl.vlcMu.Unlock()
-		return fmt.Errorf("new media from url failed: %w", err)
+		return nil, fmt.Errorf("new media from url failed: %w", err)
+	}
+	_ = m.AddOptions(pl.streamOptions()...)
+	if opts := pl.audioFilterOptions(); len(opts) > 0 {
+		_ = m.AddOptions(opts...)
+	}
+	return m, nil
+}
+
+// mediaURL returns the URL libVLC opens to play u: u itself, or its relay
+// address when SetIPVersion holds http(s) streams to one IP version.
+func (pl *Player) mediaURL(u string) string {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	lower := strings.ToLower(u)
+	if pl.ipVersion == 0 || (!strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://")) {
+		return u
+	}
+	if pl.relay == nil {
+		r, err := newFamilyRelay()
+		if err != nil {
+			log.Printf("ip version relay unavailable: %v", err)
+			return u
+		}
+		pl.relay = r
 	}
+	return pl.relay.register(u, pl.ipVersion)
+}
 
-	// media options: enable metadata, robust demux, user-agent/referrer, and sane caching/reconnect
-	_ = m.AddOptions(
+// streamOptions returns the media options every stream is opened with:
+// metadata, robust demux, user-agent/referrer, and sane caching/reconnect.
+func (pl *Player) streamOptions() []string {
+	pl.mu.Lock()
+	ua, ref := pl.userAgent, pl.referer
+	pl.mu.Unlock()
+	if ua == "" {
+		ua = DefaultUserAgent
+	}
+	if ref == "" {
+		ref = DefaultReferer
+	}
+	return []string{
 		":metadata-network-access=1",
 		":icy-metadata=1",
 		":demux=any",
-		":http-user-agent=Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36",
-		":http-referrer=https://www.bbc.co.uk/sounds",
+		":http-user-agent=" + ua,
+		":http-referrer=" + ref,
 		":network-caching=1500",
 		":live-caching=1500",
 		":http-reconnect",
-	)
-
-	if err := pl.p.SetMedia(m); err != nil {
-		m.Release()
-		pl.vlcMu.Unlock()
-		return fmt.Errorf("set media failed: %w", err)
 	}
+}
 
-	pl.media = m
-	pl.stream = url
-	pl.vlcMu.Unlock()
-
+// parseInBackground starts the metadata parse of m where the runtime needs it.
+func (pl *Player) parseInBackground(m *vlc.Media) {
 	// Ask libVLC to parse media before reading metadata for safety.
 	// This reduces the risk of libVLC returning an invalid pointer to a Meta string.
-	// (On VLC3 this behaves reliably; on VLC4 we skip metadata anyway).
-	go func(mm *vlc.Media, timeout int) {
-		// run in background without blocking UI
-		_ = mm.ParseWithOptions(timeout, vlc.MediaParseNetwork, vlc.MediaFetchNetwork)
-	}(m, pl.parseTimeout)
+	// VLC 3, and runtimes that report no version, need it; VLC 4 fills Meta
+	// from the playing input.
+	if metaSourceFor(pl.vlcMajor) == metaAfterParse {
+		go func(mm *vlc.Media, timeout int) {
+			// run in background without blocking UI
+			_ = mm.ParseWithOptions(timeout, vlc.MediaParseNetwork, vlc.MediaFetchNetwork)
+		}(m, pl.parseTimeout)
+	}
+}
 
-	return nil
+// resolvePlaylist maps a PLS/M3U URL to its first stream and follows any
+// redirects to the final endpoint, caching the answer so replaying a preset
+// does not refetch anything and the ICY watcher starts from the same URL VLC
+// plays. Lookup failures fall back to the original URL and let VLC try it;
+// they are cached too, so a Load after ResolveStream never blocks on them.
+func (pl *Player) resolvePlaylist(ctx context.Context, u string) string {
+	pl.mu.Lock()
+	cached, ok := pl.resolved[u]
+	client := pl.resolveClient
+	pl.mu.Unlock()
+	if ok && time.Since(cached.at) < resolveTTL {
+		return cached.url
+	}
+	target, err := metadata.ResolvePlaylist(ctx, client, u)
+	if err != nil {
+		log.Printf("playlist resolve %s: %v", u, err)
+		target = u
+	} else if final, err := metadata.ResolveFinalURL(ctx, client, target); err != nil {
+		log.Printf("redirect resolve %s: %v", target, err)
+	} else {
+		target = final
+	}
+	pl.mu.Lock()
+	if pl.resolved == nil {
+		pl.resolved = make(map[string]resolvedStream)
+	}
+	pl.resolved[u] = resolvedStream{url: target, at: time.Now()}
+	pl.mu.Unlock()
+	return target
+}
+
+// forgetResolved drops the cached lookup of the loaded stream, so the next
+// Load of it asks the server again. Handlers of libVLC errors call it.
+func (pl *Player) forgetResolved() {
+	pl.mu.Lock()
+	delete(pl.resolved, strings.TrimSpace(pl.source))
+	pl.mu.Unlock()
+}
+
+// attachErrorEvents forgets the cached lookup of a stream p fails to play: a
+// playlist entry or redirect target that moved would otherwise stick.
+func (pl *Player) attachErrorEvents(p *vlc.Player) {
+	em, err := p.EventManager()
+	if err != nil {
+		return
+	}
+	if _, err := em.Attach(vlc.MediaPlayerEncounteredError, func(vlc.Event, interface{}) {
+		pl.mu.Lock()
+		current := p == pl.eventPlayer
+		pl.mu.Unlock()
+		if current {
+			pl.forgetResolved()
+		}
+	}, nil); err != nil {
+		log.Printf("error events unavailable: %v", err)
+	}
+}
+
+// SetStableWindow sets how long a new title must hold before onNow reports
+// it; zero applies titles as soon as they arrive.
+func (pl *Player) SetStableWindow(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	pl.mu.Lock()
+	pl.stableWindow = d
+	pl.mu.Unlock()
+}
+
+// SetFadeDurations configures the fade-in applied by Play and the fade-out
+// applied by Stop. Zero disables the respective fade.
+func (pl *Player) SetFadeDurations(in, out time.Duration) {
+	pl.mu.Lock()
+	pl.fadeIn, pl.fadeOut = in, out
+	pl.mu.Unlock()
+}
+
+// FadeTo ramps the volume to target over d on a background goroutine,
+// cancelling any fade already in flight. The target becomes the player's
+// volume immediately, so Vol and callers persisting the level never observe a
+// transient mid-fade value.
+func (pl *Player) FadeTo(target int, d time.Duration) {
+	target = clamp(target, 0, 100)
+	pl.mu.Lock()
+	pl.volume = target
+	ctx := pl.startFadeLocked()
+	from := pl.outVolume
+	pl.mu.Unlock()
+	go pl.runFade(ctx, from, target, d)
+}
+
+// startFadeLocked cancels the running fade and returns the context for a new
+// one. pl.mu must be held.
+func (pl *Player) startFadeLocked() context.Context {
+	pl.cancelFadeLocked()
+	ctx, cancel := context.WithCancel(context.Background())
+	pl.fadeCancel = cancel
+	return ctx
+}
+
+// cancelFadeLocked stops the running fade, if any. pl.mu must be held.
+func (pl *Player) cancelFadeLocked() {
+	if pl.fadeCancel != nil {
+		pl.fadeCancel()
+		pl.fadeCancel = nil
+	}
+}
+
+// runFade steps the output volume from -> to across d. Each step takes vlcMu
+// on its own so other libVLC calls interleave with the fade.
+func (pl *Player) runFade(ctx context.Context, from, to int, d time.Duration) {
+	steps := int(d / fadeStep)
+	if steps < 1 {
+		steps = 1
+	}
+	for i := 1; i <= steps; i++ {
+		if !pl.applyOutVolume(ctx, from+(to-from)*i/steps) {
+			return
+		}
+		if i == steps {
+			return
+		}
+		t := time.NewTimer(fadeStep)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// applyOutVolume sets the libVLC volume unless ctx was cancelled first.
+func (pl *Player) applyOutVolume(ctx context.Context, v int) bool {
+	pl.vlcMu.Lock()
+	defer pl.vlcMu.Unlock()
+	if ctx.Err() != nil {
+		return false
+	}
+	_ = pl.p.SetVolume(v)
+	pl.mu.Lock()
+	pl.outVolume = v
+	pl.mu.Unlock()
+	return true
 }
 
 // Play starts playback for the last loaded media and launches the ICY watcher.
 func (pl *Player) Play() error {
+	pl.finishPendingStop()
+	pl.mu.Lock()
+	fadeIn := pl.fadeIn
+	target := pl.volume
+	pl.mu.Unlock()
+	if fadeIn > 0 {
+		pl.mu.Lock()
+		ctx := pl.startFadeLocked()
+		pl.mu.Unlock()
+		pl.applyOutVolume(ctx, 0)
+	}
+
 	pl.vlcMu.Lock()
 	err := pl.p.Play()
 	pl.vlcMu.Unlock()
 	if err != nil {
+		pl.forgetResolved()
 		return fmt.Errorf("play failed: %w", err)
 	}
+	if fadeIn > 0 {
+		pl.FadeTo(target, fadeIn)
+	}
 	pl.mu.Lock()
 	pl.isPlaying = true
+	pl.previewing = false
 	cb := pl.onNow
 	u := pl.stream
+	device := pl.audioDevice
+	balance := pl.balance
 	pl.mu.Unlock()
+	if device != "" {
+		_ = pl.applyAudioDevice()
+	}
+	if balance != 0 {
+		_ = pl.applyBalance()
+	}
 
 	// Emit immediate status; ICY watcher updates once metadata arrives
 	if cb != nil {
@@ -331,77 +950,150 @@ func (pl *Player) Play() error {
 	}
 	// Start ICY watcher alongside VLC playback
 	pl.startICYWatcher(u)
+	pl.startMetaPoll()
+	pl.startSilenceWatch()
 	return nil
 }
 
 // Stop halts playback, stops metadata pollers, and clears pending titles.
+// With a fade-out set it returns at once and libVLC stops when the fade ends.
 func (pl *Player) Stop() {
+	pl.finishPendingStop()
 	// stop ICY watcher
 	pl.stopICYWatcher()
+	pl.stopSilenceWatch()
 
-	// stop metadata ticker (safe ticker)
+	// stop the libVLC Meta poller
 	if pl.metaCancel != nil {
 		pl.metaCancel()
 		pl.metaWG.Wait()
 		pl.metaCancel = nil
 	}
 
-	pl.vlcMu.Lock()
-	_ = pl.p.Stop()
-	pl.vlcMu.Unlock()
-
 	pl.mu.Lock()
+	fade := pl.fadeOut > 0 && pl.isPlaying && !pl.muted
+	fadeOut := pl.fadeOut
+	ctx := pl.startFadeLocked()
+	from := pl.outVolume
 	pl.isPlaying = false
+	pl.stopStaleTimerLocked()
 	pl.currentTitle, pl.pendingTitle = "", ""
 	pl.firstSeenPending = time.Time{}
+	pl.flaps.reset()
+	pl.buffer.idle()
+	if !fade {
+		pl.cancelFadeLocked()
+		pl.mu.Unlock()
+		pl.stopVLC()
+		return
+	}
+	pl.stopPending = true
+	pl.stopWG.Add(1)
 	pl.mu.Unlock()
+	// the fade-out runs in the background so the caller is not held up;
+	// anything that touches the media next waits for it in finishPendingStop
+	go func() {
+		defer pl.stopWG.Done()
+		pl.runFade(ctx, from, 0, fadeOut)
+		pl.stopVLC()
+	}()
 }
 
-// ToggleMute flips the mute state, returning the new muted flag and volume.
-func (pl *Player) ToggleMute() (bool, int) {
+// finishPendingStop cuts short a fade-out started by Stop and waits until
+// libVLC has stopped, so new media or Play is not stopped by it later.
+func (pl *Player) finishPendingStop() {
+	pl.mu.Lock()
+	pending := pl.stopPending
+	if pending {
+		pl.cancelFadeLocked()
+	}
+	pl.mu.Unlock()
+	if pending {
+		pl.stopWG.Wait()
+	}
+}
+
+// stopVLC stops libVLC, then restores its mute state and the player's
+// volume, which a fade-out has ramped down, so the next Play (or a disabled
+// fade-in) is not silent.
+func (pl *Player) stopVLC() {
 	pl.vlcMu.Lock()
-	pl.p.ToggleMute()
-	pl.vlcMu.Unlock()
+	defer pl.vlcMu.Unlock()
+	pl.mu.Lock()
+	pl.stopPending = false
+	pl.mu.Unlock()
+	if pl.p == nil {
+		return
+	}
+	_ = pl.p.Stop()
+	pl.mu.Lock()
+	muted, v := pl.muted, pl.volume
+	pl.mu.Unlock()
+	// a mute fade cut short by Stop has not muted libVLC yet
+	pl.setVLCMuteLocked(muted)
+	_ = pl.p.SetVolume(v)
+	pl.mu.Lock()
+	pl.outVolume = v
+	pl.mu.Unlock()
+}
 
+// ToggleMute flips the mute state, returning the new muted flag and volume.
+func (pl *Player) ToggleMute() (bool, int) {
 	pl.mu.Lock()
-	pl.muted = !pl.muted
+	m := !pl.muted
 	v := pl.volume
 	pl.mu.Unlock()
-	return pl.muted, v
+	_ = pl.SetMute(m)
+	return m, v
 }
 
-// SetVolume clamps and applies an absolute volume level (0-100).
+// SetVolume clamps and applies an absolute volume level (0-100), cancelling
+// any fade in progress.
 func (pl *Player) SetVolume(v int) error {
 	v = clamp(v, 0, 100)
+	pl.mu.Lock()
+	pl.cancelFadeLocked()
+	pl.mu.Unlock()
+
 	pl.vlcMu.Lock()
 	err := pl.p.SetVolume(v)
 	pl.vlcMu.Unlock()
 
 	pl.mu.Lock()
 	pl.volume = v
+	pl.outVolume = v
 	pl.mu.Unlock()
 	return err
 }
 
-// SetMute ensures libVLC reflects the requested mute state.
+// SetMute ensures libVLC reflects the requested mute state at once,
+// cancelling a SetMuteFaded fade and restoring the set volume.
 func (pl *Player) SetMute(m bool) error {
+	pl.mu.Lock()
+	pl.cancelFadeLocked()
+	pl.muted = m
+	v := pl.volume
+	pl.mu.Unlock()
+
 	pl.vlcMu.Lock()
-	if m != pl.muted {
-		pl.p.ToggleMute()
-	}
+	pl.setVLCMuteLocked(m)
+	_ = pl.p.SetVolume(v)
 	pl.vlcMu.Unlock()
 
 	pl.mu.Lock()
-	pl.muted = m
+	pl.outVolume = v
 	pl.mu.Unlock()
 	return nil
 }
 
-// Vol adjusts the current volume by the provided delta (clamped to 0-100).
+// Vol adjusts the current volume by the provided delta (clamped to 0-100),
+// cancelling any fade in progress.
 func (pl *Player) Vol(delta int) int {
 	pl.mu.Lock()
+	pl.cancelFadeLocked()
 	newV := clamp(pl.volume+delta, 0, 100)
 	pl.volume = newV
+	pl.outVolume = newV
 	pl.mu.Unlock()
 
 	pl.vlcMu.Lock()
@@ -423,30 +1115,41 @@ func (pl *Player) remainingDur() (time.Duration, bool) {
 // new pending title is first seen. If more updates arrive with the same title,
 // the delayed apply will still succeed; if a different title arrives, the
 // pending state is replaced and the previous delayed apply becomes a no‑op.
+// A title that was replaced less than flapWindow ago is brought back only
+// once that has passed, so stations flapping between two titles do not make
+// the ticker thrash.
 func (pl *Player) handleICYTitle(raw string) {
 	title := strings.TrimSpace(html.UnescapeString(raw))
 	if title == "" {
 		return
 	}
 
-	// fast path: ignore duplicates
+	// fast path: ignore duplicates, which still show the title is fresh and
+	// drop a candidate that did not hold
 	pl.mu.Lock()
+	pl.touchTitleLocked()
 	if title == pl.currentTitle {
+		pl.pendingTitle = ""
 		pl.mu.Unlock()
 		return
 	}
 
 	now := time.Now()
+	window := pl.stableWindow
 	// New candidate?
 	if title != pl.pendingTitle {
 		pl.pendingTitle = title
 		pl.firstSeenPending = now
-		// Arm a one-shot delayed apply after stableWindow
+		if window == 0 {
+			pl.applyPendingLocked()
+			return
+		}
+		// Arm a one-shot delayed apply after the stable window
 		pending := pl.pendingTitle
 		pl.mu.Unlock()
 
 		go func() {
-			time.Sleep(stableWindow)
+			time.Sleep(window)
 			// Re-check state after delay
 			pl.mu.Lock()
 			// If pending changed or we already applied a different current title, skip
@@ -454,44 +1157,70 @@ func (pl *Player) handleICYTitle(raw string) {
 				pl.mu.Unlock()
 				return
 			}
-			// Optional near-end gating (disabled if remainingDur not available)
-			if rem, ok := pl.remainingDur(); ok && rem > nearEndThreshold {
-				pl.mu.Unlock()
-				return
-			}
-			pl.currentTitle = pending
-			out := pl.currentTitle
-			cbLocal := pl.onNow
-			pl.mu.Unlock()
-			if cbLocal != nil {
-				cbLocal(out)
-			}
+			pl.applyPendingLocked()
 		}()
 		return
 	}
 	// Same pending seen again — if the stable window elapsed, apply immediately
-	first := pl.firstSeenPending
-	pl.mu.Unlock()
-
-	if time.Since(first) < stableWindow {
+	if time.Since(pl.firstSeenPending) < window {
+		pl.mu.Unlock()
 		return
 	}
+	pl.applyPendingLocked()
+}
+
+// applyPendingLocked makes the pending title current unless near-end gating
+// or the flap guard holds it back, then emits onNow. A title the guard holds
+// is applied when the block runs out, if it is still pending; with no stable
+// window the guard is off. Callers hold pl.mu; it is released before the
+// callback runs.
+func (pl *Player) applyPendingLocked() {
+	// Optional near-end gating (disabled if remainingDur not available)
 	if rem, ok := pl.remainingDur(); ok && rem > nearEndThreshold {
+		pl.mu.Unlock()
 		return
 	}
-	pl.mu.Lock()
+	now := time.Now()
+	if pl.stableWindow > 0 {
+		if wait := pl.flaps.wait(pl.pendingTitle, now, pl.flapFor()); wait > 0 {
+			pending := pl.pendingTitle
+			pl.mu.Unlock()
+			time.AfterFunc(wait, func() {
+				pl.mu.Lock()
+				if pl.pendingTitle != pending || pl.currentTitle == pending {
+					pl.mu.Unlock()
+					return
+				}
+				pl.applyPendingLocked()
+			})
+			return
+		}
+	}
+	pl.flaps.replaced(pl.currentTitle, now)
 	pl.currentTitle = pl.pendingTitle
+	pl.recordTitleLocked(pl.currentTitle)
 	applied := pl.currentTitle
-	cbNow := pl.onNow
+	cb := pl.onNow
 	pl.mu.Unlock()
-	if cbNow != nil {
-		cbNow(applied)
+	if cb != nil {
+		cb(applied)
 	}
 }
 
+// flapFor returns how long a replaced title stays blocked.
+func (pl *Player) flapFor() time.Duration {
+	if pl.flapAfter > 0 {
+		return pl.flapAfter
+	}
+	return flapWindow
+}
+
 // ----------------- Metadata (VLC3) -----------------
 
-// startMetaPoll reads Meta only after media parsing completes successfully.
+// startMetaPoll reads Meta once it is safe for the running libVLC: after
+// media parsing completes on VLC 3, while playing on VLC 4. Titles found go
+// through handleICYTitle like the ICY watcher's, so whichever source reports
+// a title first shows it and the other confirms it.
 func (pl *Player) startMetaPoll() {
 	pl.mu.Lock()
 	onNow := pl.onNow
@@ -499,6 +1228,10 @@ func (pl *Player) startMetaPoll() {
 	if onNow == nil {
 		return
 	}
+	src := metaSourceFor(pl.vlcMajor)
+	if src == metaNone {
+		return
+	}
 
 	pl.vlcMu.Lock()
 	media := pl.media // snapshot
@@ -522,7 +1255,11 @@ func (pl *Player) startMetaPoll() {
 		defer pl.metaWG.Done()
 
 		// give media time to parse; first pass happens after the parse timeout
-		first := time.NewTimer(time.Duration(pl.parseTimeout+300) * time.Millisecond)
+		firstDelay := time.Duration(pl.parseTimeout+300) * time.Millisecond
+		if src == metaWhilePlaying {
+			firstDelay = time.Second
+		}
+		first := time.NewTimer(firstDelay)
 		ticker := time.NewTicker(5 * time.Second)
 		defer func() {
 			first.Stop()
@@ -540,9 +1277,13 @@ func (pl *Player) startMetaPoll() {
 				return
 			}
 
-			// read parse status; Meta is safer to access after DONE
-			status, _ := media.ParseStatus()
-			if status != vlc.MediaParseDone {
+			// read parse status; on VLC 3 Meta is safer to access after DONE
+			parseDone := false
+			if src == metaAfterParse {
+				status, _ := media.ParseStatus()
+				parseDone = status == vlc.MediaParseDone
+			}
+			if !metaReadable(src, parseDone, pl.IsPlaying()) {
 				return
 			}
 
@@ -559,14 +1300,13 @@ func (pl *Player) startMetaPoll() {
 			if s == "" {
 				s = strings.Trim(strings.TrimSpace(artist+" - "+title), " -")
 			}
-			if s != "" && s != "-" && s != last {
+			pl.mu.Lock()
+			filter := pl.titleFilter
+			pl.mu.Unlock()
+			s, ok := filter.Apply(s)
+			if ok && s != "-" && s != last {
 				last = s
-				pl.mu.Lock()
-				cb := pl.onNow
-				pl.mu.Unlock()
-				if cb != nil {
-					cb(s)
-				}
+				pl.handleICYTitle(s)
 			}
 		}
 
@@ -589,45 +1329,6 @@ func (pl *Player) startMetaPoll() {
 	}()
 }
 
-// ----------------- Safe mode (without Meta) -----------------
-
-func (pl *Player) startSafeTickerOnly() {
-	// stop previous poller
-	if pl.metaCancel != nil {
-		pl.metaCancel()
-		pl.metaWG.Wait()
-		pl.metaCancel = nil
-	}
-	ctx, cancel := context.WithCancel(context.Background())
-	pl.metaCancel = cancel
-	pl.metaWG.Add(1)
-
-	go func() {
-		defer pl.metaWG.Done()
-		t := time.NewTicker(3 * time.Second)
-		defer t.Stop()
-		alt := false
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-t.C:
-				pl.mu.Lock()
-				cb := pl.onNow
-				pl.mu.Unlock()
-				if cb != nil {
-					if alt {
-						cb("Streaming…")
-					} else {
-						cb("Playing…")
-					}
-					alt = !alt
-				}
-			}
-		}
-	}()
-}
-
 // ----------------- ICY watcher integration -----------------
 
 // startICYWatcher starts a background goroutine that reads ICY metadata from the same stream URL
@@ -647,35 +1348,71 @@ func (pl *Player) startICYWatcher(url string) {
 	pl.icyWG.Add(1)
 
 	pl.mu.Lock()
+	pl.watchURL = url
 	cbStation := pl.onStation
+	cbArtwork := pl.onArtwork
+	enricher := pl.enricher
+	filter := pl.titleFilter
 	hint := metadata.StrategyHint{Type: pl.metadataHintType, URL: pl.metadataHintURL}
 	resolvedCb := pl.onMetadataResolved
+	provider := pl.metaProvider
 	pl.mu.Unlock()
 
 	go func() {
 		defer pl.icyWG.Done()
 		didSendStation := false
-		provider := pl.metaProvider
 		if provider == nil {
-			provider = metadata.NewProvider(nil, stdLogger{})
+			provider = metadata.NewProvider(nil, stdLogger{}, metadata.ProviderOptions{})
 		}
 		provider.Watch(ctx, url, hint, func(info metadata.Info) {
+			// any update, a keep-alive included, shows the source is alive
+			pl.mu.Lock()
+			pl.touchTitleLocked()
+			pl.mu.Unlock()
+			if info == (metadata.Info{}) {
+				return
+			}
+			if info.Bitrate > 0 || info.Genre != "" || info.Listeners > 0 || info.ListenURL != "" {
+				pl.mu.Lock()
+				if info.Bitrate > 0 {
+					pl.icyBitrate = info.Bitrate
+				}
+				if info.Genre != "" {
+					pl.icyGenre = info.Genre
+				}
+				if info.Listeners > 0 {
+					pl.icyListeners = info.Listeners
+				}
+				if info.ListenURL != "" {
+					pl.icyListenURL = info.ListenURL
+				}
+				pl.mu.Unlock()
+			}
 			// push station name once when available (HTML-unescaped)
-			if !didSendStation && cbStation != nil {
+			if !didSendStation {
 				name := strings.TrimSpace(info.Station)
 				if name != "" {
 					didSendStation = true
-					cbStation(html.UnescapeString(name))
+					name = html.UnescapeString(name)
+					pl.mu.Lock()
+					pl.station = name
+					pl.mu.Unlock()
+					if cbStation != nil {
+						cbStation(name)
+					}
 				}
 			}
 			// stabilize and emit Now Playing title
-			if s := strings.TrimSpace(info.Title); s != "" {
+			if s, ok := filter.Apply(strings.TrimSpace(info.Title)); ok {
 				pl.handleICYTitle(s)
+				pl.trackArtwork(ctx, s, info.ArtworkURL, enricher, cbArtwork)
 			}
 		}, func(sel metadata.StrategyHint) {
+			sel = rememberedHint(sel, url)
 			pl.mu.Lock()
 			pl.metadataHintType = sel.Type
 			pl.metadataHintURL = sel.URL
+			pl.rescanning = false
 			pl.mu.Unlock()
 			if resolvedCb != nil {
 				resolvedCb(sel.Type, sel.URL)
@@ -684,6 +1421,22 @@ func (pl *Player) startICYWatcher(url string) {
 	}()
 }
 
+// RescanMetadata forgets the metadata strategy of the playing stream and runs
+// discovery afresh; if that finds nothing, the metadata-unavailable callback
+// fires even though the old title is still shown. Only the metadata
+// connection restarts; audio keeps playing. When stopped it just clears the
+// hint.
+func (pl *Player) RescanMetadata() {
+	pl.mu.Lock()
+	playing, u := pl.isPlaying, pl.stream
+	pl.metadataHintType, pl.metadataHintURL = "", ""
+	pl.rescanning = playing
+	pl.mu.Unlock()
+	if playing {
+		pl.startICYWatcher(u)
+	}
+}
+
 // stopICYWatcher cancels and waits for the ICY watcher goroutine to exit.
 func (pl *Player) stopICYWatcher() {
 	if pl.icyCancel != nil {
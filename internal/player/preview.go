@@ -0,0 +1,55 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrPreviewWhilePlaying is returned by Preview while audio is playing; the
+// playing stream's titles are already on the ticker.
+var ErrPreviewWhilePlaying = errors.New("stop playback before previewing a station")
+
+// Preview watches the now-playing metadata of url without playing it. Titles
+// and station names reach the OnNow and OnStation callbacks as during
+// playback, but libVLC is not touched: only the metadata source is read, so
+// nothing is heard. Previewed titles are not added to History. Load, Play and
+// StopPreview end the preview; a new Preview replaces it.
+func (pl *Player) Preview(ctx context.Context, url string) error {
+	if pl.IsPlaying() {
+		return ErrPreviewWhilePlaying
+	}
+	pl.resetStreamState()
+	u := pl.streamURL(ctx, url)
+	if strings.TrimSpace(u) == "" {
+		return errors.New("empty stream URL")
+	}
+	pl.mu.Lock()
+	pl.previewing = true
+	pl.mu.Unlock()
+	pl.startICYWatcher(u)
+	return nil
+}
+
+// StopPreview ends a preview started by Preview and forgets its titles. It
+// does nothing when no preview runs.
+func (pl *Player) StopPreview() {
+	if !pl.IsPreviewing() {
+		return
+	}
+	pl.stopICYWatcher()
+	pl.mu.Lock()
+	pl.previewing = false
+	pl.stopStaleTimerLocked()
+	pl.currentTitle, pl.pendingTitle = "", ""
+	pl.station = ""
+	pl.flaps.reset()
+	pl.mu.Unlock()
+}
+
+// IsPreviewing reports whether a preview runs.
+func (pl *Player) IsPreviewing() bool {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.previewing
+}
@@ -0,0 +1,211 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// silencePoll is how often the dead-air watchdog checks the stream.
+	silencePoll = time.Second
+	// silenceFloorDB is the RMS level, in dBFS, below which audio counts as
+	// silent; the noise floor of a live mic or an idle encoder sits well
+	// under it, quiet music well above.
+	silenceFloorDB = -50.0
+	// levelHeardWithin is how recently the level meter must have received
+	// samples for its reading to count; a meter that stopped hearing
+	// anything says nothing about the level of the stream.
+	levelHeardWithin = 3 * silencePoll
+)
+
+// ErrDeadAir is reported through the error callback when a playing stream
+// has been silent, or delivered no audio at all, for the silence timeout.
+var ErrDeadAir = errors.New("dead air")
+
+// SetOnError registers a callback for problems found while playing, such as
+// ErrDeadAir. It runs on its own goroutine, so it may stop or restart
+// playback.
+func (pl *Player) SetOnError(fn func(error)) {
+	pl.mu.Lock()
+	pl.onError = fn
+	pl.mu.Unlock()
+}
+
+// SetSilenceTimeout sets how long a playing stream may stay below
+// silenceFloorDB, or deliver no audio to the output, before ErrDeadAir is
+// reported; zero disables detection. The level is measured by a second,
+// silent libVLC player on the same stream, so detection costs one more
+// connection to the station. It takes effect the next time playback starts.
+func (pl *Player) SetSilenceTimeout(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	pl.mu.Lock()
+	pl.silenceTimeout = d
+	pl.mu.Unlock()
+}
+
+// stallMeter tracks the audio counters libVLC keeps per media: a stall is no
+// audio buffers reaching the output, because the connection stalled or the
+// server stopped sending while the player still reports Playing.
+type stallMeter struct {
+	played   int
+	since    time.Time
+	reported bool
+}
+
+// observe records the played-buffer count sampled at now and reports whether
+// the stream has now been stalled for timeout. It fires once per stall and
+// re-arms as soon as audio flows again.
+func (m *stallMeter) observe(played int, now time.Time, timeout time.Duration) bool {
+	if m.since.IsZero() || played != m.played {
+		m.played, m.since, m.reported = played, now, false
+		return false
+	}
+	if m.reported || now.Sub(m.since) < timeout {
+		return false
+	}
+	m.reported = true
+	return true
+}
+
+// levelSink collects the samples a level meter decodes and tracks when the
+// stream was last louder than silenceFloorDB. It is fed from libVLC's audio
+// thread and read by the watchdog.
+type levelSink struct {
+	mu       sync.Mutex
+	heard    time.Time
+	loud     time.Time
+	reported bool
+}
+
+// feed records a block of 16-bit samples received at now.
+func (s *levelSink) feed(samples []int16, now time.Time) {
+	db := rmsDBFS(samples)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heard = now
+	switch {
+	case db >= silenceFloorDB:
+		s.loud, s.reported = now, false
+	case s.loud.IsZero():
+		// silence is counted from the first samples heard
+		s.loud = now
+	}
+}
+
+// silent reports whether the stream has now stayed below silenceFloorDB for
+// timeout while samples kept arriving. It fires once per silence and re-arms
+// as soon as the level comes back.
+func (s *levelSink) silent(now time.Time, timeout time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.heard.IsZero() || now.Sub(s.heard) > levelHeardWithin {
+		return false
+	}
+	if s.reported || now.Sub(s.loud) < timeout {
+		return false
+	}
+	s.reported = true
+	return true
+}
+
+// rmsDBFS returns the RMS level of samples in dB relative to full scale;
+// digital silence, and an empty block, is -Inf.
+func rmsDBFS(samples []int16) float64 {
+	if len(samples) == 0 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for _, v := range samples {
+		f := float64(v) / 32768
+		sum += f * f
+	}
+	rms := math.Sqrt(sum / float64(len(samples)))
+	if rms == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(rms)
+}
+
+// startSilenceWatch begins watching the current stream for dead air when a
+// silence timeout is set, replacing any watch already running.
+func (pl *Player) startSilenceWatch() {
+	pl.stopSilenceWatch()
+	pl.mu.Lock()
+	timeout := pl.silenceTimeout
+	u := pl.stream
+	if timeout <= 0 {
+		pl.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	pl.silenceCancel = cancel
+	pl.mu.Unlock()
+
+	pl.silenceWG.Add(1)
+	go func() {
+		defer pl.silenceWG.Done()
+		// without a level meter only stalls are caught
+		var levels *levelSink
+		if meter, err := startLevelMeter(pl.mediaURL(u), pl.streamOptions()); err != nil {
+			log.Printf("level meter unavailable: %v", err)
+		} else {
+			levels = meter.levels
+			// stopping libVLC can wait on the network; nobody needs to
+			defer func() { go meter.close() }()
+		}
+		t := time.NewTicker(silencePoll)
+		defer t.Stop()
+		var stall stallMeter
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-t.C:
+				pl.vlcMu.Lock()
+				played, ok := -1, false
+				if pl.media != nil {
+					if st, err := pl.media.Stats(); err == nil {
+						played, ok = st.PlayedAudioBuffers, true
+					}
+				}
+				pl.vlcMu.Unlock()
+				var err error
+				switch {
+				case ok && stall.observe(played, now, timeout):
+					err = fmt.Errorf("%w: no audio reached the output for %s", ErrDeadAir, timeout)
+				case levels != nil && levels.silent(now, timeout):
+					err = fmt.Errorf("%w: silent for %s", ErrDeadAir, timeout)
+				}
+				if err == nil || ctx.Err() != nil {
+					continue
+				}
+				pl.forgetResolved()
+				pl.mu.Lock()
+				cb := pl.onError
+				pl.mu.Unlock()
+				if cb != nil {
+					go cb(err)
+				}
+			}
+		}
+	}()
+}
+
+// stopSilenceWatch ends the dead-air watch, if any, and waits for it to exit.
+func (pl *Player) stopSilenceWatch() {
+	pl.mu.Lock()
+	cancel := pl.silenceCancel
+	pl.silenceCancel = nil
+	pl.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		pl.silenceWG.Wait()
+	}
+}
@@ -0,0 +1,70 @@
+package player
+
+import "time"
+
+// DefaultStaleTitleTimeout is how long the current title may go unconfirmed
+// by the metadata source before it is cleared; see SetStaleTitleTimeout.
+const DefaultStaleTitleTimeout = 10 * time.Minute
+
+// SetStaleTitleTimeout sets how long the current title stays up once the
+// metadata source stops answering: every metadata block, keep-alive or poll
+// counts, with or without a title, so a long track on a healthy stream keeps
+// its title. A source that went away would otherwise leave the last song
+// showing forever; on expiry the title is cleared and onNow reports the
+// station name, or "Streaming…" before one is known. Zero keeps titles until
+// they change. It takes effect with the next update.
+func (pl *Player) SetStaleTitleTimeout(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	pl.mu.Lock()
+	pl.staleAfter = d
+	pl.mu.Unlock()
+}
+
+// touchTitleLocked notes that the metadata source just answered and re-arms
+// the stale-title timer. Callers hold pl.mu.
+func (pl *Player) touchTitleLocked() {
+	pl.stopStaleTimerLocked()
+	if pl.staleAfter <= 0 {
+		return
+	}
+	gen := pl.staleGen
+	pl.staleTimer = time.AfterFunc(pl.staleAfter, func() { pl.expireTitle(gen) })
+}
+
+// stopStaleTimerLocked disarms the stale-title timer; one already firing
+// sees the generation change and does nothing. Callers hold pl.mu.
+func (pl *Player) stopStaleTimerLocked() {
+	if pl.staleTimer != nil {
+		pl.staleTimer.Stop()
+		pl.staleTimer = nil
+	}
+	pl.staleGen++
+}
+
+// expireTitle clears the current title once the timer armed at generation gen
+// runs out, and reports the neutral text in its place.
+func (pl *Player) expireTitle(gen int) {
+	pl.mu.Lock()
+	if gen != pl.staleGen || pl.currentTitle == "" {
+		pl.mu.Unlock()
+		return
+	}
+	pl.staleTimer = nil
+	pl.currentTitle, pl.pendingTitle = "", ""
+	pl.firstSeenPending = time.Time{}
+	// a preview goes back to waiting for a title
+	msg := ""
+	if !pl.previewing {
+		msg = pl.station
+		if msg == "" {
+			msg = "Streaming…"
+		}
+	}
+	cb := pl.onNow
+	pl.mu.Unlock()
+	if cb != nil {
+		cb(msg)
+	}
+}
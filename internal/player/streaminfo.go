@@ -0,0 +1,91 @@
+package player
+
+import (
+	"strings"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// codecNames maps libVLC audio fourccs to the names listeners know.
+var codecNames = map[string]string{
+	"mp4a": "AAC",
+	"mpga": "MP3",
+	"mp3 ": "MP3",
+	"opus": "Opus",
+	"vorb": "Vorbis",
+	"flac": "FLAC",
+	"a52 ": "AC-3",
+	"araw": "PCM",
+}
+
+// StreamInfo reports the technical details of the current stream: codec,
+// bitrate in kbps, sample rate in Hz and channel count. libVLC knows the codec
+// once the media has been parsed; when it cannot tell the bitrate, the
+// measured demux rate and then the server's icy-br header are used instead.
+// ok is false before anything is known.
+func (pl *Player) StreamInfo() (codec string, bitrateKbps int, sampleRate int, channels int, ok bool) {
+	pl.vlcMu.Lock()
+	media := pl.media
+	if media != nil {
+		if tracks, err := media.Tracks(); err == nil {
+			for _, t := range tracks {
+				if t == nil || t.Type != vlc.MediaTrackAudio {
+					continue
+				}
+				codec = codecName(t.Codec)
+				bitrateKbps = int(t.BitRate / 1000)
+				if t.Audio != nil {
+					sampleRate = int(t.Audio.Rate)
+					channels = int(t.Audio.Channels)
+				}
+				ok = true
+				break
+			}
+		}
+		if bitrateKbps == 0 {
+			// libVLC reports stats in kB per ms
+			if st, err := media.Stats(); err == nil && st.DemuxBitRate > 0 {
+				bitrateKbps = int(st.DemuxBitRate*8000 + 0.5)
+			}
+		}
+	}
+	pl.vlcMu.Unlock()
+
+	if bitrateKbps == 0 {
+		pl.mu.Lock()
+		bitrateKbps = pl.icyBitrate
+		pl.mu.Unlock()
+	}
+	if bitrateKbps > 0 {
+		ok = true
+	}
+	return codec, bitrateKbps, sampleRate, channels, ok
+}
+
+// Genre returns the icy-genre header of the current stream, if any.
+func (pl *Player) Genre() string {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.icyGenre
+}
+
+// StationDetails returns what the metadata source told about the current
+// station beyond its titles: the ICY or status-json name, genre and bitrate
+// in kbps, and from Icecast status-json the listener count and public stream
+// URL. Unknown values are zero.
+func (pl *Player) StationDetails() (name, genre string, bitrateKbps, listeners int, listenURL string) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.station, pl.icyGenre, pl.icyBitrate, pl.icyListeners, pl.icyListenURL
+}
+
+// codecName turns a libVLC fourcc into a display name, falling back to the
+// fourcc itself.
+func codecName(fourcc uint) string {
+	b := []byte{byte(fourcc), byte(fourcc >> 8), byte(fourcc >> 16), byte(fourcc >> 24)}
+	code := string(b)
+	if name, ok := codecNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	return strings.ToUpper(strings.TrimSpace(strings.Trim(code, "\x00")))
+}
@@ -4,10 +4,13 @@ package radioapp
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"html"
 	"image/color"
 	"log"
+	"net/http"
 	"strings"
 	"time"
 
@@ -16,12 +19,16 @@ import (
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	config "github.com/edward-ap/miniradio/internal/config"
 	eqmodel "github.com/edward-ap/miniradio/internal/equalizer"
+	metadata "github.com/edward-ap/miniradio/internal/metadata"
+	mediakeys "github.com/edward-ap/miniradio/internal/platform/win/mediakeys"
+	smtc "github.com/edward-ap/miniradio/internal/platform/win/smtc"
 	windowpos "github.com/edward-ap/miniradio/internal/platform/win/windowpos"
 	playerpkg "github.com/edward-ap/miniradio/internal/player"
 	ui "github.com/edward-ap/miniradio/internal/ui"
@@ -47,6 +54,8 @@ const (
 	DrawerSettings
 	// DrawerEqualizer shows the EQ sliders and preset list.
 	DrawerEqualizer
+	// DrawerHistory lists recently played titles.
+	DrawerHistory
 )
 
 // UIState stores high-level visual selections so they can be persisted or
@@ -75,6 +84,18 @@ type App struct {
 	customEQIndex map[string]int // name -> index in Config.CustomEQPresets
 	drawerMode    string         // "", "settings", "equalizer"
 	eqModel       *eqmodel.EQModel
+	// vlcErr is why libVLC failed to start; the app then runs in safe mode
+	// with playback off while presets stay editable. eqErr is why the
+	// equalizer is unavailable, which leaves playback working.
+	vlcErr error
+	eqErr  error
+
+	// previewIdx is the preset whose titles a running preview shows, see
+	// previewPreset
+	previewIdx int
+	// resolveGen counts stream lookups; only the newest one may go on to
+	// play, see resolveThen
+	resolveGen int
 
 	// UI elements
 	playBtn     *widget.Button
@@ -89,19 +110,39 @@ type App struct {
 	settingsBg *canvas.Rectangle
 	eqBtnBg    *canvas.Rectangle
 
+	// first-run hint beside settingsBtn; hidden once dismissed
+	onboardingHint *fyne.Container
+
 	// ticker visuals
 	tickerBg *canvas.Rectangle
 
+	// palette-coloured backgrounds repainted on theme changes
+	playBg      *canvas.Rectangle
+	rightBg     *canvas.Rectangle
+	drawerPaint *canvas.Rectangle
+
 	// volume controls
 	volBtn    *widget.Button
 	volSlider *ui.MiniThumbSlider
+	// volLabel shows the level as "NN%" beside the slider (nil when hidden)
+	volLabel *widget.Label
+	// volSaveTimer debounces config saves while the volume changes, see
+	// saveVolumeSoon
+	volSaveTimer *time.Timer
 
 	// stream indicator (circle that animates when active)
 	ind *ui.StreamIndicator
 
+	// station details shown beside the indicator while it is hovered
+	stationTip *ui.ValueTip
+
 	// ticker controller
 	ticker *ui.TickerController
 
+	// OS media overlay session (Windows SMTC); nil where unsupported.
+	// Accessed on the UI thread only.
+	media *smtc.Session
+
 	shortcutCatcher *shortcutCatcher
 
 	// root containers
@@ -119,41 +160,73 @@ type App struct {
 	drawerHeight  float32
 	drawerContent fyne.CanvasObject
 	baseHeight    float32
+	// sizeScale is the SizeMode factor the strip was built with; a changed
+	// mode applies on the next start.
+	sizeScale     float32
 	pendingDrawer string
 
-	// controls inside settings drawer for single-select behavior
-	settingsRadios [10]*widget.Check
+	// controls inside settings drawer for single-select behavior; sized to
+	// len(config.Presets) whenever the drawer is built
+	settingsRadios []*widget.Check
 	// keep references to EQ preset selects in Settings for synchronization
-	eqPresetSelects [10]*widget.Select
+	eqPresetSelects []*widget.Select
 	// reference to EQ drawer preset select for cross-sync
 	eqDrawerPreset *widget.Select
 	// EQ drawer UI helpers
 	eqSliderValues  []float64
 	eqPreampSlider  *ui.VerticalSlider
+	eqCurve         *ui.EQCurve
+	eqTip           *ui.ValueTip
+	eqToneSliders   []*ui.VerticalSlider // bass, mid, treble in tone mode
+	eqGraphicView   fyne.CanvasObject
+	eqToneView      fyne.CanvasObject
 	eqBandSliders   []*ui.VerticalSlider
 	eqNameEntry     *widget.Entry
 	eqSaveButton    *widget.Button
 	eqDeleteButton  *widget.Button
+	eqExportButton  *widget.Button
 	eqCustomNames   []string
 	eqCustomMap     map[string]config.EQPresetData
 	eqSlidersSilent bool
-	silentUpdating  bool
+	// eqManual is set once sliders are edited away from the selected preset
+	eqManual       bool
+	silentUpdating bool
+	// eqUndo holds the custom EQ preset just deleted while it can be undone
+	eqUndo *eqDeletion
+	// tickerAction runs when the ticker is tapped, see showTickerAction
+	tickerAction func()
 	// after first successful Play we allow radios interaction even when stopped later
 	playedOnce bool
+	// rescanning is set by a metadata rescan until discovery reports back
+	rescanning bool
+
+	// station requested on the command line, started once VLC is ready
+	start StartOptions
+	// local HTTP control API; nil unless Config.RemoteControl is set
+	remote *http.Server
+	// watcher for outside edits to config.json; nil unless
+	// Config.WatchConfigFile is set
+	configWatch *config.Watcher
 }
 
 // NewApp wires configuration, player, and fyne scaffolding into a ready-to-run
-// App instance.
-func NewApp() *App {
+// App instance. opts names a station to start playing once VLC is ready; an
+// error is returned, before any window is created, when opts do not fit the
+// loaded configuration.
+func NewApp(opts StartOptions) (*App, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		log.Println("config load error:", err)
-		cfg = &config.Config{CurrentURL: config.DefaultTestURL, Volume: config.DefaultVolume, WindowW: config.DefaultWidth, WindowH: config.DefaultHeight}
+		cfg = config.NewDefault()
+	}
+	if err := opts.validate(cfg); err != nil {
+		return nil, err
 	}
+	applyStartURL(cfg, opts.URL)
 
 	fa := app.NewWithID(config.AppID)
-	// Switch to dark theme early for better contrast
-	fa.Settings().SetTheme(theme.DarkTheme())
+	// Apply the configured theme (dark by default) before any window exists
+	fa.Settings().SetTheme(ui.ScaledTheme(baseTheme(cfg.Theme), config.SizeScale(cfg.SizeMode)))
 	// Set application icon
 	if AppIcon != nil {
 		fa.SetIcon(AppIcon)
@@ -161,20 +234,31 @@ func NewApp() *App {
 	w := fa.NewWindow("MiniRadio")
 	w.SetMaster()
 	w.SetPadded(false)
-	// Disable window resizing/maximize; keep narrow strip height on start
+	// No OS resizing or maximize: the height always follows the strip plus
+	// the open drawer (see resizeWindowForDrawer), and the width is dragged
+	// with the grip at the strip's right edge (see widenBy)
 	w.SetFixedSize(true)
 	if AppIcon != nil {
 		w.SetIcon(AppIcon)
 	}
-	w.Resize(fyne.NewSize(float32(cfg.WindowW), float32(config.DefaultHeight)))
+	w.Resize(fyne.NewSize(float32(cfg.WindowW), config.DefaultHeight*config.SizeScale(cfg.SizeMode)))
 
 	p := playerpkg.NewPlayer()
+	p.ConfigureMetadata(metadataOptions(cfg))
+	p.SetIPVersion(ipVersion(cfg))
+	p.SetCoverArtLookup(cfg.CoverArtLookup)
+	p.SetFadeDurations(fadeDurations(cfg))
+	p.SetHistorySize(cfg.HistorySize)
+	p.SetSilenceTimeout(time.Duration(cfg.SilenceTimeoutMs) * time.Millisecond)
+	p.SetStaleTitleTimeout(staleTitleTimeout(cfg))
+	p.SetAudioFilters(audioFilters(cfg))
 
 	app := &App{
 		fa:     fa,
 		w:      w,
 		player: p,
 		config: cfg,
+		start:  opts,
 		playerState: PlayerState{
 			CurrentURL: cfg.CurrentURL,
 			IsPlaying:  false,
@@ -197,9 +281,15 @@ func NewApp() *App {
 		app.playerState.CurrentEQ = name
 		app.uiState.SelectedEQPreset = name
 	}
+	app.playerState.Volume = app.effectiveVolume()
+	app.loadHistory()
 
 	app.buildUI()
+	app.watchTheme()
 	app.restoreWindowPlacement()
+	app.registerMediaKeys()
+	app.startRemoteControl()
+	app.startConfigWatch()
 	if idx := app.currentPresetIndex(); idx >= 0 && idx < len(app.config.Presets) {
 		app.setWindowTitleForName(app.config.Presets[idx].Name)
 	}
@@ -209,19 +299,21 @@ func NewApp() *App {
 
 	// Initialize VLC asynchronously to avoid blocking UI startup
 	go func() {
-		if err := p.Init(cfg.Volume, cfg.Muted); err != nil {
+		if err := p.Init(app.effectiveVolume(), cfg.Muted); err != nil {
 			ui.CallOnMain(func() {
 				// Show friendly message about VLC runtime layout (DLLs + plugins)
 				dialog.ShowError(fmt.Errorf("cannot initialize VLC: %w\n\nPlace next to .exe: libvlc.dll, libvlccore.dll, and the plugins\\ folder (copied entirely from VLC).\nAlternatively install VLC, add its bin to PATH, and point plugins via VLC_PLUGIN_PATH or rely on the system path.\nArchitectures must match (x64 ⇔ x64).", err), w)
-				if app.ticker != nil {
-					app.ticker.SetText("VLC init failed")
-				}
+				app.enterSafeMode(err)
 			})
 			return
 		}
 
 		// Set callbacks after init
 		p.SetOnNow(func(s string) {
+			if p.IsPreviewing() {
+				ui.CallOnMain(func() { app.showPreviewTitle(s) })
+				return
+			}
 			if app.ticker != nil {
 				msg := s
 				if strings.TrimSpace(msg) == "" {
@@ -229,8 +321,12 @@ func NewApp() *App {
 				}
 				ui.CallOnMain(func() { app.ticker.SetText(msg) })
 			}
+			ui.CallOnMain(func() { app.media.SetTrack(strings.TrimSpace(s)) })
 		})
 		p.SetOnStation(func(name string) {
+			if p.IsPreviewing() {
+				return
+			}
 			raw := strings.TrimSpace(html.UnescapeString(name))
 			idx := app.currentPresetIndex()
 			display := raw
@@ -240,25 +336,53 @@ func NewApp() *App {
 			app.setWindowTitleForName(display)
 			app.storePresetNameIfMissing(idx, raw)
 		})
+		p.SetOnBuffering(func(pct float64) {
+			ui.CallOnMain(func() { app.showBuffering(pct) })
+		})
+		p.SetOnError(func(err error) {
+			ui.CallOnMain(func() { app.handlePlaybackError(err) })
+		})
+		p.SetOnSecurityError(func(err error) {
+			log.Printf("metadata: %v", err)
+			app.UpdateTicker("Metadata blocked: " + err.Error())
+		})
+		p.SetOnMetadataUnavailable(func() {
+			ui.CallOnMain(app.handleMetadataUnavailable)
+		})
 
 		// apply initial volume/mute after init
-		_ = p.SetVolume(cfg.Volume)
+		_ = p.SetVolume(app.effectiveVolume())
 		if cfg.Muted {
 			_ = p.SetMute(true)
 		}
+		if cfg.AudioDevice != "" {
+			_ = p.SetAudioDevice(cfg.AudioDevice)
+		}
+		if cfg.Balance != 0 {
+			p.SetBalance(cfg.Balance)
+		}
 
 		// Initialize Equalizer after VLC is ready
-		if e, err := NewEqualizer(); err == nil {
+		e, eqErr := NewEqualizer()
+		if eqErr == nil {
 			app.eq = e
 			_ = app.player.SetAudioEqualizer(app.eq.eq)
 			ui.CallOnMain(func() { app.rebuildCustomEQIndex() })
+		} else {
+			log.Printf("equalizer unavailable: %v", eqErr)
 		}
 
 		ui.CallOnMain(func() {
+			ready := "Ready"
+			if eqErr != nil {
+				app.eqErr = eqErr
+				ready = "Ready (equalizer unavailable)"
+			}
 			if app.ticker != nil {
-				app.ticker.SetText("Ready")
+				app.ticker.SetText(ready)
 			}
 		})
+		app.autoStart()
 	}()
 
 	// window close handler: save size & position, release resources
@@ -272,11 +396,18 @@ func NewApp() *App {
 			cfg.WindowH = config.DefaultHeight
 		}
 		app.captureWindowPlacement()
+		if app.drawerMode == "equalizer" {
+			app.captureManualEQ()
+		}
 		// save config
 		_ = cfg.Save()
+		app.saveHistory()
 		if app.ticker != nil {
 			app.ticker.Close()
 		}
+		app.media.Close()
+		app.stopRemoteControl()
+		app.stopConfigWatch()
 		if app.eq != nil {
 			app.eq.Release()
 		}
@@ -292,10 +423,113 @@ func NewApp() *App {
 	w.Canvas().SetOnTypedKey(func(ke *fyne.KeyEvent) {
 		app.handleShortcutKey(ke)
 	})
+	w.SetOnDropped(app.handleDrop)
+	for key, delta := range map[fyne.KeyName]int{fyne.KeyRight: +1, fyne.KeyLeft: -1} {
+		w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShift}, func(fyne.Shortcut) {
+			app.cycleFavorite(delta)
+		})
+	}
+	// key events carry no modifiers, so Ctrl+C (Cmd+C on macOS) is a canvas
+	// shortcut; a focused entry still handles its own copy first
+	w.Canvas().AddShortcut(&fyne.ShortcutCopy{}, func(fyne.Shortcut) {
+		app.copyCurrentTitle()
+	})
+
+	return app, nil
+}
+
+// metadataOptions maps user preferences onto metadata provider options.
+func metadataOptions(cfg *config.Config) metadata.ProviderOptions {
+	ms := func(v int) time.Duration { return time.Duration(v) * time.Millisecond }
+	return metadata.ProviderOptions{
+		MaxConnections: cfg.MetadataMaxConnections,
+		PollInterval:   ms(cfg.MetadataPollIntervalMs),
+		RequestTimeout: ms(cfg.MetadataRequestTimeoutMs),
+		DialTimeout:    ms(cfg.MetadataDialTimeoutMs),
+		Proxy:          cfg.MetadataProxy,
+		MinTLS:         minTLSVersions[cfg.MetadataMinTLS],
+		IPVersion:      ipVersion(cfg),
+		SiblingOrder:   siblingOrders[cfg.SiblingBitrate],
+
+		DiscoveryTimeout: ms(cfg.MetadataDiscoveryMs),
+	}
+}
+
+// siblingOrders maps Config.SiblingBitrate onto metadata sibling orders; ""
+// keeps the highest-first default.
+var siblingOrders = map[string]string{
+	config.SiblingLowest: metadata.SiblingLowest,
+	config.SiblingMatch:  metadata.SiblingMatch,
+}
+
+// ipVersion maps Config.IPFamily onto the 4/6/0 the player and metadata
+// clients take.
+func ipVersion(cfg *config.Config) int {
+	switch cfg.IPFamily {
+	case config.IPFamilyV4:
+		return 4
+	case config.IPFamilyV6:
+		return 6
+	}
+	return 0
+}
+
+// minTLSVersions maps Config.MetadataMinTLS onto crypto/tls versions; ""
+// leaves the metadata package default.
+var minTLSVersions = map[string]uint16{
+	config.TLS12: tls.VersionTLS12,
+	config.TLS13: tls.VersionTLS13,
+}
+
+// fadeDurations maps the fade preferences onto player durations.
+func fadeDurations(cfg *config.Config) (in, out time.Duration) {
+	in = playerpkg.DefaultFadeIn
+	switch {
+	case cfg.FadeInMs < 0:
+		in = 0
+	case cfg.FadeInMs > 0:
+		in = time.Duration(cfg.FadeInMs) * time.Millisecond
+	}
+	return in, time.Duration(cfg.FadeOutMs) * time.Millisecond
+}
+
+// audioFilters maps the normalization and mono settings to player filters.
+func audioFilters(cfg *config.Config) playerpkg.AudioFilters {
+	return playerpkg.AudioFilters{
+		Normalize:      cfg.Normalize,
+		NormalizeLevel: cfg.NormalizeLevel,
+		Mono:           cfg.Mono,
+	}
+}
+
+// staleTitleTimeout maps StaleTitleMs onto the player's stale-title timeout.
+func staleTitleTimeout(cfg *config.Config) time.Duration {
+	switch {
+	case cfg.StaleTitleMs < 0:
+		return 0
+	case cfg.StaleTitleMs > 0:
+		return time.Duration(cfg.StaleTitleMs) * time.Millisecond
+	}
+	return playerpkg.DefaultStaleTitleTimeout
+}
 
-	return app
+// stableWindow resolves the title stabilization window for preset idx (-1 =
+// no preset): the preset's override, else the global setting, else the player
+// default.
+func stableWindow(cfg *config.Config, idx int) time.Duration {
+	ms := cfg.StableWindowMs
+	if idx >= 0 && idx < len(cfg.Presets) && cfg.Presets[idx].StableWindowMs != nil {
+		ms = cfg.Presets[idx].StableWindowMs
+	}
+	if ms == nil {
+		return playerpkg.DefaultStableWindow
+	}
+	return time.Duration(*ms) * time.Millisecond
 }
 
+// keyToPresetIndex maps the digit row onto the first ten presets (1…9, then 0
+// for the tenth). Presets beyond the tenth have no shortcut and are reachable
+// only from the Settings drawer; callers bounds-check against len(Presets).
 func keyToPresetIndex(key fyne.KeyName) int {
 	switch key {
 	case fyne.Key1:
@@ -328,6 +562,9 @@ func (a *App) handleShortcutKey(ke *fyne.KeyEvent) {
 	if ke == nil {
 		return
 	}
+	if a.keyBelongsToEntry(ke.Name) {
+		return
+	}
 	switch ke.Name {
 	case fyne.KeySpace:
 		a.togglePlay()
@@ -341,12 +578,165 @@ func (a *App) handleShortcutKey(ke *fyne.KeyEvent) {
 		a.changeVolume(-1)
 	case fyne.KeyAsterisk:
 		a.toggleMute()
+	case fyne.KeyRight, fyne.KeyLeft:
+		// like the station radios, cycling waits for the first playback
+		if !a.playedOnce {
+			return
+		}
+		if ke.Name == fyne.KeyRight {
+			a.cyclePreset(+1, false)
+		} else {
+			a.cyclePreset(-1, false)
+		}
 	case fyne.Key1, fyne.Key2, fyne.Key3, fyne.Key4, fyne.Key5, fyne.Key6, fyne.Key7, fyne.Key8, fyne.Key9, fyne.Key0:
 		idx := keyToPresetIndex(ke.Name)
 		a.activatePreset(idx)
 	}
 }
 
+// registerMediaKeys hooks the keyboard's media keys and opens the OS media
+// overlay session where the platform supports them. The native window only
+// exists once the app is running, so both are retried briefly in the
+// background.
+func (a *App) registerMediaKeys() {
+	go func() {
+		const attempts = 20
+		for i := 0; i < attempts; i++ {
+			if mediakeys.Register(a.w, a.handleMediaKey) {
+				break
+			}
+			time.Sleep(150 * time.Millisecond)
+		}
+		for i := 0; i < attempts; i++ {
+			if s := smtc.Open(a.w, a.handleMediaKey); s != nil {
+				ui.CallOnMain(func() {
+					a.media = s
+					s.SetPlaying(a.player != nil && a.player.IsPlaying())
+				})
+				return
+			}
+			time.Sleep(150 * time.Millisecond)
+		}
+	}()
+}
+
+// handleMediaKey maps a hardware media key onto the matching playback action.
+func (a *App) handleMediaKey(k mediakeys.Key) {
+	ui.CallOnMain(func() {
+		switch k {
+		case mediakeys.PlayPause:
+			a.togglePlay()
+		case mediakeys.Play:
+			if a.player == nil || !a.player.IsPlaying() {
+				a.togglePlay()
+			}
+		case mediakeys.Pause, mediakeys.Stop:
+			if a.player != nil && a.player.IsPlaying() {
+				a.togglePlay()
+			}
+			a.cancelPendingPlay()
+		case mediakeys.Next:
+			a.cyclePreset(+1, false)
+		case mediakeys.Previous:
+			a.cyclePreset(-1, false)
+		}
+	})
+}
+
+// cyclePreset activates the next (delta > 0) or previous (delta < 0) preset
+// that has a stream URL, starting from the active station. favorites limits
+// the cycle to starred presets.
+func (a *App) cyclePreset(delta int, favorites bool) {
+	if a == nil || a.config == nil {
+		return
+	}
+	if idx := nextPresetIndex(a.config.Presets, a.currentPresetIndex(), delta, favorites); idx >= 0 {
+		a.activatePreset(idx)
+	}
+}
+
+// cycleFavorite is the Shift+←/→ shortcut: like ←/→ it waits for the first
+// playback, then steps through starred presets only.
+func (a *App) cycleFavorite(delta int) {
+	if !a.playedOnce {
+		return
+	}
+	for _, p := range a.config.Presets {
+		if p.Favorite && strings.TrimSpace(p.URL) != "" {
+			a.cyclePreset(delta, true)
+			return
+		}
+	}
+	a.ShowToast("No favorite stations: star them in Settings")
+}
+
+// nextPresetIndex returns the preset after (delta > 0) or before (delta < 0)
+// cur that has a URL, wrapping around the ends and skipping cur itself. With
+// no active preset (cur < 0) forward starts at the first preset and backward
+// at the last. favorites also skips presets that are not starred. Returns -1
+// when no other preset qualifies.
+func nextPresetIndex(presets []config.Preset, cur, delta int, favorites bool) int {
+	n := len(presets)
+	if n == 0 || delta == 0 {
+		return -1
+	}
+	start := cur
+	if start < 0 || start >= n {
+		start = -1
+		if delta < 0 {
+			start = 0
+		}
+	}
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	for k := 1; k <= n; k++ {
+		i := ((start+step*k)%n + n) % n
+		if i == cur || strings.TrimSpace(presets[i].URL) == "" || (favorites && !presets[i].Favorite) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// keyBelongsToEntry reports whether a shortcut key should be left to a focused
+// text entry (e.g. Space or digits typed into a stream URL) instead of
+// triggering playback actions. Config.EntryShortcuts restores the old
+// always-global behavior.
+func (a *App) keyBelongsToEntry(key fyne.KeyName) bool {
+	if a == nil || a.w == nil || !isTextEntryKey(key) {
+		return false
+	}
+	if a.config != nil && a.config.EntryShortcuts {
+		return false
+	}
+	return isTextEntry(a.w.Canvas().Focused())
+}
+
+// isTextEntryKey lists shortcut keys that also produce text input.
+func isTextEntryKey(key fyne.KeyName) bool {
+	switch key {
+	case fyne.KeySpace, fyne.KeyPlus, fyne.KeyMinus, fyne.KeyAsterisk, fyne.KeyLeft, fyne.KeyRight:
+		return true
+	}
+	return keyToPresetIndex(key) >= 0
+}
+
+// isTextEntry reports whether the focused object edits text.
+func isTextEntry(obj fyne.Focusable) bool {
+	switch obj.(type) {
+	case nil:
+		return false
+	case *widget.Entry, *widget.SelectEntry:
+		return true
+	}
+	// custom entries usually embed widget.Entry and keep its selection helper
+	_, ok := obj.(interface{ SelectedText() string })
+	return ok
+}
+
 // Run finalizes layout construction and enters the fyne event loop.
 func (a *App) Run() {
 	a.w.ShowAndRun()
@@ -357,7 +747,8 @@ func (a *App) Run() {
 // MiniRadio window.
 func (a *App) buildUI() {
 	// The base (strip) height is fixed and should never include drawer height.
-	a.baseHeight = config.DefaultHeight
+	a.sizeScale = config.SizeScale(a.config.SizeMode)
+	a.baseHeight = config.DefaultHeight * a.sizeScale
 	a.buildDrawers()
 	a.buildMainWindow()
 }
@@ -389,7 +780,7 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 	}
 
 	barHeight := a.baseHeight
-	darkBg := color.NRGBA{0x1a, 0x1a, 0x1a, 0xFF}
+	colors := a.colors()
 
 	// --- PLAY ---------------------------------------------------------
 
@@ -397,11 +788,11 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 	a.playBtn = widget.NewButtonWithIcon("", playIcon, func() { a.togglePlay() })
 	a.playBtn.Importance = widget.LowImportance
 
-	playBg := canvas.NewRectangle(darkBg)
-	playBg.SetMinSize(fyne.NewSize(36, barHeight))
+	a.playBg = canvas.NewRectangle(colors.strip)
+	a.playBg.SetMinSize(fyne.NewSize(36*a.sizeScale, barHeight))
 
 	playWrap := container.NewMax(
-		playBg,
+		a.playBg,
 		container.NewCenter(a.playBtn),
 	)
 
@@ -416,23 +807,41 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 	a.centerLbl.Truncation = fyne.TextTruncateClip
 	a.centerLbl.Alignment = fyne.TextAlignLeading
 
-	a.tickerBg = canvas.NewRectangle(color.NRGBA{0x00, 0x99, 0xFF, 0x40})
+	a.tickerBg = canvas.NewRectangle(colors.tint)
 	bgHeight := barHeight - 6
 	if bgHeight < 1 {
 		bgHeight = 1
 	}
 	a.tickerBg.SetMinSize(fyne.NewSize(1, bgHeight))
 
-	a.ind = ui.NewStreamIndicator(14)
+	a.ind = ui.NewStreamIndicator(14 * a.sizeScale)
+	a.ind.SetIdleColor(colors.idle)
+	a.stationTip = ui.NewValueTip()
+	// hovering the indicator tells what the station reports about itself
+	indHover := ui.NewHoverArea(a.ind.CanvasObject(), nil, a.stationTip.Hide)
+	indHover.OnMouseIn = func() { a.showStationTip(indHover) }
 	gap := canvas.NewRectangle(color.NRGBA{0, 0, 0, 0})
 	gap.SetMinSize(fyne.NewSize(6, 1))
 
-	labelWrap := container.NewMax(a.centerLbl)
+	// hovering the ticker pauses scrolling so long titles can be read
+	hover := ui.NewHoverArea(a.centerLbl,
+		func() {
+			if a.ticker != nil {
+				a.ticker.Pause()
+			}
+		},
+		func() {
+			if a.ticker != nil {
+				a.ticker.Resume()
+			}
+		})
+	labelWrap := container.NewMax(ui.NewTapArea(hover, a.runTickerAction))
 	a.centerWrap = labelWrap
 	a.ticker = ui.NewTickerController(a.centerLbl, a.centerWrap)
+	a.applyTickerPrefs()
 
 	centerRow := container.NewHBox(
-		a.ind.CanvasObject(),
+		indHover,
 		gap,
 		labelWrap,
 	)
@@ -452,6 +861,7 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 	centerContent := container.NewMax(
 		centerBgWrap,
 		container.NewPadded(centerRow),
+		a.stationTip.CanvasObject(),
 	)
 
 	// --- RIGHT: громкость + настройки + EQ ----------------------------
@@ -461,27 +871,24 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 
 	a.volSlider = ui.NewMiniThumbSlider(0, 100)
 	a.volSlider.Step = 1
-	a.volSlider.Value = float64(a.config.Volume)
+	a.volSlider.Value = float64(a.effectiveVolume())
+	a.volSlider.OnUnhandledKey = a.handleShortcutKey
 	a.updateVolumeIcon()
 
-	var saveTimer *time.Timer
 	a.volSlider.OnChanged = func(v float64) {
 		vv := int(v + 0.5)
 		a.ensureVolumeUnmuted()
 		if a != nil && a.player != nil {
 			_ = a.player.SetVolume(vv)
 		}
-		a.config.Volume = vv
+		a.storeVolume(vv)
 		a.playerState.Volume = vv
 		a.updateVolumeIcon()
-		if saveTimer != nil {
-			saveTimer.Stop()
-		}
-		saveTimer = time.AfterFunc(400*time.Millisecond, func() { _ = a.config.Save() })
+		a.saveVolumeSoon()
 	}
 
 	longVol := container.New(
-		layout.NewGridWrapLayout(fyne.NewSize(110, a.volSlider.MinSize().Height)),
+		layout.NewGridWrapLayout(fyne.NewSize(110*a.sizeScale, a.volSlider.MinSize().Height)),
 		a.volSlider,
 	)
 
@@ -489,6 +896,15 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 	topPad := canvas.NewRectangle(color.NRGBA{0, 0, 0, 0})
 	topPad.SetMinSize(fyne.NewSize(1, 3))
 	volCentered := container.NewBorder(topPad, nil, nil, nil, longVol)
+	volGroup := fyne.CanvasObject(volCentered)
+	if !a.config.HideVolumeReadout {
+		a.volLabel = widget.NewLabel("")
+		// fixed width that fits "Muted", so the strip does not shift as it changes
+		readoutWidth := canvas.NewRectangle(color.NRGBA{0, 0, 0, 0})
+		readoutWidth.SetMinSize(fyne.NewSize(48*a.sizeScale, 1))
+		volGroup = container.NewHBox(volCentered, container.NewStack(readoutWidth, container.NewCenter(a.volLabel)))
+		a.updateVolumeIcon()
+	}
 
 	a.settingsBtn = widget.NewButtonWithIcon("", theme.SettingsIcon(), func() { a.toggleSettingsDrawer() })
 	a.settingsBtn.Importance = widget.LowImportance
@@ -502,20 +918,25 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 	a.eqBtnBg = canvas.NewRectangle(color.NRGBA{0, 0, 0, 0})
 	eqWrap := container.NewMax(a.eqBtnBg, a.eqBtn)
 
+	historyBtn := widget.NewButtonWithIcon("", theme.HistoryIcon(), func() { a.toggleHistoryDrawer() })
+	historyBtn.Importance = widget.LowImportance
+
 	rightPanel := container.NewHBox(
 		a.volBtn,
-		volCentered,
+		volGroup,
 		widget.NewSeparator(),
+		historyBtn,
+		a.buildOnboardingHint(),
 		settingsWrap,
 		eqWrap,
 	)
 
-	rightBg := canvas.NewRectangle(darkBg)
-	rightBg.SetMinSize(fyne.NewSize(1, barHeight))
+	a.rightBg = canvas.NewRectangle(colors.strip)
+	a.rightBg.SetMinSize(fyne.NewSize(1, barHeight))
 
 	rightBlock := container.NewMax(
-		rightBg,
-		container.NewPadded(rightPanel),
+		a.rightBg,
+		container.NewBorder(nil, nil, nil, ui.NewResizeGrip(6*a.sizeScale, a.widenBy), container.NewPadded(rightPanel)),
 	)
 
 	// --- Вся верхняя панель -------------------------------------------
@@ -542,7 +963,37 @@ func (a *App) buildControlBar() fyne.CanvasObject {
 	// spTop := canvas.NewRectangle(color.NRGBA{0x00, 0x40, 0x80, 0xFF}) // debug
 	spTop.SetMinSize(fyne.NewSize(1, barHeight))
 
-	return container.NewMax(spTop, topOverlay)
+	// the wheel changes the volume anywhere on the strip; drawers sit outside
+	// it, so their sliders and lists keep their own scrolling
+	return ui.NewScrollArea(container.NewMax(spTop, topOverlay), a.handleStripScroll)
+}
+
+// widenBy changes the window width by dx from the resize grip, never below
+// MinWindowWidth; the height stays what the strip and drawer need.
+func (a *App) widenBy(dx float32) {
+	sz := a.w.Canvas().Size()
+	w := sz.Width + dx
+	if w < config.MinWindowWidth {
+		w = config.MinWindowWidth
+	}
+	if w == sz.Width {
+		return
+	}
+	a.w.Resize(fyne.NewSize(w, sz.Height))
+}
+
+// wheelVolumeStep is the volume change per wheel notch over the strip.
+const wheelVolumeStep = 2
+
+// handleStripScroll turns a wheel event over the control strip into a volume
+// change; up is louder.
+func (a *App) handleStripScroll(ev *fyne.ScrollEvent) {
+	switch {
+	case ev.Scrolled.DY > 0:
+		a.changeVolume(wheelVolumeStep)
+	case ev.Scrolled.DY < 0:
+		a.changeVolume(-wheelVolumeStep)
+	}
 }
 
 // buildDrawers initializes the persistent bottom container used for settings
@@ -617,12 +1068,27 @@ func (a *App) togglePlay() {
 		dialog.ShowError(fmt.Errorf("VLC not initialized"), a.w)
 		return
 	}
+	// the cause was shown once at startup; presets may still be picked
+	if a.vlcErr != nil {
+		a.ShowToast("Safe mode: VLC did not start, playback is off")
+		return
+	}
+	// Play tunes in to a previewed station
+	if a.player.IsPreviewing() {
+		idx := a.previewIdx
+		a.stopPreview()
+		a.activatePreset(idx)
+		return
+	}
 	if a.player.IsPlaying() {
+		a.cancelPendingPlay()
 		a.player.Stop()
 		a.playBtn.SetIcon(theme.MediaPlayIcon())
 		if a.ind != nil {
 			a.ind.SetActive(false)
 		}
+		a.media.SetPlaying(false)
+		a.media.SetTrack("")
 		a.UpdateTicker("Stopped")
 		return
 	}
@@ -633,9 +1099,7 @@ func (a *App) togglePlay() {
 	}
 	if a.player != nil {
 		if idx := a.currentPresetIndex(); idx >= 0 && idx < len(a.config.Presets) {
-			p := a.config.Presets[idx]
-			metaType := strings.TrimSpace(p.MetadataType)
-			metaURL := strings.TrimSpace(p.MetadataURL)
+			metaType, metaURL := a.config.Presets[idx].MetadataHint()
 			idxCopy := idx
 			a.player.SetMetadataHint(metaType, metaURL, func(t, u string) {
 				a.handleMetadataDiscovered(idxCopy, t, u)
@@ -650,15 +1114,44 @@ func (a *App) togglePlay() {
 			if strings.TrimSpace(name) == "" {
 				name = EQNameOff
 			}
-			customMap := map[string]config.EQPresetData{}
-			for _, ce := range a.config.CustomEQPresets {
-				customMap[strings.TrimSpace(ce.Name)] = ce
-			}
-			_ = a.eq.ApplyPresetName(a.player, name, customMap)
+			_ = a.eq.ApplyPresetName(a.player, name, a.stationEQCurves(idx))
 		}
 	}
 	// Refresh the title with the stored preset name until metadata arrives.
 	a.setWindowTitleForCurrentPreset()
+	a.applyRequestHeaders(a.currentPresetIndex())
+	a.applyTitleRules(a.currentPresetIndex())
+	a.resolveThen(url, func() { a.startStream(url) })
+}
+
+// resolveThen looks up the stream behind url, reading a playlist and
+// following redirects, off the UI thread, then runs next on it; the player
+// caches the answer, so next loads url without waiting on the network. A
+// newer lookup or a stop supersedes this one, whose next is then dropped.
+func (a *App) resolveThen(url string, next func()) {
+	a.resolveGen++
+	gen := a.resolveGen
+	a.UpdateTicker("Connecting…")
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		a.player.ResolveStream(ctx, url)
+		ui.CallOnMain(func() {
+			if gen == a.resolveGen {
+				next()
+			}
+		})
+	}()
+}
+
+// cancelPendingPlay drops a stream lookup still running for a play request,
+// so a stop given while connecting is not undone when it finishes.
+func (a *App) cancelPendingPlay() {
+	a.resolveGen++
+}
+
+// startStream loads and plays url once resolveThen has looked it up.
+func (a *App) startStream(url string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := a.player.Load(ctx, url); err != nil {
@@ -688,9 +1181,55 @@ func (a *App) togglePlay() {
 	if a.ind != nil {
 		a.ind.SetActive(true)
 	}
+	a.media.SetPlaying(true)
 	a.UpdateTicker("Streaming…")
 }
 
+// showBuffering reflects buffering progress in the indicator and ticker; at
+// 100% the ticker goes back to the current title.
+func (a *App) showBuffering(pct float64) {
+	if !a.player.IsPlaying() {
+		return
+	}
+	done := pct >= 100
+	if a.ind != nil {
+		a.ind.SetBuffering(!done)
+	}
+	if a.ticker == nil {
+		return
+	}
+	if !done {
+		a.ticker.SetText(fmt.Sprintf("Buffering… %d%%", int(pct)))
+		return
+	}
+	text := a.player.CurrentTitle()
+	if text == "" {
+		text = "Streaming…"
+	}
+	a.ticker.SetText(text)
+}
+
+// handlePlaybackError surfaces a problem the player found while streaming.
+// Dead air restarts the stream when SilenceReconnect is set.
+func (a *App) handlePlaybackError(err error) {
+	log.Printf("playback: %v", err)
+	if !a.player.IsPlaying() {
+		return
+	}
+	if !errors.Is(err, playerpkg.ErrDeadAir) {
+		a.UpdateTicker(err.Error())
+		return
+	}
+	if !a.config.SilenceReconnect {
+		a.UpdateTicker("No audio from station")
+		return
+	}
+	url := a.config.CurrentURL
+	a.player.Stop()
+	a.resolveThen(url, func() { a.startStream(url) })
+	a.UpdateTicker("No audio from station, reconnecting…")
+}
+
 // changeVolume increments/decrements the slider and player volume in tandem.
 func (a *App) changeVolume(delta int) {
 	if a.player == nil {
@@ -698,22 +1237,34 @@ func (a *App) changeVolume(delta int) {
 	}
 	a.ensureVolumeUnmuted()
 	v := a.player.Vol(delta)
-	a.config.Volume = v
+	a.storeVolume(v)
 	// sync UI controls
 	if a.volSlider != nil {
 		a.volSlider.SetValue(float64(v))
 	}
 	a.updateVolumeIcon()
-	_ = a.config.Save()
+	a.saveVolumeSoon()
 }
 
+// saveVolumeSoon saves the config once the volume has stopped changing for a
+// moment, so dragging the slider or spinning the wheel writes it once.
+func (a *App) saveVolumeSoon() {
+	if a.volSaveTimer != nil {
+		a.volSaveTimer.Stop()
+	}
+	a.volSaveTimer = time.AfterFunc(400*time.Millisecond, func() { _ = a.config.Save() })
+}
+
+// muteFade is how long toggling mute ramps the volume.
+const muteFade = 250 * time.Millisecond
+
 // toggleMute flips muting both in the player and the UI toggle button.
 func (a *App) toggleMute() {
 	if a.player == nil {
 		return
 	}
 	target := !a.config.Muted
-	_ = a.player.SetMute(target)
+	a.player.SetMuteFaded(target, muteFade)
 	a.config.Muted = target
 	a.updateVolumeIcon()
 	_ = a.config.Save()
@@ -731,16 +1282,67 @@ func (a *App) ensureVolumeUnmuted() {
 	a.config.Muted = false
 }
 
-// updateVolumeIcon adjusts the mute button icon to represent the current level.
+// effectiveVolume is the level for the active preset: its remembered volume
+// when set, otherwise the global Config.Volume.
+func (a *App) effectiveVolume() int {
+	if idx := a.currentPresetIndex(); idx >= 0 {
+		if v := a.config.Presets[idx].Volume; v >= 0 {
+			return v
+		}
+	}
+	return a.config.Volume
+}
+
+// storeVolume records a user volume change against the active preset, or the
+// global volume when no preset is active. Callers save the config.
+func (a *App) storeVolume(v int) {
+	if idx := a.currentPresetIndex(); idx >= 0 {
+		a.config.Presets[idx].Volume = v
+		return
+	}
+	a.config.Volume = v
+}
+
+// applyPresetVolume moves the player and slider to the active preset's level
+// without going through the slider callback (which would unmute).
+func (a *App) applyPresetVolume() {
+	v := a.effectiveVolume()
+	if a.player != nil {
+		_ = a.player.SetVolume(v)
+	}
+	a.playerState.Volume = v
+	if a.volSlider != nil {
+		a.volSlider.Value = float64(v)
+		a.volSlider.Refresh()
+	}
+	a.updateVolumeIcon()
+}
+
+// updateVolumeIcon adjusts the mute button icon, the volume readout and the
+// indicator's muted dimming to represent the current level.
 func (a *App) updateVolumeIcon() {
 	if a.volBtn == nil || a.config == nil {
 		return
 	}
 	icon := theme.VolumeUpIcon()
-	if a.config.Muted || a.config.Volume <= 0 {
+	if a.config.Muted || a.effectiveVolume() <= 0 {
 		icon = theme.VolumeMuteIcon()
 	}
 	a.volBtn.SetIcon(icon)
+	if a.ind != nil {
+		a.ind.SetMuted(a.config.Muted)
+	}
+	if a.volLabel != nil {
+		a.volLabel.SetText(volumeReadout(a.effectiveVolume(), a.config.Muted))
+	}
+}
+
+// volumeReadout is the text of the volume readout.
+func volumeReadout(v int, muted bool) string {
+	if muted {
+		return "Muted"
+	}
+	return fmt.Sprintf("%d%%", v)
 }
 
 // rebuildCustomEQIndex rebuilds the lookup map for user EQ presets to keep the
@@ -763,6 +1365,7 @@ func (a *App) toggleEQDrawer() {
 
 // toggleSettingsDrawer toggles the station/settings drawer visibility.
 func (a *App) toggleSettingsDrawer() {
+	a.dismissOnboarding()
 	target := "settings"
 	if a.pendingDrawer == target {
 		target = ""
@@ -770,13 +1373,22 @@ func (a *App) toggleSettingsDrawer() {
 	a.setDrawerTarget(target)
 }
 
+// toggleHistoryDrawer toggles the now-playing history drawer.
+func (a *App) toggleHistoryDrawer() {
+	target := "history"
+	if a.pendingDrawer == target {
+		target = ""
+	}
+	a.setDrawerTarget(target)
+}
+
 // restoreWindowPlacement loads persisted window coordinates when supported.
 func (a *App) restoreWindowPlacement() {
 	if a == nil || a.w == nil || a.config == nil || !a.config.WindowPosValid {
 		return
 	}
 	try := func() bool {
-		return windowpos.ApplyWindowPosition(a.w, a.config.WindowX, a.config.WindowY)
+		return windowpos.ApplyWindowPositionVisible(a.w, a.config.WindowX, a.config.WindowY)
 	}
 	if try() {
 		return
@@ -795,6 +1407,43 @@ func (a *App) restoreWindowPlacement() {
 	}()
 }
 
+// resetWindowPlacement is the escape hatch for a window that ended up
+// off-screen or oddly sized: it forgets the stored coordinates, shrinks the
+// window back to the minimum-width strip, centers it on the primary monitor,
+// and saves.
+func (a *App) resetWindowPlacement() {
+	if a == nil || a.w == nil || a.config == nil {
+		return
+	}
+	a.config.WindowX = 0
+	a.config.WindowY = 0
+	a.config.WindowPosValid = false
+	a.config.WindowW = config.MinWindowWidth
+	a.config.WindowH = int(a.baseHeight)
+	a.setDrawerTarget("")
+	ui.CallOnMain(func() {
+		h := a.baseHeight
+		if a.topFixed != nil {
+			h = a.topFixed.MinSize().Height
+		}
+		a.w.Resize(fyne.NewSize(config.MinWindowWidth, h))
+	})
+	// Centering goes through the native handle, which must not be requested
+	// from the UI thread; give the resize a moment to land first. The config
+	// is only touched back on the UI thread.
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		centered := windowpos.CenterOnPrimaryMonitor(a.w)
+		ui.CallOnMain(func() {
+			if !centered {
+				a.w.CenterOnScreen()
+			}
+			a.captureWindowPlacement()
+			_ = a.config.Save()
+		})
+	}()
+}
+
 // captureWindowPlacement stores window coordinates before toggling drawers so
 // the window returns to the user's preferred spot.
 func (a *App) captureWindowPlacement() {
@@ -836,7 +1485,16 @@ func (a *App) applyDrawerTarget() {
 		}
 		return
 	}
-	a.openDrawer(content, target, desired)
+	// drawer layouts are sized for the normal theme
+	a.openDrawer(content, target*a.drawerScale(), desired)
+}
+
+// drawerScale is sizeScale, or 1 before the strip has been built.
+func (a *App) drawerScale() float32 {
+	if a.sizeScale <= 0 {
+		return 1
+	}
+	return a.sizeScale
 }
 
 // buildDrawerContent returns the widget tree and height for the requested
@@ -846,10 +1504,9 @@ func (a *App) buildDrawerContent(mode string) (fyne.CanvasObject, float32) {
 	case "settings":
 		return BuildSettingsDrawer(a)
 	case "equalizer":
-		if a.eq == nil {
-			return nil, 0
-		}
 		return BuildEqualizerDrawer(a)
+	case "history":
+		return BuildHistoryDrawer(a)
 	}
 	return nil, 0
 }
@@ -894,6 +1551,13 @@ func (a *App) openDrawer(content fyne.CanvasObject, height float32, mode string)
 
 // closeDrawer collapses the drawer and clears its content container.
 func (a *App) closeDrawer() {
+	// an A/B comparison only lives as long as the EQ drawer showing it
+	if a.eq != nil && a.eq.Comparing() {
+		_ = a.eq.SetCompare(a.player, false)
+	}
+	if a.drawerMode == "equalizer" {
+		a.captureManualEQ()
+	}
 	a.clearDrawerHighlight()
 	a.setDrawerHeight(0)
 	ui.CallOnMain(func() {
@@ -969,8 +1633,7 @@ func (a *App) activatePreset(idx int) {
 		return
 	}
 	if a.player != nil {
-		metaType := strings.TrimSpace(p.MetadataType)
-		metaURL := strings.TrimSpace(p.MetadataURL)
+		metaType, metaURL := p.MetadataHint()
 		idxCopy := idx
 		a.player.SetMetadataHint(metaType, metaURL, func(t, u string) {
 			a.handleMetadataDiscovered(idxCopy, t, u)
@@ -979,6 +1642,7 @@ func (a *App) activatePreset(idx int) {
 	a.config.CurrentURL = p.URL
 	a.config.LastPreset = idx
 	_ = a.config.Save()
+	a.applyPresetVolume()
 	// Refresh title with stored station name; metadata might not arrive.
 	a.setWindowTitleForName(p.Name)
 	// sync drawer radios if open
@@ -994,20 +1658,117 @@ func (a *App) activatePreset(idx int) {
 	a.UpdateTicker(fmt.Sprintf("Preset %d: %s", idx+1, nonEmpty(p.Name, p.URL)))
 	// Apply EQ preset, if available
 	if a.eq != nil {
-		custom := map[string]config.EQPresetData{}
-		for _, ce := range a.config.CustomEQPresets {
-			custom[strings.TrimSpace(ce.Name)] = ce
-		}
-		_ = a.eq.ApplyPresetName(a.player, p.EQ, custom)
+		_ = a.eq.ApplyPresetName(a.player, p.EQ, a.stationEQCurves(idx))
 	}
 	// auto play selected preset
 	if a.player.IsPlaying() {
+		if a.config.CrossfadeMs > 0 {
+			url := p.URL
+			a.resolveThen(url, func() { a.crossfadeOrRestart(url) })
+			return
+		}
 		a.player.Stop()
 	}
 	// Simulate click play
 	a.togglePlay()
 }
 
+// applyTickerPrefs pushes the configured marquee speed and direction to the
+// ticker.
+func (a *App) applyTickerPrefs() {
+	if a.ticker == nil {
+		return
+	}
+	a.ticker.SetSpeed(time.Duration(a.config.TickerSpeedMs) * time.Millisecond)
+	dir := ui.ScrollLeft
+	if a.config.TickerDirection == config.TickerRight {
+		dir = ui.ScrollRight
+	}
+	a.ticker.SetDirection(dir)
+}
+
+// applyRequestHeaders hands preset idx's User-Agent and Referer (or the
+// global ones) and its TLS policy to the player before a stream is loaded. A
+// bad certificate pin is logged and the station is not pinned.
+func (a *App) applyRequestHeaders(idx int) {
+	ua, ref := a.config.UserAgent, a.config.Referer
+	var requireTLS bool
+	var pins []string
+	if idx >= 0 && idx < len(a.config.Presets) {
+		p := &a.config.Presets[idx]
+		ua, ref = presetHeaders(a.config, p)
+		requireTLS, pins = p.RequireTLS, p.CertPins
+	}
+	a.player.SetRequestHeaders(ua, ref)
+	if err := a.player.SetStationTLS(requireTLS, pins); err != nil {
+		log.Printf("preset %d: %v", idx+1, err)
+	}
+}
+
+// presetHeaders returns the User-Agent and Referer requests for p carry: its
+// own when set, the global ones otherwise.
+func presetHeaders(cfg *config.Config, p *config.Preset) (ua, ref string) {
+	ua, ref = cfg.UserAgent, cfg.Referer
+	if strings.TrimSpace(p.UserAgent) != "" {
+		ua = p.UserAgent
+	}
+	if strings.TrimSpace(p.Referer) != "" {
+		ref = p.Referer
+	}
+	return ua, ref
+}
+
+// applyTitleRules hands preset idx's title filters, stabilization window and
+// JSON metadata fields to the player before a stream is loaded. A bad
+// pattern or path is logged and ignored.
+func (a *App) applyTitleRules(idx int) {
+	var patterns []string
+	var fields metadata.JSONFields
+	if idx >= 0 && idx < len(a.config.Presets) {
+		p := &a.config.Presets[idx]
+		patterns = p.TitleFilters
+		if f := p.MetadataFields; f != nil {
+			fields = metadata.JSONFields{Title: f.Title, Artist: f.Artist, Station: f.Station, Artwork: f.Artwork}
+		}
+	}
+	if err := a.player.SetTitleFilters(patterns); err != nil {
+		log.Printf("preset %d: %v", idx+1, err)
+	}
+	a.player.SetStableWindow(stableWindow(a.config, idx))
+	if err := a.player.SetJSONFields(fields); err != nil {
+		log.Printf("preset %d: %v", idx+1, err)
+	}
+}
+
+// crossfadeOrRestart crossfades to url, or stops and starts it afresh when the
+// crossfade fails or playback ended meanwhile.
+func (a *App) crossfadeOrRestart(url string) {
+	if a.player.IsPlaying() && a.crossfadeTo(url) {
+		return
+	}
+	if a.player.IsPlaying() {
+		a.player.Stop()
+	}
+	a.togglePlay()
+}
+
+// crossfadeTo switches the playing stream to url with the configured
+// crossfade. It reports false when the switch failed, so the caller can fall
+// back to stop-then-play.
+func (a *App) crossfadeTo(url string) bool {
+	a.setWindowTitleForCurrentPreset()
+	a.applyRequestHeaders(a.currentPresetIndex())
+	a.applyTitleRules(a.currentPresetIndex())
+	d := time.Duration(a.config.CrossfadeMs) * time.Millisecond
+	if err := a.player.CrossfadeTo(url, d); err != nil {
+		log.Printf("crossfade to %s: %v", url, err)
+		return false
+	}
+	a.media.SetTrack("")
+	a.UpdateTicker("Streaming…")
+	return true
+}
+
 func nonEmpty(vals ...string) string {
 	for _, v := range vals {
 		if v != "" {
@@ -1025,7 +1786,10 @@ func (a *App) setWindowTitleForName(name string) {
 	if trimmed := strings.TrimSpace(name); trimmed != "" {
 		title = "MiniRadio - " + trimmed
 	}
-	ui.CallOnMain(func() { a.w.SetTitle(title) })
+	ui.CallOnMain(func() {
+		a.w.SetTitle(title)
+		a.media.SetStation(strings.TrimSpace(name))
+	})
 }
 
 func (a *App) setWindowTitleForCurrentPreset() {
@@ -1096,15 +1860,25 @@ func (a *App) handleMetadataDiscovered(idx int, metaType, metaURL string) {
 	}
 	typ := strings.ToUpper(strings.TrimSpace(metaType))
 	url := strings.TrimSpace(metaURL)
-	if typ == "" || url == "" {
+	// ICY and HLS read the stream itself when url is empty
+	if typ == "" || (url == "" && typ != metadata.MetadataTypeICY && typ != metadata.MetadataTypeHLS) {
 		return
 	}
 	p := &a.config.Presets[idx]
-	if strings.EqualFold(strings.TrimSpace(p.MetadataType), typ) && strings.TrimSpace(p.MetadataURL) == url {
+	if p.MetadataManual {
+		return
+	}
+	ui.CallOnMain(func() {
+		if a.rescanning {
+			a.rescanning = false
+			a.UpdateTicker("Metadata found: " + typ)
+		}
+	})
+	if strings.EqualFold(strings.TrimSpace(p.MetadataType), typ) && strings.TrimSpace(p.MetadataURL) == url &&
+		p.MetadataFor == strings.TrimSpace(p.URL) {
 		return
 	}
-	p.MetadataType = typ
-	p.MetadataURL = url
+	p.RememberMetadata(typ, url)
 	_ = a.config.Save()
 }
 
@@ -1119,19 +1893,42 @@ func (a *App) UpdateTicker(text string) {
 	if a.tickerBg != nil {
 		ui.CallOnMain(func() {
 			// brighter cyan
-			a.tickerBg.FillColor = color.NRGBA{0x00, 0xCC, 0xFF, 0x60}
+			a.tickerBg.FillColor = a.colors().flash
 			a.tickerBg.Refresh()
 		})
 		time.AfterFunc(180*time.Millisecond, func() {
 			ui.CallOnMain(func() {
 				// restore
-				a.tickerBg.FillColor = color.NRGBA{0x00, 0x99, 0xFF, 0x40}
+				a.tickerBg.FillColor = a.colors().tint
 				a.tickerBg.Refresh()
 			})
 		})
 	}
 }
 
+// copyToastDuration is how long "Copied: …" replaces the scrolling title.
+const copyToastDuration = 1500 * time.Millisecond
+
+// copyCurrentTitle puts the now-playing title on the clipboard and confirms
+// it in the ticker, which returns to the title afterwards unless the track
+// changed meanwhile. Without a title there is nothing to copy.
+func (a *App) copyCurrentTitle() {
+	if a.player == nil {
+		return
+	}
+	title := strings.TrimSpace(a.player.CurrentTitle())
+	if title == "" {
+		return
+	}
+	a.w.Clipboard().SetContent(title)
+	a.ShowToast("Copied: " + title)
+	time.AfterFunc(copyToastDuration, func() {
+		if strings.TrimSpace(a.player.CurrentTitle()) == title {
+			a.ShowToast(title)
+		}
+	})
+}
+
 // ShowToast shows temporary ticker message (same channel as ticker) in a UI-safe way.
 // ShowToast displays a transient dialog-like notification near the window.
 func (a *App) ShowToast(text string) {
@@ -1139,3 +1936,24 @@ func (a *App) ShowToast(text string) {
 		ui.CallOnMain(func() { a.ticker.SetText(text) })
 	}
 }
+
+// showTickerAction shows text like ShowToast and makes a tap on the ticker
+// run action, until the tap or clearTickerAction. Call it on the UI thread.
+func (a *App) showTickerAction(text string, action func()) {
+	a.tickerAction = action
+	a.ShowToast(text)
+}
+
+// clearTickerAction disarms the tap installed by showTickerAction.
+func (a *App) clearTickerAction() {
+	a.tickerAction = nil
+}
+
+// runTickerAction handles a tap on the ticker.
+func (a *App) runTickerAction() {
+	defer a.ensureShortcutFocus()
+	if fn := a.tickerAction; fn != nil {
+		a.tickerAction = nil
+		fn()
+	}
+}
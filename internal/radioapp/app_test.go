@@ -0,0 +1,672 @@
+package radioapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	config "github.com/edward-ap/miniradio/internal/config"
+	eqmodel "github.com/edward-ap/miniradio/internal/equalizer"
+	metadata "github.com/edward-ap/miniradio/internal/metadata"
+	playerpkg "github.com/edward-ap/miniradio/internal/player"
+	ui "github.com/edward-ap/miniradio/internal/ui"
+)
+
+func TestIsTextEntry(t *testing.T) {
+	test.NewApp()
+	tests := []struct {
+		name string
+		obj  fyne.Focusable
+		want bool
+	}{
+		{name: "nil", obj: nil, want: false},
+		{name: "entry", obj: widget.NewEntry(), want: true},
+		{name: "select entry", obj: widget.NewSelectEntry(nil), want: true},
+		{name: "shortcut catcher", obj: newShortcutCatcher(nil), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTextEntry(tt.obj); got != tt.want {
+				t.Fatalf("isTextEntry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyBelongsToEntry(t *testing.T) {
+	test.NewApp()
+	entry := widget.NewEntry()
+	catcher := newShortcutCatcher(nil)
+	w := test.NewWindow(container.NewVBox(entry, catcher))
+	defer w.Close()
+	a := &App{w: w, config: &config.Config{}}
+
+	w.Canvas().Focus(catcher)
+	if a.keyBelongsToEntry(fyne.KeySpace) {
+		t.Fatal("space should trigger shortcuts when the catcher has focus")
+	}
+
+	w.Canvas().Focus(entry)
+	for _, key := range []fyne.KeyName{fyne.KeySpace, fyne.Key1, fyne.Key0, fyne.KeyMinus} {
+		if !a.keyBelongsToEntry(key) {
+			t.Fatalf("%s should be left to the focused entry", key)
+		}
+	}
+	if a.keyBelongsToEntry(fyne.KeyUp) {
+		t.Fatal("non-text keys should remain global shortcuts")
+	}
+
+	a.config.EntryShortcuts = true
+	if a.keyBelongsToEntry(fyne.KeySpace) {
+		t.Fatal("EntryShortcuts should keep space global")
+	}
+}
+
+func TestFormatStreamInfo(t *testing.T) {
+	tests := []struct {
+		name                 string
+		codec                string
+		kbps, rate, channels int
+		genre                string
+		want                 string
+	}{
+		{name: "full", codec: "AAC", kbps: 128, rate: 44100, channels: 2, genre: "Jazz", want: "AAC · 128 kbps · 44.1 kHz · stereo · Jazz"},
+		{name: "round rate", codec: "MP3", kbps: 320, rate: 48000, channels: 1, want: "MP3 · 320 kbps · 48 kHz · mono"},
+		{name: "bitrate only", kbps: 64, want: "64 kbps"},
+		{name: "surround", codec: "AC-3", channels: 6, want: "AC-3 · 6 ch"},
+		{name: "nothing", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatStreamInfo(tt.codec, tt.kbps, tt.rate, tt.channels, tt.genre); got != tt.want {
+				t.Fatalf("formatStreamInfo = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPresetIndex(t *testing.T) {
+	presets := []config.Preset{{URL: "http://a"}, {}, {URL: "http://c"}, {URL: " "}, {URL: "http://e"}}
+	tests := []struct {
+		name       string
+		cur, delta int
+		want       int
+	}{
+		{name: "forward skips empty", cur: 0, delta: 1, want: 2},
+		{name: "forward wraps", cur: 4, delta: 1, want: 0},
+		{name: "backward skips blank", cur: 4, delta: -1, want: 2},
+		{name: "backward wraps", cur: 0, delta: -1, want: 4},
+		{name: "none active forward", cur: -1, delta: 1, want: 0},
+		{name: "none active backward", cur: -1, delta: -1, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPresetIndex(presets, tt.cur, tt.delta, false); got != tt.want {
+				t.Fatalf("nextPresetIndex(%d, %d) = %d, want %d", tt.cur, tt.delta, got, tt.want)
+			}
+		})
+	}
+	if got := nextPresetIndex([]config.Preset{{URL: "http://only"}}, 0, 1, false); got != -1 {
+		t.Fatalf("single preset should not cycle, got %d", got)
+	}
+
+	starred := []config.Preset{{URL: "http://a", Favorite: true}, {URL: "http://b"}, {URL: "http://c", Favorite: true}, {Favorite: true}}
+	for _, tt := range []struct{ cur, delta, want int }{
+		{cur: 0, delta: 1, want: 2},
+		{cur: 2, delta: 1, want: 0},
+		{cur: 1, delta: -1, want: 0},
+		{cur: -1, delta: 1, want: 0},
+	} {
+		if got := nextPresetIndex(starred, tt.cur, tt.delta, true); got != tt.want {
+			t.Errorf("favorites: nextPresetIndex(%d, %d) = %d, want %d", tt.cur, tt.delta, got, tt.want)
+		}
+	}
+}
+
+func TestStartOptionsValidate(t *testing.T) {
+	cfg := &config.Config{Presets: []config.Preset{{URL: "http://a"}, {}, {URL: "http://c"}}}
+	tests := []struct {
+		name    string
+		opts    StartOptions
+		wantErr bool
+	}{
+		{name: "none", opts: StartOptions{}},
+		{name: "url", opts: StartOptions{URL: "http://x"}},
+		{name: "first preset", opts: StartOptions{Preset: 1}},
+		{name: "last preset", opts: StartOptions{Preset: 3}},
+		{name: "empty preset", opts: StartOptions{Preset: 2}, wantErr: true},
+		{name: "too high", opts: StartOptions{Preset: 4}, wantErr: true},
+		{name: "negative", opts: StartOptions{Preset: -1}, wantErr: true},
+		{name: "both", opts: StartOptions{URL: "http://x", Preset: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.validate(cfg); (err != nil) != tt.wantErr {
+				t.Fatalf("validate(%+v) error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoteAddr(t *testing.T) {
+	if got := remoteAddr(&config.Config{}); got != "127.0.0.1:8765" {
+		t.Fatalf("default addr = %q", got)
+	}
+	if got := remoteAddr(&config.Config{RemoteControlHost: "0.0.0.0", RemoteControlPort: 9000}); got != "0.0.0.0:9000" {
+		t.Fatalf("custom addr = %q", got)
+	}
+}
+
+func TestRemoteRejectsBadRequests(t *testing.T) {
+	mux := (&App{config: &config.Config{}}).remoteMux()
+	tests := []struct {
+		method, target string
+		want           int
+	}{
+		{http.MethodPost, "/volume?level=101", http.StatusBadRequest},
+		{http.MethodPost, "/volume", http.StatusBadRequest},
+		{http.MethodPost, "/preset?i=x", http.StatusBadRequest},
+		{http.MethodGet, "/play", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/unknown", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.target, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestRemoteGuard(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	guard := remoteGuard(ok, "")
+	tests := []struct {
+		host, origin string
+		want         int
+	}{
+		{"127.0.0.1:8765", "", http.StatusOK},
+		{"localhost:8765", "", http.StatusOK},
+		{"[::1]:8765", "", http.StatusOK},
+		{"192.168.1.20:8765", "", http.StatusOK},
+		{"127.0.0.1:8765", "https://evil.example", http.StatusForbidden},
+		{"rebind.evil.example:8765", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/play", nil)
+		req.Host = tt.host
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		guard.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Host %q Origin %q = %d, want %d", tt.host, tt.origin, rec.Code, tt.want)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/play", nil)
+	req.Host = "radio.lan:8765"
+	rec := httptest.NewRecorder()
+	remoteGuard(ok, "radio.lan").ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("configured host name refused: %d", rec.Code)
+	}
+}
+
+func TestFitPresetBands(t *testing.T) {
+	in := config.EQPresetData{
+		Name:   "Wide",
+		Preamp: 1.5,
+		Bands:  []float32{0, 4, 8},
+		Freqs:  []float32{100, 400, 1600},
+	}
+	hz := []float64{100, 200, 400, 800, 1600}
+	got := fitPresetBands(in, hz)
+	want := []float32{0, 2, 4, 6, 8}
+	if len(got.Bands) != len(want) || got.Preamp != 1.5 || got.Name != "Wide" {
+		t.Fatalf("fitted = %+v", got)
+	}
+	for i := range want {
+		if got.Bands[i] != want[i] || got.Freqs[i] != float32(hz[i]) {
+			t.Fatalf("fitted = %+v, want bands %v at %v", got, want, hz)
+		}
+	}
+	// presets saved before frequencies were stored keep index order when the
+	// band count already matches
+	legacy := fitPresetBands(config.EQPresetData{Name: "Old", Bands: []float32{1, 2, 3, 4, 5}}, hz)
+	if legacy.Bands[0] != 1 || legacy.Bands[4] != 5 {
+		t.Fatalf("legacy = %+v", legacy)
+	}
+}
+
+func TestSnapBalance(t *testing.T) {
+	for _, tt := range []struct{ in, want float64 }{
+		{0, 0}, {5, 0}, {-8, 0}, {9, 9}, {-40, -40}, {100, 100},
+	} {
+		if got := snapBalance(tt.in); got != tt.want {
+			t.Errorf("snapBalance(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDroppedStreamURL(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) fyne.URI {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return storage.NewFileURI(p)
+	}
+	web, _ := storage.ParseURI("https://radio.example/live.mp3")
+	ftp, _ := storage.ParseURI("ftp://radio.example/live.mp3")
+	cases := []struct {
+		name string
+		uri  fyne.URI
+		want string
+	}{
+		{"url", web, "https://radio.example/live.mp3"},
+		{"pls", write("station.pls", "[playlist]\nFile1=http://radio.example/aac\n"), "http://radio.example/aac"},
+		{"m3u", write("station.m3u", "#EXTM3U\nhttp://radio.example/mp3\n"), "http://radio.example/mp3"},
+		{"shortcut", write("Station.url", "[InternetShortcut]\r\nURL=https://radio.example/hls\r\n"), "https://radio.example/hls"},
+		{"relative entry", write("local.m3u", "#EXTM3U\nmusic/song.mp3\n"), ""},
+		{"not a playlist", write("notes.txt", "hello"), ""},
+		{"other scheme", ftp, ""},
+	}
+	for _, c := range cases {
+		got, err := droppedStreamURL(c.uri)
+		if (err != nil) != (c.want == "") || got != c.want {
+			t.Errorf("%s: droppedStreamURL = %q, %v; want %q", c.name, got, err, c.want)
+		}
+	}
+}
+
+func TestStaleTitleTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		ms   int
+		want time.Duration
+	}{
+		{ms: 0, want: playerpkg.DefaultStaleTitleTimeout},
+		{ms: -1, want: 0},
+		{ms: 90000, want: 90 * time.Second},
+	} {
+		if got := staleTitleTimeout(&config.Config{StaleTitleMs: tt.ms}); got != tt.want {
+			t.Errorf("staleTitleTimeout(%d) = %v, want %v", tt.ms, got, tt.want)
+		}
+	}
+}
+
+func TestStableWindow(t *testing.T) {
+	ms := func(v int) *int { return &v }
+	cfg := &config.Config{Presets: []config.Preset{{}, {StableWindowMs: ms(0)}}}
+	if got := stableWindow(cfg, -1); got != playerpkg.DefaultStableWindow {
+		t.Fatalf("unset window = %v, want player default", got)
+	}
+	cfg.StableWindowMs = ms(2500)
+	for _, tt := range []struct {
+		idx  int
+		want time.Duration
+	}{
+		{idx: -1, want: 2500 * time.Millisecond},
+		{idx: 0, want: 2500 * time.Millisecond},
+		{idx: 1, want: 0},
+		{idx: 5, want: 2500 * time.Millisecond},
+	} {
+		if got := stableWindow(cfg, tt.idx); got != tt.want {
+			t.Errorf("stableWindow(preset %d) = %v, want %v", tt.idx, got, tt.want)
+		}
+	}
+}
+
+func TestVolumeReadout(t *testing.T) {
+	for _, tt := range []struct {
+		v     int
+		muted bool
+		want  string
+	}{
+		{v: 40, want: "40%"},
+		{v: 0, want: "0%"},
+		{v: 100, want: "100%"},
+		{v: 60, muted: true, want: "Muted"},
+	} {
+		if got := volumeReadout(tt.v, tt.muted); got != tt.want {
+			t.Errorf("volumeReadout(%d, %v) = %q, want %q", tt.v, tt.muted, got, tt.want)
+		}
+	}
+}
+
+func TestStreamTestReport(t *testing.T) {
+	res := metadata.ProbeResult{
+		FinalURL:    "http://cdn.example/live",
+		Redirected:  true,
+		Status:      200,
+		ContentType: "audio/mpeg",
+		MetaInt:     "16000",
+		Station:     "Rock &amp; Roll",
+	}
+	want := "HTTP 200 OK\nContent type: audio/mpeg\nRedirected to: http://cdn.example/live\nICY metadata: yes (icy-metaint 16000)\nStation: Rock & Roll"
+	if got := streamTestReport(res, nil); got != want {
+		t.Fatalf("report = %q, want %q", got, want)
+	}
+	want = "HTTP 404 Not Found\nContent type: not given\nNo redirect\nICY metadata: no"
+	if got := streamTestReport(metadata.ProbeResult{Status: 404}, nil); got != want {
+		t.Fatalf("report = %q, want %q", got, want)
+	}
+	if got := streamTestReport(metadata.ProbeResult{}, context.DeadlineExceeded); got != "No answer within 3s." {
+		t.Fatalf("timeout report = %q", got)
+	}
+	if got := streamTestReport(metadata.ProbeResult{}, errors.New("refused")); got != "Not reachable: refused" {
+		t.Fatalf("error report = %q", got)
+	}
+}
+
+func TestNormalizeStreamURL(t *testing.T) {
+	cases := map[string]string{
+		"  http://radio.example/live  ":   "http://radio.example/live",
+		"<https://radio.example/live>":    "https://radio.example/live",
+		"\"http://radio.example/a.mp3\".": "http://radio.example/a.mp3",
+		"http://radio.example/live?":      "http://radio.example/live",
+		"radio.example:8000/live":         "https://radio.example:8000/live",
+		"localhost:8000/live":             "localhost:8000/live",
+		"http://radio.example/live?sid=1": "http://radio.example/live?sid=1",
+	}
+	for in, want := range cases {
+		if got := normalizeStreamURL(in); got != want {
+			t.Errorf("normalizeStreamURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestStreamURLProblem(t *testing.T) {
+	for _, ok := range []string{"", "  ", "http://radio.example/live", "HTTPS://radio.example:8443/x"} {
+		if err := streamURLProblem(ok); err != nil {
+			t.Errorf("streamURLProblem(%q) = %v, want nil", ok, err)
+		}
+	}
+	for in, want := range map[string]string{
+		"radio.example/live":     "missing http:// or https://",
+		"htps://radio.example/":  "did you mean https://?",
+		"htttp://radio.example/": "did you mean http://?",
+		"rtsp://radio.example/":  "rtsp:// is not an http(s) stream",
+		"http:///live":           "missing host name",
+	} {
+		if err := streamURLProblem(in); err == nil || err.Error() != want {
+			t.Errorf("streamURLProblem(%q) = %v, want %q", in, err, want)
+		}
+	}
+}
+
+func TestEQDrawerManualTransition(t *testing.T) {
+	test.NewApp()
+	bands := len(eqmodel.ReferenceBandsHz)
+	a := &App{
+		config:         &config.Config{},
+		eq:             &Equalizer{bandsHz: eqmodel.ReferenceBandsHz, bandCount: bands},
+		eqModel:        &eqmodel.EQModel{Current: eqmodel.EQPreset{Name: EQNameFlat, Gains: make([]float64, bands)}},
+		eqSliderValues: make([]float64, bands+1),
+	}
+	a.eqDrawerPreset = widget.NewSelect(a.drawerEQOptions(), nil)
+	a.showEQDrawerSelection(EQNameFlat)
+	for _, o := range a.eqDrawerPreset.Options {
+		if o == EQNameManual {
+			t.Fatal("Manual offered before any edit")
+		}
+	}
+
+	a.updateEQSliderState(3, 4.5)
+	a.markEQManual()
+	if got := a.eqDrawerPreset.Selected; got != EQNameManual {
+		t.Fatalf("after a slider edit the drawer shows %q, want Manual", got)
+	}
+	if a.eqDrawerPreset.Options[0] != EQNameManual {
+		t.Fatalf("Manual should lead the options: %v", a.eqDrawerPreset.Options)
+	}
+
+	a.applySelectedPreset(EQNameFlat)
+	if a.eqManual || a.eqDrawerPreset.Selected != EQNameFlat {
+		t.Fatalf("after picking Flat: manual=%v, shown %q", a.eqManual, a.eqDrawerPreset.Selected)
+	}
+	for _, o := range a.eqDrawerPreset.Options {
+		if o == EQNameManual {
+			t.Fatal("Manual still offered after picking a preset")
+		}
+	}
+}
+
+func TestStationEQCurvesKeepPreamp(t *testing.T) {
+	bands := len(eqmodel.ReferenceBandsHz)
+	gains := make([]float32, bands)
+	gains[2] = 3
+	freqs := make([]float32, bands)
+	for i, f := range eqmodel.ReferenceBandsHz {
+		freqs[i] = float32(f)
+	}
+	a := &App{
+		config: &config.Config{
+			CustomEQPresets: []config.EQPresetData{{Name: "Warm", Preamp: 4.5, Bands: gains, Freqs: freqs}},
+			Presets: []config.Preset{
+				{URL: "http://a.example/live", EQ: "warm"},
+				{URL: "http://b.example/live", EQ: EQNameManual, ManualEQ: &config.EQPresetData{Name: EQNameManual, Preamp: -6, Bands: gains, Freqs: freqs}},
+				{URL: "http://c.example/live", EQ: EQNameFlat},
+			},
+		},
+		eq: &Equalizer{bandsHz: eqmodel.ReferenceBandsHz, bandCount: bands},
+	}
+	for i, want := range []float64{4.5, -6, 0} {
+		p := a.config.Presets[i]
+		c := a.eq.resolveCurve(p.EQ, a.stationEQCurves(i))
+		if c.off || c.vlcPreset >= 0 || c.preamp != want {
+			t.Fatalf("station %d (%s): curve %+v, want preamp %v", i, p.EQ, c, want)
+		}
+		if want != 0 && (len(c.gains) != bands || c.gains[2] != 3) {
+			t.Fatalf("station %d (%s): gains %v", i, p.EQ, c.gains)
+		}
+	}
+	// a Manual curve belongs to its station only
+	if c := a.eq.resolveCurve(EQNameManual, a.stationEQCurves(2)); c.preamp != 0 || c.gains != nil {
+		t.Fatalf("station without a Manual curve resolved to %+v", c)
+	}
+	if c := a.eq.resolveCurve(EQNameOff, nil); !c.off {
+		t.Fatal("Off should resolve to no equalizer")
+	}
+}
+
+func TestEQUndoButton(t *testing.T) {
+	test.NewApp()
+	a := &App{config: &config.Config{}, eqDeleteButton: widget.NewButton("Delete", nil)}
+	a.eqDeleteButton.Disable()
+	a.offerEQUndo(config.EQPresetData{Name: "Warm"}, 0, nil)
+	if a.eqUndo == nil || a.eqDeleteButton.Text != "Undo" || a.eqDeleteButton.Disabled() {
+		t.Fatalf("after delete: undo=%v, button %q disabled=%v", a.eqUndo, a.eqDeleteButton.Text, a.eqDeleteButton.Disabled())
+	}
+	a.updateEQButtonsForSelection(EQNameFlat)
+	if a.eqDeleteButton.Disabled() {
+		t.Fatal("a pending undo must stay clickable")
+	}
+	if a.tickerAction == nil {
+		t.Fatal("the ticker should offer the undo")
+	}
+	a.endEQUndo()
+	if a.eqUndo != nil || a.eqDeleteButton.Text != "Delete" || a.tickerAction != nil {
+		t.Fatalf("after the window: undo=%v, button %q, ticker armed %v", a.eqUndo, a.eqDeleteButton.Text, a.tickerAction != nil)
+	}
+}
+
+func TestEQDeleteUndoRestoresStations(t *testing.T) {
+	presets := []config.Preset{{EQ: "Warm"}, {EQ: "Rock"}, {EQ: " warm "}, {EQ: "Warm"}}
+	stations := detachStationEQ(presets, "Warm")
+	if len(stations) != 3 || presets[0].EQ != EQNameFlat || presets[1].EQ != "Rock" || presets[2].EQ != EQNameFlat {
+		t.Fatalf("detached %v: %+v", stations, presets)
+	}
+	// a station moved to another preset meanwhile keeps it
+	presets[3].EQ = "Jazz"
+	restored := reattachStationEQ(presets, stations, "Warm")
+	if len(restored) != 2 || presets[0].EQ != "Warm" || presets[2].EQ != "Warm" || presets[3].EQ != "Jazz" {
+		t.Fatalf("restored %v: %+v", restored, presets)
+	}
+}
+
+func TestEQPresetNameProblem(t *testing.T) {
+	a := &App{
+		eq:            &Equalizer{presets: []string{"Full bass"}},
+		eqCustomNames: []string{"My Curve"},
+	}
+	for _, bad := range []string{"", "  ", "off", "FLAT", "auto", "Manual", "full BASS"} {
+		if a.eqPresetNameProblem(bad) == nil {
+			t.Errorf("%q accepted as a custom preset name", bad)
+		}
+	}
+	for _, ok := range []string{"Evening", " Flat 2 ", "my curve"} {
+		if err := a.eqPresetNameProblem(ok); err != nil {
+			t.Errorf("%q rejected: %v", ok, err)
+		}
+	}
+	if n, ok := a.customEQName(" my curve "); !ok || n != "My Curve" {
+		t.Fatalf("customEQName = %q, %v", n, ok)
+	}
+	if got := a.uniqueEQPresetName("My Curve"); got != "My Curve (2)" {
+		t.Fatalf("uniqueEQPresetName = %q", got)
+	}
+}
+
+func TestDBScaleMarks(t *testing.T) {
+	tests := []struct {
+		min, max float64
+		want     []dbMark
+	}{
+		{-20, 20, []dbMark{{"+20 dB", 0}, {"0 dB", 0.5}, {"-20 dB", 1}}},
+		{-12, 4, []dbMark{{"+4 dB", 0}, {"0 dB", 0.25}, {"-12 dB", 1}}},
+		{0, 15, []dbMark{{"+15 dB", 0}, {"+7.5 dB", 0.5}, {"0 dB", 1}}},
+	}
+	for _, tt := range tests {
+		got := dbScaleMarks(tt.min, tt.max)
+		if len(got) != len(tt.want) {
+			t.Fatalf("dbScaleMarks(%v, %v) = %v", tt.min, tt.max, got)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("dbScaleMarks(%v, %v)[%d] = %v, want %v", tt.min, tt.max, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestEQToneMode(t *testing.T) {
+	test.NewApp()
+	bands := len(eqmodel.ReferenceBandsHz)
+	a := &App{
+		config:         &config.Config{EQMode: "tone"},
+		eq:             &Equalizer{bandsHz: eqmodel.ReferenceBandsHz, bandCount: bands, ampMin: -20, ampMax: 20},
+		eqSliderValues: make([]float64, bands+1),
+		eqPreampSlider: ui.NewVerticalSlider(-20, 20),
+	}
+	a.buildEQToneSection()
+
+	tone := config.EQToneData{Bass: 6, Mid: -3, Treble: 4}
+	custom := map[string]config.EQPresetData{"Warm": {Name: "Warm", Bands: make([]float32, 4), Tone: &tone}}
+	c := a.eq.resolveCurve("warm", custom)
+	if len(c.gains) != bands || c.gains[0] != 6 || c.gains[bands-1] != 4 {
+		t.Fatalf("tone preset curve = %v, want it derived from the tone setting", c.gains)
+	}
+
+	copy(a.eqSliderValues[1:], c.gains)
+	a.applySliderValuesToWidgets()
+	if got := a.eqTone(); got != toneSetting(tone) {
+		t.Fatalf("tone controls show %+v, want %+v", got, toneSetting(tone))
+	}
+	if got := a.withEQTone(config.EQPresetData{Name: "x"}).Tone; got == nil || *got != tone {
+		t.Fatalf("tone mode saves tone %+v, want %+v", got, tone)
+	}
+	a.config.EQMode = ""
+	if got := a.withEQTone(config.EQPresetData{Name: "x"}).Tone; got != nil {
+		t.Fatalf("graphic mode saved tone %+v", got)
+	}
+}
+
+func TestSafeModeKeepsEQChoices(t *testing.T) {
+	test.NewApp()
+	a := &App{
+		config: &config.Config{CustomEQPresets: []config.EQPresetData{{Name: "Mine"}}},
+		player: playerpkg.NewPlayer(),
+	}
+	a.enterSafeMode(errors.New("libvlc.dll not found"))
+	opts := a.settingsEQOptions()
+	if opts[0] != EQNameOff || opts[1] != EQNameFlat || opts[len(opts)-1] != "Mine" || len(opts) != len(eqmodel.PresetNames())+2 {
+		t.Fatalf("safe-mode EQ options = %v, want Off, Flat, the bundled presets and Mine", opts)
+	}
+	a.togglePlay() // must not reach libVLC
+	if a.player.IsPlaying() {
+		t.Fatal("safe mode started playback")
+	}
+	if got := eqUnavailableText(a.eqErr, true); !strings.Contains(got, "libvlc.dll not found") || !strings.Contains(got, "playback is off") {
+		t.Fatalf("safe-mode note = %q", got)
+	}
+	if got := eqUnavailableText(nil, false); !strings.Contains(got, "still starting") {
+		t.Fatalf("note before VLC is ready = %q", got)
+	}
+}
+
+func TestOnboardingHintDismissalPersists(t *testing.T) {
+	test.NewApp()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+	a := &App{config: &config.Config{ShowOnboarding: true}}
+	hint := a.buildOnboardingHint()
+	if !hint.Visible() {
+		t.Fatal("hint hidden on first run")
+	}
+	a.dismissOnboarding()
+	if hint.Visible() || a.config.ShowOnboarding {
+		t.Fatal("hint still shown after dismissal")
+	}
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if saved.ShowOnboarding {
+		t.Fatal("dismissal not saved")
+	}
+	if (&App{config: saved}).buildOnboardingHint().Visible() {
+		t.Fatal("hint shown again after dismissal")
+	}
+}
+
+func TestPreviewTickerText(t *testing.T) {
+	p := config.Preset{Name: " KEXP ", URL: "https://kexp.example/live"}
+	if got := previewTickerText(p, ""); !strings.HasPrefix(got, previewTickerPrefix) || !strings.Contains(got, "KEXP – waiting") {
+		t.Fatalf("waiting text = %q", got)
+	}
+	if got, want := previewTickerText(p, "Artist - Song"), previewTickerPrefix+"KEXP: Artist - Song"; got != want {
+		t.Fatalf("title text = %q, want %q", got, want)
+	}
+	// an unnamed preset is shown by its URL
+	if got := previewTickerText(config.Preset{URL: p.URL}, "x"); !strings.Contains(got, p.URL+": x") {
+		t.Fatalf("unnamed preset text = %q", got)
+	}
+}
+
+func TestFormatStationDetails(t *testing.T) {
+	got := formatStationDetails(" KEXP ", "Eclectic", 128, 42, "https://kexp.example/live")
+	if want := "KEXP · Eclectic · 128 kbps · 42 listeners · https://kexp.example/live"; got != want {
+		t.Fatalf("full details = %q, want %q", got, want)
+	}
+	if got := formatStationDetails("", "", 0, 1, ""); got != "1 listener" {
+		t.Fatalf("one listener = %q", got)
+	}
+	if got := formatStationDetails("", " ", 0, 0, ""); got != "" {
+		t.Fatalf("nothing known = %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package radioapp
+
+import (
+	"log"
+
+	config "github.com/edward-ap/miniradio/internal/config"
+	ui "github.com/edward-ap/miniradio/internal/ui"
+)
+
+// startConfigWatch follows outside edits to config.json when enabled in
+// config, applying each one on the UI thread.
+func (a *App) startConfigWatch() {
+	if !a.config.WatchConfigFile {
+		return
+	}
+	w, err := config.Watch()
+	if err != nil {
+		log.Printf("config watch: %v", err)
+		return
+	}
+	a.configWatch = w
+	go func() {
+		for c := range w.Changes() {
+			ui.CallOnMain(func() { a.applyExternalConfig(c) })
+		}
+	}()
+}
+
+// stopConfigWatch ends the watch started by startConfigWatch, if any.
+func (a *App) stopConfigWatch() {
+	if a.configWatch != nil {
+		_ = a.configWatch.Close()
+		a.configWatch = nil
+	}
+}
+
+// applyExternalConfig takes in config.json as edited by another program:
+// stations, EQ presets and preferences are replaced and an open Settings or
+// EQ drawer is rebuilt, while this session's window stays where it is. The
+// file is not saved back, so the edit is never reformatted under the editor.
+func (a *App) applyExternalConfig(in *config.Config) {
+	in.WindowX, in.WindowY = a.config.WindowX, a.config.WindowY
+	in.WindowW, in.WindowH = a.config.WindowW, a.config.WindowH
+	in.WindowPosValid = a.config.WindowPosValid
+	*a.config = *in
+	if !a.config.WatchConfigFile {
+		a.stopConfigWatch()
+	}
+	a.applyReplacedConfig()
+	if mode := a.drawerMode; mode == "settings" || mode == "equalizer" {
+		a.setDrawerTarget("")
+		a.setDrawerTarget(mode)
+	}
+	a.ShowToast("Reloaded config.json")
+}
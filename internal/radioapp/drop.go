@@ -0,0 +1,154 @@
+package radioapp
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	metadata "github.com/edward-ap/miniradio/internal/metadata"
+)
+
+// maxDroppedFileSize bounds playlist and shortcut files read from a drop.
+const maxDroppedFileSize = 64 << 10
+
+// handleDrop plays a stream URL, a .pls/.m3u playlist or an internet shortcut
+// (.url) dropped onto the window, then offers to keep it in an empty preset.
+// Only the first dropped item is used.
+func (a *App) handleDrop(_ fyne.Position, uris []fyne.URI) {
+	if len(uris) == 0 {
+		return
+	}
+	stream, err := droppedStreamURL(uris[0])
+	if err != nil {
+		dialog.ShowError(err, a.w)
+		return
+	}
+	a.playDroppedURL(stream)
+	a.offerDroppedPreset(stream)
+}
+
+// droppedStreamURL turns a dropped item into an http(s) stream URL. Files are
+// read as playlists, or as Windows internet shortcuts, which is what dragging
+// a link out of a browser usually produces.
+func droppedStreamURL(u fyne.URI) (string, error) {
+	switch strings.ToLower(u.Scheme()) {
+	case "http", "https":
+		return u.String(), nil
+	case "file":
+	default:
+		return "", fmt.Errorf("cannot play %s: drop a stream URL or a .pls/.m3u playlist", u.String())
+	}
+	name := u.Name()
+	f, err := os.Open(u.Path())
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	body, err := io.ReadAll(io.LimitReader(f, maxDroppedFileSize))
+	if err != nil {
+		return "", err
+	}
+
+	var entry string
+	if strings.EqualFold(path.Ext(name), ".url") {
+		entry = internetShortcutURL(string(body))
+	} else if entry, err = metadata.ParsePlaylist(name, body); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	if !isStreamURL(entry) {
+		return "", fmt.Errorf("%s does not point at an http(s) stream", name)
+	}
+	return strings.TrimSpace(entry), nil
+}
+
+// internetShortcutURL reads the URL= line of a .url file.
+func internetShortcutURL(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && strings.EqualFold(key, "URL") {
+			return strings.TrimSpace(val)
+		}
+	}
+	return ""
+}
+
+// isStreamURL reports whether s is an absolute http(s) URL with a host.
+func isStreamURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+// isWebSocketURL reports whether s is an absolute ws(s) URL with a host.
+func isWebSocketURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "ws" || u.Scheme == "wss"
+}
+
+// playDroppedURL switches playback to stream like a -url start would: the
+// remembered preset is dropped unless it already plays the same stream.
+func (a *App) playDroppedURL(stream string) {
+	applyStartURL(a.config, stream)
+	_ = a.config.Save()
+	a.setWindowTitleForCurrentPreset()
+	if a.player == nil {
+		a.togglePlay()
+		return
+	}
+	if a.player.IsPlaying() {
+		if a.config.CrossfadeMs > 0 {
+			a.resolveThen(stream, func() { a.crossfadeOrRestart(stream) })
+			return
+		}
+		a.player.Stop()
+	}
+	a.togglePlay()
+}
+
+// offerDroppedPreset asks whether to save stream into the first preset slot
+// without a URL. Nothing is asked when a preset already has it or all slots
+// are taken.
+func (a *App) offerDroppedPreset(stream string) {
+	free := -1
+	for i, p := range a.config.Presets {
+		u := strings.TrimSpace(p.URL)
+		if u == stream {
+			return
+		}
+		if u == "" && free < 0 {
+			free = i
+		}
+	}
+	if free < 0 {
+		return
+	}
+	msg := fmt.Sprintf("Save %s as preset %d?", stream, free+1)
+	dialog.ShowConfirm("Save station", msg, func(ok bool) {
+		defer a.ensureShortcutFocus()
+		if !ok || strings.TrimSpace(a.config.Presets[free].URL) != "" {
+			return
+		}
+		p := &a.config.Presets[free]
+		p.URL = stream
+		p.ForgetMetadata()
+		if a.config.CurrentURL == stream {
+			a.config.LastPreset = free
+		}
+		_ = a.config.Save()
+		if a.drawerMode == "settings" {
+			a.setDrawerTarget("")
+			a.setDrawerTarget("settings")
+		}
+	}, a.w)
+}
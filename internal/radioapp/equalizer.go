@@ -1,14 +1,19 @@
 package radioapp
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"math"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
 
 	vlc "github.com/adrg/libvlc-go/v3"
@@ -41,6 +46,12 @@ type Equalizer struct {
 	preampMax float64
 	ampMin    float64
 	ampMax    float64
+	// flat is the neutral equalizer the player hears during an A/B
+	// comparison; nil otherwise
+	flat *vlc.Equalizer
+	// bypassed holds the player on no equalizer at all, whatever the
+	// station's preset, until the drawer's toggle lifts it
+	bypassed bool
 }
 
 // NewEqualizer instantiates a libVLC equalizer and caches preset/band metadata
@@ -81,41 +92,124 @@ func (e *Equalizer) Release() {
 		e.eq.Release()
 		e.eq = nil
 	}
+	if e.flat != nil {
+		e.flat.Release()
+		e.flat = nil
+	}
+}
+
+// push hands the current curve to the player unless an A/B comparison holds
+// it on flat or the EQ is bypassed; the curve is then applied when that ends.
+func (e *Equalizer) push(player *playerpkg.Player) error {
+	if e.flat != nil || e.bypassed {
+		return nil
+	}
+	return player.SetAudioEqualizer(e.eq)
+}
+
+// Comparing reports whether the player is temporarily on flat for an A/B
+// comparison.
+func (e *Equalizer) Comparing() bool { return e.flat != nil }
+
+// SetCompare switches the player to a flat equalizer (on) or back to the
+// current curve (off). Slider edits and preset changes made meanwhile are
+// kept and heard once the comparison ends.
+func (e *Equalizer) SetCompare(player *playerpkg.Player, on bool) error {
+	if on == e.Comparing() {
+		return nil
+	}
+	if on {
+		flat, err := vlc.NewEqualizer()
+		if err != nil {
+			return err
+		}
+		e.flat = flat
+		if e.bypassed {
+			return nil
+		}
+		return player.SetAudioEqualizer(flat)
+	}
+	flat := e.flat
+	e.flat = nil
+	err := e.push(player)
+	flat.Release()
+	return err
+}
+
+// Bypassed reports whether EQ processing is switched off globally.
+func (e *Equalizer) Bypassed() bool { return e.bypassed }
+
+// SetBypass releases the player's equalizer (on) or hands it back the
+// current curve, or flat during an A/B comparison (off). Unlike the Off
+// preset it leaves every station's preset selection alone.
+func (e *Equalizer) SetBypass(player *playerpkg.Player, on bool) error {
+	if on == e.bypassed {
+		return nil
+	}
+	e.bypassed = on
+	if on {
+		return player.SetAudioEqualizer(nil)
+	}
+	if e.flat != nil {
+		return player.SetAudioEqualizer(e.flat)
+	}
+	return e.push(player)
 }
 
 // PresetNamesWithFlat returns the preset order used in the EQ drawer:
-// Flat, VLC built-ins (excluding duplicates), and lastly custom entries.
+// Flat, bundled and VLC built-ins (excluding duplicates), and lastly custom entries.
 func (e *Equalizer) PresetNamesWithFlat(custom []string) []string {
 	// For the EQ drawer: do not include Auto or Off; include exactly one Flat at the top,
-	// then VLC built-ins (excluding duplicate Flat/Auto), then custom presets.
-	out := []string{EQNameFlat}
-	for _, name := range e.presets {
-		if strings.EqualFold(name, EQNameFlat) || strings.EqualFold(name, EQNameAuto) {
-			continue
-		}
-		out = append(out, name)
-	}
+	// then built-ins (excluding duplicate Flat/Auto), then custom presets.
+	out := append([]string{EQNameFlat}, e.builtInNames()...)
 	out = append(out, custom...)
 	return out
 }
 
 // PresetNamesForSettings returns options for the Settings drawer dropdown:
-// Off, Flat, VLC built-ins (without duplicates), followed by custom entries.
+// Off, Flat, bundled and VLC built-ins (without duplicates), followed by custom entries.
 func (e *Equalizer) PresetNamesForSettings(custom []string) []string {
-	out := []string{EQNameOff, EQNameFlat}
-	for _, name := range e.presets {
+	out := append([]string{EQNameOff, EQNameFlat}, e.builtInNames()...)
+	out = append(out, custom...)
+	return out
+}
+
+// builtInNames lists the bundled eqmodel presets followed by the VLC presets
+// whose names they do not already cover. Flat and Auto are left to callers.
+func (e *Equalizer) builtInNames() []string {
+	var out []string
+	seen := func(name string) bool {
 		if strings.EqualFold(name, EQNameFlat) || strings.EqualFold(name, EQNameAuto) {
-			continue
+			return true
+		}
+		for _, n := range out {
+			if strings.EqualFold(n, name) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range eqmodel.PresetNames() {
+		if !seen(name) {
+			out = append(out, name)
+		}
+	}
+	for _, name := range e.presets {
+		if !seen(name) {
+			out = append(out, name)
 		}
-		out = append(out, name)
 	}
-	out = append(out, custom...)
 	return out
 }
 
 // Bands reports the slider band center frequencies and count.
 func (e *Equalizer) Bands() ([]float64, int) { return e.bandsHz, e.bandCount }
 
+// fitGains remaps gains stored for fromHz onto this equalizer's bands.
+func (e *Equalizer) fitGains(gains, fromHz []float64) []float64 {
+	return eqmodel.RemapGains(gains, fromHz, e.bandsHz)
+}
+
 // Range reports allowed dB ranges for preamp and per-band sliders.
 func (e *Equalizer) Range() (preMin, preMax, ampMin, ampMax float64) {
 	return e.preampMin, e.preampMax, e.ampMin, e.ampMax
@@ -124,81 +218,109 @@ func (e *Equalizer) Range() (preMin, preMax, ampMin, ampMax float64) {
 // ApplyPresetName selects the given preset name (built-in, manual, or custom)
 // and applies it to the provided Player. EQNameOff disables EQ entirely.
 func (e *Equalizer) ApplyPresetName(player *playerpkg.Player, name string, custom map[string]config.EQPresetData) error {
-	name = strings.TrimSpace(name)
+	c := e.resolveCurve(name, custom)
 	// Off disables EQ entirely for the player
-	if strings.EqualFold(name, EQNameOff) {
+	if c.off {
 		if e.eq != nil {
 			_ = e.eq.Release()
 			e.eq = nil
 		}
-		return player.SetAudioEqualizer(nil)
+		return e.push(player)
 	}
-	// legacy Auto behaves like Flat (enabled EQ with all 0 dB)
-	if name == "" || strings.EqualFold(name, EQNameAuto) || strings.EqualFold(name, EQNameFlat) {
-		newEq, err := vlc.NewEqualizer()
-		if err != nil {
-			return err
-		}
-		if e.eq != nil {
-			_ = e.eq.Release()
-		}
-		e.eq = newEq
-		return player.SetAudioEqualizer(e.eq)
+	var newEq *vlc.Equalizer
+	var err error
+	if c.vlcPreset >= 0 {
+		newEq, err = vlc.NewEqualizerFromPreset(uint(c.vlcPreset))
+	} else {
+		newEq, err = vlc.NewEqualizer()
 	}
-	if data, ok := custom[name]; ok {
-		newEq, err := vlc.NewEqualizer()
-		if err != nil {
-			return err
+	if err != nil {
+		return err
+	}
+	if c.gains != nil {
+		_ = newEq.SetPreampValue(c.preamp)
+		for i, v := range c.gains {
+			_ = newEq.SetAmpValueAtIndex(v, uint(i))
 		}
-		_ = newEq.SetPreampValue(float64(data.Preamp))
-		for i, v := range data.Bands {
-			if i >= e.bandCount {
+	}
+	if e.eq != nil {
+		_ = e.eq.Release()
+	}
+	e.eq = newEq
+	return e.push(player)
+}
+
+// eqCurve is what a preset name stands for: no equalizer at all, one of
+// libVLC's presets, or an explicit preamp and band gains. With none of those
+// set it is Flat.
+type eqCurve struct {
+	off       bool
+	vlcPreset int // index into libVLC's presets, or -1
+	preamp    float64
+	gains     []float64 // fitted to this equalizer's bands
+}
+
+// resolveCurve looks name up: Off, Flat (or legacy Auto, or no name), then
+// custom (matched ignoring case), then bundled curves, which take precedence
+// over VLC presets of the same name so the sliders (filled from eqmodel)
+// match what is heard, then VLC presets; anything else falls back to Flat.
+func (e *Equalizer) resolveCurve(name string, custom map[string]config.EQPresetData) eqCurve {
+	name = strings.TrimSpace(name)
+	flat := eqCurve{vlcPreset: -1}
+	if strings.EqualFold(name, EQNameOff) {
+		return eqCurve{off: true, vlcPreset: -1}
+	}
+	if name == "" || strings.EqualFold(name, EQNameAuto) || strings.EqualFold(name, EQNameFlat) {
+		return flat
+	}
+	data, ok := custom[name]
+	if !ok {
+		for k, v := range custom {
+			if strings.EqualFold(strings.TrimSpace(k), name) {
+				data, ok = v, true
 				break
 			}
-			_ = newEq.SetAmpValueAtIndex(float64(v), uint(i))
-		}
-		if e.eq != nil {
-			_ = e.eq.Release()
 		}
-		e.eq = newEq
-		return player.SetAudioEqualizer(e.eq)
 	}
-	// built-in VLC preset by name
-	idx := -1
+	if ok && data.Tone != nil {
+		// tone presets are re-derived for these bands rather than remapped
+		return eqCurve{vlcPreset: -1, preamp: float64(data.Preamp), gains: eqmodel.ToneGains(toneSetting(*data.Tone), e.bandsHz)}
+	}
+	if ok {
+		return eqCurve{vlcPreset: -1, preamp: float64(data.Preamp), gains: e.fitGains(float32s(data.Bands), float32s(data.Freqs))}
+	}
+	if preset, ok := eqmodel.FindPresetByName(name); ok {
+		return eqCurve{vlcPreset: -1, preamp: preset.Preamp, gains: e.fitGains(preset.Gains, eqmodel.ReferenceBandsHz)}
+	}
 	for i, s := range e.presets {
 		if strings.EqualFold(s, name) {
-			idx = i
-			break
-		}
-	}
-	if idx >= 0 {
-		newEq, err := vlc.NewEqualizerFromPreset(uint(idx))
-		if err != nil {
-			return err
-		}
-		if e.eq != nil {
-			_ = e.eq.Release()
+			return eqCurve{vlcPreset: i}
 		}
-		e.eq = newEq
-		return player.SetAudioEqualizer(e.eq)
 	}
-	// fallback to Flat
-	newEq, err := vlc.NewEqualizer()
-	if err != nil {
-		return err
+	return flat
+}
+
+// stationEQCurves is the custom map ApplyPresetName needs for the station at
+// idx: the custom presets by name plus, under Manual, the curve the station
+// was last edited to, so switching back to it restores its exact curve.
+func (a *App) stationEQCurves(idx int) map[string]config.EQPresetData {
+	curves := make(map[string]config.EQPresetData, len(a.config.CustomEQPresets)+1)
+	for _, ce := range a.config.CustomEQPresets {
+		curves[strings.TrimSpace(ce.Name)] = ce
 	}
-	if e.eq != nil {
-		_ = e.eq.Release()
+	if idx >= 0 && idx < len(a.config.Presets) {
+		if m := a.config.Presets[idx].ManualEQ; m != nil {
+			curves[EQNameManual] = *m
+		}
 	}
-	e.eq = newEq
-	return player.SetAudioEqualizer(e.eq)
+	return curves
 }
 
 // SetPreamp updates the global EQ preamp, clamping to supported ranges and
 // applying the change to libVLC.
 func (e *Equalizer) SetPreamp(player *playerpkg.Player, db float64) error {
 	_ = e.eq.SetPreampValue(db)
-	return player.SetAudioEqualizer(e.eq)
+	return e.push(player)
 }
 
 // SetBand adjusts a specific band slider and pushes the change to libVLC.
@@ -207,13 +329,27 @@ func (e *Equalizer) SetBand(player *playerpkg.Player, band int, db float64) erro
 		return nil
 	}
 	_ = e.eq.SetAmpValueAtIndex(db, uint(band))
-	return player.SetAudioEqualizer(e.eq)
+	return e.push(player)
+}
+
+// SetBands replaces every band gain at once and pushes the curve to libVLC;
+// gains beyond the band count are ignored.
+func (e *Equalizer) SetBands(player *playerpkg.Player, gains []float64) error {
+	for i, v := range gains {
+		if i >= e.bandCount {
+			break
+		}
+		_ = e.eq.SetAmpValueAtIndex(v, uint(i))
+	}
+	return e.push(player)
 }
 
 // BuildEqualizerDrawer builds the EQ drawer UI and returns the content and preferred height.
 func BuildEqualizerDrawer(a *App) (fyne.CanvasObject, float32) {
 	if a.eq == nil {
-		return widget.NewLabel("Equalizer unavailable"), 200
+		note := widget.NewLabel(eqUnavailableText(a.eqErr, a.vlcErr != nil))
+		note.Wrapping = fyne.TextWrapWord
+		return note, 120
 	}
 
 	a.rebuildCustomPresetLists()
@@ -221,9 +357,12 @@ func BuildEqualizerDrawer(a *App) (fyne.CanvasObject, float32) {
 	a.eqSliderValues = make([]float64, bandCount+1)
 	a.eqBandSliders = make([]*ui.VerticalSlider, bandCount)
 	a.eqPreampSlider = nil
+	a.eqCurve = nil
+	a.eqToneSliders = nil
 	a.eqNameEntry = nil
 	a.eqSaveButton = nil
 	a.eqDeleteButton = nil
+	a.eqExportButton = nil
 	a.eqSlidersSilent = false
 
 	defaults := eqmodel.DefaultPresets()
@@ -237,17 +376,91 @@ func BuildEqualizerDrawer(a *App) (fyne.CanvasObject, float32) {
 		Current: current,
 	}
 
-	sliders := a.buildEQSlidersSection(a.eqModel)
+	// the tip floats over whichever slider view is showing
+	a.eqTip = ui.NewValueTip()
+	a.eqGraphicView = a.buildEQSlidersSection(a.eqModel)
+	a.eqToneView = a.buildEQToneSection()
+	a.showEQMode()
 	presets := a.buildEQPresetsSection(a.eqModel)
 	buttons := a.buildEQButtonsSection(a.eqModel)
 
 	header := container.NewBorder(nil, nil,
-		widget.NewLabel("Presets"),
+		container.NewHBox(a.buildEQBypassToggle(), a.buildEQModeSelect(), widget.NewLabel("Presets")),
 		buttons,
 		presets,
 	)
+	sliders := container.NewStack(a.eqGraphicView, a.eqToneView, a.eqTip.CanvasObject())
 	content := container.NewVBox(header, widget.NewSeparator(), sliders)
-	return content, 260
+	return content, 300
+}
+
+// eqUnavailableText explains why the drawer has no equalizer: libVLC is
+// still starting (no error yet), or err stopped it, saying whether playback
+// is affected.
+func eqUnavailableText(err error, safeMode bool) string {
+	if err == nil {
+		return "Equalizer not ready yet: VLC is still starting."
+	}
+	msg := "Equalizer unavailable: " + err.Error()
+	if safeMode {
+		return msg + ". VLC did not start, so playback is off; stations and their EQ presets can still be set in Settings."
+	}
+	return msg + ". Playback is not affected; EQ presets chosen in Settings apply once the equalizer works again."
+}
+
+// eqModeLabels name the drawer modes in the mode dropdown, graphic first.
+var eqModeLabels = []string{"Graphic", "Tone"}
+
+// eqMode is the drawer mode chosen in config.
+func (a *App) eqMode() eqmodel.Mode { return eqmodel.ParseMode(a.config.EQMode) }
+
+// buildEQModeSelect switches the drawer between a slider per band and the
+// bass/mid/treble tone controls, and remembers the choice. Both edit the
+// same curve, so switching never changes what is heard.
+func (a *App) buildEQModeSelect() *widget.Select {
+	sel := widget.NewSelect(eqModeLabels, nil)
+	if a.eqMode() == eqmodel.ModeTone {
+		sel.SetSelected(eqModeLabels[1])
+	} else {
+		sel.SetSelected(eqModeLabels[0])
+	}
+	sel.OnChanged = func(label string) {
+		defer a.ensureShortcutFocus()
+		mode := eqmodel.ParseMode(label)
+		if mode == a.eqMode() {
+			return
+		}
+		a.config.EQMode = string(mode)
+		_ = a.config.Save()
+		a.showEQMode()
+	}
+	return sel
+}
+
+// showEQMode shows the slider view for the current mode and hides the other.
+func (a *App) showEQMode() {
+	if a.eqGraphicView == nil || a.eqToneView == nil {
+		return
+	}
+	if a.eqMode() == eqmodel.ModeTone {
+		a.eqGraphicView.Hide()
+		a.eqToneView.Show()
+		return
+	}
+	a.eqToneView.Hide()
+	a.eqGraphicView.Show()
+}
+
+// buildEQBypassToggle switches EQ processing on and off for every station at
+// once, for comparing with and without the equalizer; presets stay as chosen.
+func (a *App) buildEQBypassToggle() *widget.Check {
+	toggle := widget.NewCheck("EQ", nil)
+	toggle.SetChecked(!a.eq.Bypassed())
+	toggle.OnChanged = func(on bool) {
+		defer a.ensureShortcutFocus()
+		_ = a.eq.SetBypass(a.player, !on)
+	}
+	return toggle
 }
 
 // rebuildCustomPresetLists refreshes the cached map and slice of custom EQ
@@ -274,25 +487,16 @@ func (a *App) buildEQSlidersSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 	preMin, preMax, ampMin, ampMax := a.eq.Range()
 	preamp := ui.NewVerticalSlider(preMin, preMax)
 	preamp.Step = 0.5
+	preamp.OnUnhandledKey = a.handleShortcutKey
 	a.eqPreampSlider = preamp
+	preamp.OnDrag = func(done bool) { a.showEQValueTip(preamp, done) }
 	preamp.OnChanged = func(v float64) {
 		if a.eqSlidersSilent {
 			return
 		}
 		a.updateEQSliderState(0, v)
 		_ = a.eq.SetPreamp(a.player, v)
-		if a.eqDrawerPreset != nil {
-			a.silentUpdating = true
-			a.eqDrawerPreset.SetSelected(EQNameManual)
-			a.silentUpdating = false
-		}
-		if a.eqSaveButton != nil {
-			a.eqSaveButton.Enable()
-		}
-		if a.eqDeleteButton != nil {
-			a.eqDeleteButton.Disable()
-		}
-		a.updateEQButtonsForSelection(EQNameManual)
+		a.markEQManual()
 	}
 
 	bandsHz, _ := a.eq.Bands()
@@ -301,6 +505,8 @@ func (a *App) buildEQSlidersSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 	for i := 0; i < len(a.eqBandSliders); i++ {
 		s := ui.NewVerticalSlider(ampMin, ampMax)
 		s.Step = 0.5
+		s.OnUnhandledKey = a.handleShortcutKey
+		s.OnDrag = func(done bool) { a.showEQValueTip(s, done) }
 		idx := i
 		s.OnChanged = func(v float64) {
 			if a.eqSlidersSilent {
@@ -308,18 +514,7 @@ func (a *App) buildEQSlidersSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 			}
 			a.updateEQSliderState(idx+1, v)
 			_ = a.eq.SetBand(a.player, idx, v)
-			if a.eqDrawerPreset != nil {
-				a.silentUpdating = true
-				a.eqDrawerPreset.SetSelected(EQNameManual)
-				a.silentUpdating = false
-			}
-			if a.eqSaveButton != nil {
-				a.eqSaveButton.Enable()
-			}
-			if a.eqDeleteButton != nil {
-				a.eqDeleteButton.Disable()
-			}
-			a.updateEQButtonsForSelection(EQNameManual)
+			a.markEQManual()
 		}
 		a.eqBandSliders[i] = s
 		const bandCellW = float32(22)
@@ -351,7 +546,7 @@ func (a *App) buildEQSlidersSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 	preampRow := container.NewHBox(rotCell, preampCell)
 	scaleCol := container.NewVBox(
 		layout.NewSpacer(),
-		makeDBScaleColumnFixed(bandHeight),
+		makeDBScaleColumnFixed(bandHeight, ampMin, ampMax),
 		layout.NewSpacer(),
 	)
 	preCol := container.NewVBox(preampRow)
@@ -359,7 +554,184 @@ func (a *App) buildEQSlidersSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 	labelsGrid := container.NewGridWithColumns(len(a.eqBandSliders), freqLabels...)
 	centerArea := container.NewVBox(bandsGrid, labelsGrid)
 	leftBox := container.NewHBox(preCol, widget.NewSeparator(), scaleCol, widget.NewSeparator())
-	return container.NewBorder(nil, nil, leftBox, nil, centerArea)
+	// the curve spans the band grid only, so its points sit above the sliders
+	a.eqCurve = ui.NewEQCurve(len(a.eqBandSliders), ampMin, ampMax)
+	a.refreshEQCurve()
+	indent := canvas.NewRectangle(color.NRGBA{0, 0, 0, 0})
+	indent.SetMinSize(fyne.NewSize(leftBox.MinSize().Width, 1))
+	curveRow := container.NewBorder(nil, nil, indent, nil, a.eqCurve)
+	return container.NewBorder(curveRow, nil, leftBox, nil, centerArea)
+}
+
+// eqToneCellW is the width given to each tone control slider.
+const eqToneCellW = float32(48)
+
+// eqToneLabels name the tone controls from low to high.
+var eqToneLabels = []string{"Bass", "Mid", "Treble"}
+
+// buildEQToneSection renders tone mode: bass, mid and treble sliders that
+// reshape every band at once. The preamp keeps its graphic-mode setting.
+func (a *App) buildEQToneSection() fyne.CanvasObject {
+	_, _, ampMin, ampMax := a.eq.Range()
+	a.eqToneSliders = make([]*ui.VerticalSlider, len(eqToneLabels))
+	cols := make([]fyne.CanvasObject, 0, len(eqToneLabels))
+	for i, name := range eqToneLabels {
+		s := ui.NewVerticalSlider(ampMin, ampMax)
+		s.Step = 0.5
+		s.OnUnhandledKey = a.handleShortcutKey
+		s.OnDrag = func(done bool) { a.showEQValueTip(s, done) }
+		s.OnChanged = func(float64) {
+			if a.eqSlidersSilent {
+				return
+			}
+			a.applyEQTone()
+		}
+		a.eqToneSliders[i] = s
+		cell := container.New(layout.NewGridWrapLayout(fyne.NewSize(eqToneCellW, s.MinSize().Height)), s)
+		lbl := widget.NewLabel(name)
+		lbl.Alignment = fyne.TextAlignCenter
+		cols = append(cols, container.NewVBox(cell, lbl))
+	}
+	return container.NewCenter(container.NewHBox(cols...))
+}
+
+// eqTone reads the tone controls, or fits them to the band sliders while
+// the drawer has none.
+func (a *App) eqTone() eqmodel.Tone {
+	if len(a.eqToneSliders) == len(eqToneLabels) {
+		return eqmodel.Tone{
+			Bass:   a.eqToneSliders[0].Value,
+			Mid:    a.eqToneSliders[1].Value,
+			Treble: a.eqToneSliders[2].Value,
+		}
+	}
+	var gains []float64
+	if len(a.eqSliderValues) > 1 {
+		gains = a.eqSliderValues[1:]
+	}
+	return eqmodel.ToneFromGains(gains, a.eq.bandsHz)
+}
+
+// applyEQTone turns the tone controls into band gains, plays them and marks
+// the curve Manual like any slider edit.
+func (a *App) applyEQTone() {
+	gains := eqmodel.ToneGains(a.eqTone(), a.eq.bandsHz)
+	for i, g := range gains {
+		a.updateEQSliderState(i+1, g)
+	}
+	_ = a.eq.SetBands(a.player, gains)
+	a.applySliderValuesToWidgets()
+	a.markEQManual()
+}
+
+// withEQTone marks data as a tone preset with the current tone settings
+// while the drawer is in tone mode.
+func (a *App) withEQTone(data config.EQPresetData) config.EQPresetData {
+	if a.eqMode() != eqmodel.ModeTone {
+		return data
+	}
+	t := a.eqTone()
+	data.Tone = &config.EQToneData{Bass: float32(t.Bass), Mid: float32(t.Mid), Treble: float32(t.Treble)}
+	return data
+}
+
+// toneSetting widens a stored tone setting for eqmodel math.
+func toneSetting(t config.EQToneData) eqmodel.Tone {
+	return eqmodel.Tone{Bass: float64(t.Bass), Mid: float64(t.Mid), Treble: float64(t.Treble)}
+}
+
+// showEQValueTip shows s's exact value beside its thumb while it is dragged
+// and hides it when the drag ends.
+func (a *App) showEQValueTip(s *ui.VerticalSlider, done bool) {
+	if done {
+		a.eqTip.Hide()
+		return
+	}
+	d := fyne.CurrentApp().Driver()
+	at := d.AbsolutePositionForObject(s).
+		Subtract(d.AbsolutePositionForObject(a.eqTip.CanvasObject())).
+		Add(s.ThumbPosition())
+	a.eqTip.ShowAt(eqValueText(s.Value), at)
+}
+
+// eqValueText formats a gain for the drag tip, signed to one decimal.
+func eqValueText(v float64) string {
+	if math.Abs(v) < 0.05 {
+		return "0.0 dB"
+	}
+	return fmt.Sprintf("%+.1f dB", v)
+}
+
+// markEQManual switches the drawer to the Manual state after a slider edit.
+func (a *App) markEQManual() {
+	a.eqManual = true
+	a.showEQDrawerSelection(EQNameManual)
+	// the curve stays with the station; it is saved as the drawer closes
+	if idx := a.currentPresetIndex(); idx >= 0 && a.eq != nil {
+		data := a.manualEQData()
+		a.config.Presets[idx].EQ = EQNameManual
+		a.config.Presets[idx].ManualEQ = &data
+		if sel := a.presetEQSelect(idx); sel != nil {
+			sel.Selected = EQNameManual
+			sel.Refresh()
+		}
+	}
+	if a.eqSaveButton != nil {
+		a.eqSaveButton.Enable()
+	}
+	if a.eqDeleteButton != nil && a.eqUndo == nil {
+		a.eqDeleteButton.Disable()
+	}
+	a.updateEQButtonsForSelection(EQNameManual)
+}
+
+// captureManualEQ remembers an unsaved Manual curve in config as the drawer
+// closes, and forgets it when the drawer was left on a named preset.
+func (a *App) captureManualEQ() {
+	if a.eq == nil || len(a.eqSliderValues) == 0 {
+		return
+	}
+	if !a.eqManual {
+		if a.config.LastManualEQ != nil {
+			a.config.LastManualEQ = nil
+			_ = a.config.Save()
+		}
+		return
+	}
+	data := a.manualEQData()
+	a.config.LastManualEQ = &data
+	_ = a.config.Save()
+}
+
+// manualEQData is the current slider curve as a Manual preset.
+func (a *App) manualEQData() config.EQPresetData {
+	p := eqmodel.ExtractPresetFromSliders(a.eqSliderValues)
+	p.Name = EQNameManual
+	return a.withEQTone(presetToConfigData(p, a.eq.bandsHz))
+}
+
+// restoreManualEQ reapplies the curve captured by captureManualEQ to the
+// sliders and the player. It reports false when there is nothing to restore.
+func (a *App) restoreManualEQ() bool {
+	m := a.config.LastManualEQ
+	if m == nil || a.eq == nil || a.eq.eq == nil || len(a.eqSliderValues) == 0 {
+		return false
+	}
+	p := eqmodel.EQPreset{
+		Name:   EQNameManual,
+		Preamp: float64(m.Preamp),
+		Gains:  a.eq.fitGains(float32s(m.Bands), float32s(m.Freqs)),
+	}
+	_ = a.eq.eq.SetPreampValue(p.Preamp)
+	for i, v := range p.Gains {
+		_ = a.eq.eq.SetAmpValueAtIndex(v, uint(i))
+	}
+	_ = a.eq.push(a.player)
+	a.eqModel.Current = p
+	eqmodel.ApplyPresetToSliders(p, a.eqSliderValues)
+	a.applySliderValuesToWidgets()
+	a.eqManual = true
+	return true
 }
 
 // buildEQPresetsSection assembles the preset dropdown and name entry.
@@ -367,11 +739,17 @@ func (a *App) buildEQSlidersSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 func (a *App) buildEQPresetsSection(eqModel *eqmodel.EQModel) fyne.CanvasObject {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Preset name")
+	nameEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		return a.eqPresetNameProblem(s)
+	}
 	a.eqNameEntry = nameEntry
 
-	options := a.eq.PresetNamesWithFlat(a.eqCustomNames)
-	presetSel := widget.NewSelect(options, func(name string) {
-		if a.silentUpdating {
+	presetSel := widget.NewSelect(a.drawerEQOptions(), func(name string) {
+		// picking Manual again keeps the edit as it is
+		if a.silentUpdating || name == EQNameManual {
 			return
 		}
 		_ = a.eq.ApplyPresetName(a.player, name, a.eqCustomMap)
@@ -392,12 +770,47 @@ func (a *App) buildEQPresetsSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 	a.silentUpdating = true
 	presetSel.SetSelected(activeName)
 	a.silentUpdating = false
-	_ = a.eq.ApplyPresetName(a.player, activeName, a.eqCustomMap)
+	idx := a.currentPresetIndex()
+	curves := a.stationEQCurves(idx)
+	_ = a.eq.ApplyPresetName(a.player, activeName, curves)
+	if _, ok := curves[EQNameManual]; ok && strings.EqualFold(activeName, EQNameManual) {
+		// applySelectedPreset fills Manual sliders from the current curve
+		c := a.eq.resolveCurve(EQNameManual, curves)
+		a.eqModel.Current = eqmodel.EQPreset{Name: EQNameManual, Preamp: c.preamp, Gains: c.gains}
+	}
 	a.applySelectedPreset(activeName)
+	if idx < 0 && a.restoreManualEQ() {
+		a.showEQDrawerSelection(EQNameManual)
+	}
 
 	return container.NewGridWithColumns(2, presetSel, nameEntry)
 }
 
+// drawerEQOptions lists the EQ drawer choices. Manual is not a preset and
+// leads the list only while the sliders hold an unsaved edit, so the
+// dropdown can show that state.
+func (a *App) drawerEQOptions() []string {
+	opts := a.eq.PresetNamesWithFlat(a.eqCustomNames)
+	if a.eqManual {
+		opts = append([]string{EQNameManual}, opts...)
+	}
+	return opts
+}
+
+// showEQDrawerSelection shows name in the drawer dropdown without applying
+// it, adding or dropping the Manual entry to match the current state.
+func (a *App) showEQDrawerSelection(name string) {
+	sel := a.eqDrawerPreset
+	if sel == nil {
+		return
+	}
+	sel.Options = a.drawerEQOptions()
+	a.silentUpdating = true
+	sel.SetSelected(name)
+	a.silentUpdating = false
+	sel.Refresh()
+}
+
 // buildEQButtonsSection renders Save/Delete controls for custom presets.
 // buildEQButtonsSection renders Save/Delete controls for custom presets.
 func (a *App) buildEQButtonsSection(eqModel *eqmodel.EQModel) fyne.CanvasObject {
@@ -406,75 +819,396 @@ func (a *App) buildEQButtonsSection(eqModel *eqmodel.EQModel) fyne.CanvasObject
 			return
 		}
 		n := strings.TrimSpace(a.eqNameEntry.Text)
-		if n == "" {
+		if err := a.eqPresetNameProblem(n); err != nil {
+			dialog.ShowError(fmt.Errorf("cannot save EQ preset: %w", err), a.w)
 			return
 		}
 		preset := eqmodel.ExtractPresetFromSliders(a.eqSliderValues)
 		preset.Name = n
-		data := presetToConfigData(preset)
-		if idx, ok := a.customEQIndex[n]; ok {
-			a.config.CustomEQPresets[idx] = data
-		} else {
-			a.config.CustomEQPresets = append(a.config.CustomEQPresets, data)
-			a.rebuildCustomEQIndex()
-		}
-		_ = a.config.Save()
-		a.rebuildCustomPresetLists()
-		a.refreshEQPresetOptions(n)
-		if a.eqDrawerPreset != nil {
-			a.silentUpdating = true
-			a.eqDrawerPreset.SetSelected(n)
-			a.silentUpdating = false
-		}
-		a.applySelectedPreset(n)
+		a.confirmEQPresetName(a.withEQTone(presetToConfigData(preset, a.eq.bandsHz)), "Save", a.storeCustomEQPreset)
 	})
 	saveBtn.Disable()
 	a.eqSaveButton = saveBtn
 
 	delBtn := widget.NewButton("Delete", func() {
-		if a.eqDrawerPreset == nil {
+		if a.eqUndo != nil {
+			a.undoEQDelete()
 			return
 		}
-		sel := a.eqDrawerPreset.Selected
-		if idx, ok := a.customEQIndex[sel]; ok {
-			a.config.CustomEQPresets = append(a.config.CustomEQPresets[:idx], a.config.CustomEQPresets[idx+1:]...)
-			a.rebuildCustomEQIndex()
-			_ = a.config.Save()
-			a.rebuildCustomPresetLists()
-			a.refreshEQPresetOptions(EQNameFlat)
-			if a.eqDrawerPreset != nil {
-				a.silentUpdating = true
-				a.eqDrawerPreset.SetSelected(EQNameFlat)
-				a.silentUpdating = false
-			}
-			_ = a.eq.ApplyPresetName(a.player, EQNameFlat, a.eqCustomMap)
-			a.applySelectedPreset(EQNameFlat)
-			for i := range a.eqPresetSelects {
-				if selw := a.eqPresetSelects[i]; selw != nil {
-					if strings.EqualFold(strings.TrimSpace(selw.Selected), strings.TrimSpace(sel)) {
-						a.silentUpdating = true
-						selw.SetSelected(EQNameFlat)
-						a.silentUpdating = false
-					}
-				}
-			}
-			if idxActive := a.currentPresetIndex(); idxActive >= 0 && idxActive < len(a.config.Presets) {
-				if strings.EqualFold(strings.TrimSpace(a.config.Presets[idxActive].EQ), strings.TrimSpace(sel)) {
-					a.config.Presets[idxActive].EQ = EQNameFlat
-					_ = a.config.Save()
-					_ = a.eq.ApplyPresetName(a.player, EQNameFlat, a.eqCustomMap)
-				}
-			}
+		if a.eqDrawerPreset != nil {
+			a.deleteEQPreset(a.eqDrawerPreset.Selected)
 		}
 	})
 	delBtn.Disable()
 	a.eqDeleteButton = delBtn
+	if a.eqUndo != nil {
+		delBtn.SetText("Undo")
+		delBtn.Enable()
+	}
 
-	if a.eqDrawerPreset != nil {
+	exportBtn := widget.NewButton("Export…", a.exportEQPreset)
+	exportBtn.Disable()
+	a.eqExportButton = exportBtn
+	importBtn := widget.NewButton("Import…", a.importEQPreset)
+
+	if a.eqManual {
+		a.updateEQButtonsForSelection(EQNameManual)
+	} else if a.eqDrawerPreset != nil {
 		a.updateEQButtonsForSelection(a.eqDrawerPreset.Selected)
 	}
 
-	return container.NewHBox(saveBtn, delBtn)
+	// A/B holds playback on flat without touching sliders, selection or
+	// Save state; tapping again restores the curve.
+	var abBtn *widget.Button
+	abBtn = widget.NewButton("A/B", func() {
+		on := !a.eq.Comparing()
+		if err := a.eq.SetCompare(a.player, on); err != nil {
+			return
+		}
+		if on {
+			abBtn.Importance = widget.HighImportance
+		} else {
+			abBtn.Importance = widget.MediumImportance
+		}
+		abBtn.Refresh()
+	})
+
+	return container.NewHBox(abBtn, saveBtn, delBtn, exportBtn, importBtn)
+}
+
+// eqUndoWindow is how long a deleted custom EQ preset can be brought back.
+const eqUndoWindow = 5 * time.Second
+
+// eqDeletion is a custom EQ preset removed from config, kept until its undo
+// window runs out.
+type eqDeletion struct {
+	data  config.EQPresetData
+	index int // position in config.CustomEQPresets
+	// stations are the config.Presets that used it and were moved to Flat
+	stations []int
+	timer    *time.Timer
+}
+
+// deleteEQPreset removes the custom preset name, switching the drawer and
+// every station using it to Flat, and turns the Delete button into Undo for
+// eqUndoWindow.
+func (a *App) deleteEQPreset(name string) {
+	idx, ok := a.customEQIndex[name]
+	if !ok {
+		return
+	}
+	data := a.config.CustomEQPresets[idx]
+	a.config.CustomEQPresets = append(a.config.CustomEQPresets[:idx], a.config.CustomEQPresets[idx+1:]...)
+	a.rebuildCustomEQIndex()
+	_ = a.config.Save()
+	a.rebuildCustomPresetLists()
+	a.refreshEQPresetOptions(EQNameFlat)
+	if a.eqDrawerPreset != nil {
+		a.silentUpdating = true
+		a.eqDrawerPreset.SetSelected(EQNameFlat)
+		a.silentUpdating = false
+	}
+	_ = a.eq.ApplyPresetName(a.player, EQNameFlat, a.eqCustomMap)
+	a.applySelectedPreset(EQNameFlat)
+	stations := detachStationEQ(a.config.Presets, name)
+	if len(stations) > 0 {
+		_ = a.config.Save()
+	}
+	a.showStationEQs(stations)
+	a.offerEQUndo(data, idx, stations)
+}
+
+// detachStationEQ moves every preset whose EQ is name to Flat and returns
+// their indexes.
+func detachStationEQ(presets []config.Preset, name string) []int {
+	var out []int
+	for i := range presets {
+		if strings.EqualFold(strings.TrimSpace(presets[i].EQ), strings.TrimSpace(name)) {
+			presets[i].EQ = EQNameFlat
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// reattachStationEQ gives name back to the presets at stations that are still
+// on the Flat detachStationEQ left them with, returning the ones it changed.
+func reattachStationEQ(presets []config.Preset, stations []int, name string) []int {
+	var out []int
+	for _, i := range stations {
+		if i < len(presets) && presets[i].EQ == EQNameFlat {
+			presets[i].EQ = name
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// showStationEQs brings the Settings EQ selects of stations in line with
+// their presets and reapplies the EQ when the playing station is among them.
+func (a *App) showStationEQs(stations []int) {
+	active := a.currentPresetIndex()
+	for _, i := range stations {
+		if i < len(a.eqPresetSelects) && a.eqPresetSelects[i] != nil {
+			a.silentUpdating = true
+			a.eqPresetSelects[i].SetSelected(a.config.Presets[i].EQ)
+			a.silentUpdating = false
+		}
+		if i == active {
+			_ = a.eq.ApplyPresetName(a.player, a.config.Presets[i].EQ, a.eqCustomMap)
+		}
+	}
+}
+
+// offerEQUndo keeps data, and the stations that used it, restorable for
+// eqUndoWindow, through the Undo button or a tap on the ticker; a newer
+// deletion replaces an older one.
+func (a *App) offerEQUndo(data config.EQPresetData, index int, stations []int) {
+	if a.eqUndo != nil {
+		a.eqUndo.timer.Stop()
+	}
+	u := &eqDeletion{data: data, index: index, stations: stations}
+	u.timer = time.AfterFunc(eqUndoWindow, func() {
+		ui.CallOnMain(func() {
+			if a.eqUndo == u {
+				a.endEQUndo()
+			}
+		})
+	})
+	a.eqUndo = u
+	if a.eqDeleteButton != nil {
+		a.eqDeleteButton.SetText("Undo")
+		a.eqDeleteButton.Enable()
+	}
+	a.showTickerAction(fmt.Sprintf("Deleted '%s' — tap here to undo", data.Name), a.undoEQDelete)
+}
+
+// endEQUndo forgets the pending deletion and gives the Delete button back.
+func (a *App) endEQUndo() {
+	if a.eqUndo == nil {
+		return
+	}
+	a.eqUndo.timer.Stop()
+	a.eqUndo = nil
+	a.clearTickerAction()
+	if a.eqDeleteButton != nil {
+		a.eqDeleteButton.SetText("Delete")
+		if a.eqDrawerPreset != nil {
+			a.updateEQButtonsForSelection(a.eqDrawerPreset.Selected)
+		}
+	}
+}
+
+// undoEQDelete puts the preset deleted last back where it was, selects it
+// and gives it back to the stations that used it. A preset of the same name
+// created meanwhile is replaced.
+func (a *App) undoEQDelete() {
+	u := a.eqUndo
+	if u == nil {
+		return
+	}
+	a.endEQUndo()
+	if _, taken := a.customEQIndex[u.data.Name]; !taken {
+		at := min(u.index, len(a.config.CustomEQPresets))
+		a.config.CustomEQPresets = append(a.config.CustomEQPresets[:at],
+			append([]config.EQPresetData{u.data}, a.config.CustomEQPresets[at:]...)...)
+		a.rebuildCustomEQIndex()
+	}
+	a.addImportedEQPreset(u.data)
+	if restored := reattachStationEQ(a.config.Presets, u.stations, u.data.Name); len(restored) > 0 {
+		_ = a.config.Save()
+		a.showStationEQs(restored)
+	}
+	a.ShowToast(fmt.Sprintf("Restored '%s'", u.data.Name))
+}
+
+// storeCustomEQPreset adds data to the custom EQ presets, replacing one with
+// the same name, persists it and selects it in the drawer.
+func (a *App) storeCustomEQPreset(data config.EQPresetData) {
+	n := data.Name
+	if idx, ok := a.customEQIndex[n]; ok {
+		a.config.CustomEQPresets[idx] = data
+	} else {
+		a.config.CustomEQPresets = append(a.config.CustomEQPresets, data)
+		a.rebuildCustomEQIndex()
+	}
+	_ = a.config.Save()
+	a.rebuildCustomPresetLists()
+	a.refreshEQPresetOptions(n)
+	if a.eqDrawerPreset != nil {
+		a.silentUpdating = true
+		a.eqDrawerPreset.SetSelected(n)
+		a.silentUpdating = false
+	}
+	a.applySelectedPreset(n)
+}
+
+// exportEQPreset writes the selected custom EQ preset to a user-chosen file.
+func (a *App) exportEQPreset() {
+	if a.eqDrawerPreset == nil {
+		return
+	}
+	name := strings.TrimSpace(a.eqDrawerPreset.Selected)
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.w)
+			return
+		}
+		if w == nil {
+			return
+		}
+		path := w.URI().Path()
+		_ = w.Close()
+		if err := a.config.ExportPreset(name, path); err != nil {
+			dialog.ShowError(err, a.w)
+		}
+	}, a.w)
+	d.SetFileName(name + ".eq.json")
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	a.showFileDialog(d)
+}
+
+// importEQPreset reads a shared EQ preset, fits it to this equalizer's bands
+// and adds it to the custom presets, asking before replacing an existing one.
+func (a *App) importEQPreset() {
+	d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.w)
+			return
+		}
+		if r == nil {
+			return
+		}
+		path := r.URI().Path()
+		_ = r.Close()
+		data, err := config.ImportPreset(path)
+		if err != nil {
+			dialog.ShowError(err, a.w)
+			return
+		}
+		bandsHz, bands := a.eq.Bands()
+		got := len(data.Bands)
+		data = fitPresetBands(data, bandsHz)
+		if got != bands {
+			dialog.ShowInformation("EQ preset",
+				fmt.Sprintf("%q was made for %d bands; it was adjusted to this equalizer's %d bands.", data.Name, got, bands), a.w)
+		}
+		a.confirmEQPresetName(data, "Import", a.addImportedEQPreset)
+	}, a.w)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	a.showFileDialog(d)
+}
+
+// confirmEQPresetName stores a saved or imported preset through store,
+// prompting to overwrite or rename when a custom preset already has its name
+// and asking for another one when the name is reserved or built in. action
+// labels the rename dialog's confirm button.
+func (a *App) confirmEQPresetName(data config.EQPresetData, action string, store func(config.EQPresetData)) {
+	if err := a.eqPresetNameProblem(data.Name); err != nil {
+		a.renameEQPreset(data, action, store, err)
+		return
+	}
+	existing, taken := a.customEQName(data.Name)
+	if !taken {
+		store(data)
+		return
+	}
+	msg := widget.NewLabel(fmt.Sprintf("An EQ preset named %q already exists.", existing))
+	dialog.NewCustomConfirm("EQ preset", "Overwrite", "Rename…", msg, func(overwrite bool) {
+		if overwrite {
+			data.Name = existing
+			store(data)
+			return
+		}
+		a.renameEQPreset(data, action, store, nil)
+	}, a.w).Show()
+}
+
+// renameEQPreset asks for another name for data, saying why when problem is
+// set, and retries confirmEQPresetName with it.
+func (a *App) renameEQPreset(data config.EQPresetData, action string, store func(config.EQPresetData), problem error) {
+	entry := widget.NewEntry()
+	entry.SetText(a.uniqueEQPresetName(data.Name))
+	entry.Validator = func(s string) error { return a.eqPresetNameProblem(strings.TrimSpace(s)) }
+	items := []*widget.FormItem{widget.NewFormItem("Name", entry)}
+	if problem != nil {
+		items = append([]*widget.FormItem{widget.NewFormItem("", widget.NewLabel(problem.Error()+"."))}, items...)
+	}
+	dialog.NewForm("Rename EQ preset", action, "Cancel", items, func(ok bool) {
+		defer a.ensureShortcutFocus()
+		if !ok {
+			return
+		}
+		data.Name = strings.TrimSpace(entry.Text)
+		a.confirmEQPresetName(data, action, store)
+	}, a.w).Show()
+}
+
+// addImportedEQPreset stores data and makes it the active curve.
+func (a *App) addImportedEQPreset(data config.EQPresetData) {
+	a.storeCustomEQPreset(data)
+	_ = a.eq.ApplyPresetName(a.player, data.Name, a.eqCustomMap)
+}
+
+// eqPresetNameTaken reports whether name is used by a custom, built-in or
+// reserved preset.
+func (a *App) eqPresetNameTaken(name string) bool {
+	_, custom := a.customEQName(name)
+	return custom || a.eqPresetNameProblem(name) != nil
+}
+
+// eqPresetNameProblem explains why name cannot be given to a custom preset:
+// it is empty, one of the reserved Off, Flat, Auto and Manual, or the name of
+// a built-in preset, compared ignoring case. Names of other custom presets are
+// fine; saving under one overwrites it.
+func (a *App) eqPresetNameProblem(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("enter a preset name")
+	}
+	for _, r := range []string{EQNameOff, EQNameFlat, EQNameAuto, EQNameManual} {
+		if strings.EqualFold(name, r) {
+			return fmt.Errorf("%q is reserved", r)
+		}
+	}
+	for _, n := range a.eq.builtInNames() {
+		if strings.EqualFold(n, name) {
+			return fmt.Errorf("%q is a built-in preset", n)
+		}
+	}
+	return nil
+}
+
+// customEQName returns the custom preset name matching name, ignoring case.
+func (a *App) customEQName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, n := range a.eqCustomNames {
+		if strings.EqualFold(strings.TrimSpace(n), name) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// uniqueEQPresetName suggests "name (2)", "name (3)", … until one is free.
+func (a *App) uniqueEQPresetName(name string) string {
+	for i := 2; ; i++ {
+		c := fmt.Sprintf("%s (%d)", name, i)
+		if !a.eqPresetNameTaken(c) {
+			return c
+		}
+	}
+}
+
+// fitPresetBands remaps an imported preset onto bandsHz, interpolating by
+// frequency when it was saved for other bands.
+func fitPresetBands(data config.EQPresetData, bandsHz []float64) config.EQPresetData {
+	p := eqmodel.EQPreset{
+		Name:   data.Name,
+		Preamp: float64(data.Preamp),
+		Gains:  eqmodel.RemapGains(float32s(data.Bands), float32s(data.Freqs), bandsHz),
+	}
+	return presetToConfigData(p, bandsHz)
 }
 
 // applySelectedPreset updates sliders, buttons, and the VLC equalizer when a
@@ -493,7 +1227,8 @@ func (a *App) applySelectedPreset(name string) {
 	}
 	output := eqmodel.EQPreset{Name: cleanName, Gains: make([]float64, bands)}
 	if preset, ok := eqmodel.FindPresetByName(cleanName); ok && !strings.EqualFold(cleanName, EQNameManual) {
-		output = ensurePresetLength(preset, bands)
+		output = preset
+		output.Gains = a.eq.fitGains(preset.Gains, eqmodel.ReferenceBandsHz)
 	} else {
 		switch {
 		case strings.EqualFold(cleanName, EQNameFlat):
@@ -511,11 +1246,11 @@ func (a *App) applySelectedPreset(name string) {
 		}():
 			for k, v := range a.eqCustomMap {
 				if strings.EqualFold(strings.TrimSpace(k), cleanName) {
-					p := eqmodel.EQPreset{Name: cleanName, Preamp: float64(v.Preamp), Gains: make([]float64, bands)}
-					for i := 0; i < len(p.Gains) && i < len(v.Bands); i++ {
-						p.Gains[i] = float64(v.Bands[i])
+					output = eqmodel.EQPreset{
+						Name:   cleanName,
+						Preamp: float64(v.Preamp),
+						Gains:  a.eq.resolveCurve(k, a.eqCustomMap).gains,
 					}
-					output = p
 					break
 				}
 			}
@@ -533,15 +1268,17 @@ func (a *App) applySelectedPreset(name string) {
 		}
 	}
 	a.eqModel.Current = output
+	a.eqManual = strings.EqualFold(cleanName, EQNameManual)
 	eqmodel.ApplyPresetToSliders(output, a.eqSliderValues)
 	a.applySliderValuesToWidgets()
 	a.updateEQButtonsForSelection(cleanName)
+	a.showEQDrawerSelection(cleanName)
 
 	idx := a.currentPresetIndex()
 	if idx >= 0 && idx < len(a.config.Presets) {
 		a.config.Presets[idx].EQ = cleanName
 		_ = a.config.Save()
-		if sel := a.eqPresetSelects[idx]; sel != nil {
+		if sel := a.presetEQSelect(idx); sel != nil {
 			a.silentUpdating = true
 			sel.SetSelected(cleanName)
 			a.silentUpdating = false
@@ -551,7 +1288,7 @@ func (a *App) applySelectedPreset(name string) {
 
 // refreshEQPresetOptions rebuilds dropdown options after custom preset edits.
 func (a *App) refreshEQPresetOptions(selected string) {
-	drawerOpts := a.eq.PresetNamesWithFlat(a.eqCustomNames)
+	drawerOpts := a.drawerEQOptions()
 	if a.eqDrawerPreset != nil {
 		a.eqDrawerPreset.Options = drawerOpts
 		a.eqDrawerPreset.Refresh()
@@ -590,6 +1327,14 @@ func (a *App) updateEQSliderState(index int, value float64) {
 		a.eqModel.Current.Gains = gains
 	}
 	a.eqModel.Current.Gains[index-1] = value
+	a.refreshEQCurve()
+}
+
+// refreshEQCurve redraws the drawer's response sketch from the band sliders.
+func (a *App) refreshEQCurve() {
+	if a.eqCurve != nil && len(a.eqSliderValues) > 1 {
+		a.eqCurve.SetGains(a.eqSliderValues[1:])
+	}
 }
 
 // applySliderValuesToWidgets pushes stored eqModel values back into the slider
@@ -605,7 +1350,14 @@ func (a *App) applySliderValuesToWidgets() {
 			slider.SetValue(a.eqSliderValues[i+1])
 		}
 	}
+	if len(a.eqToneSliders) == len(eqToneLabels) {
+		t := eqmodel.ToneFromGains(a.eqSliderValues[1:], a.eq.bandsHz)
+		for i, v := range []float64{t.Bass, t.Mid, t.Treble} {
+			a.eqToneSliders[i].SetValue(v)
+		}
+	}
 	a.eqSlidersSilent = false
+	a.refreshEQCurve()
 }
 
 // updateEQButtonsForSelection toggles Save/Delete buttons depending on whether
@@ -622,11 +1374,19 @@ func (a *App) updateEQButtonsForSelection(name string) {
 				}
 			}
 		}
-		if ok {
+		// a pending undo keeps the button as Undo, whatever is selected
+		if ok || a.eqUndo != nil {
 			a.eqDeleteButton.Enable()
 		} else {
 			a.eqDeleteButton.Disable()
 		}
+		if a.eqExportButton != nil {
+			if ok {
+				a.eqExportButton.Enable()
+			} else {
+				a.eqExportButton.Disable()
+			}
+		}
 	}
 	if a.eqSaveButton != nil {
 		if strings.EqualFold(target, EQNameManual) {
@@ -637,8 +1397,9 @@ func (a *App) updateEQButtonsForSelection(name string) {
 	}
 }
 
-// presetToConfigData converts an eqmodel preset into the config storage shape.
-func presetToConfigData(p eqmodel.EQPreset) config.EQPresetData {
+// presetToConfigData converts an eqmodel preset into the config storage shape,
+// recording bandsHz as the frequencies the gains belong to.
+func presetToConfigData(p eqmodel.EQPreset, bandsHz []float64) config.EQPresetData {
 	data := config.EQPresetData{
 		Name:   p.Name,
 		Preamp: float32(p.Preamp),
@@ -647,22 +1408,22 @@ func presetToConfigData(p eqmodel.EQPreset) config.EQPresetData {
 	for i := range p.Gains {
 		data.Bands[i] = float32(p.Gains[i])
 	}
+	if len(bandsHz) == len(p.Gains) {
+		data.Freqs = make([]float32, len(bandsHz))
+		for i, f := range bandsHz {
+			data.Freqs[i] = float32(f)
+		}
+	}
 	return data
 }
 
-// ensurePresetLength resizes gain arrays so they match the number of VLC bands.
-func ensurePresetLength(p eqmodel.EQPreset, bands int) eqmodel.EQPreset {
-	if bands <= 0 {
-		p.Gains = []float64{}
-		return p
-	}
-	if len(p.Gains) == bands {
-		return p
+// float32s widens stored config values for eqmodel math.
+func float32s(in []float32) []float64 {
+	out := make([]float64, len(in))
+	for i, v := range in {
+		out[i] = float64(v)
 	}
-	gains := make([]float64, bands)
-	copy(gains, p.Gains)
-	p.Gains = gains
-	return p
+	return out
 }
 
 // hzLabel formats frequency like 32 64 125 250 500 1K 2K 4K 8K 16KHz
@@ -678,62 +1439,69 @@ func hzLabel(f float64) string {
 }
 
 const (
-	dbScaleColumnWidth     float32 = 56
-	dbScaleTopLabelText            = "+15 dB"
-	dbScaleMidLabelText            = "0 dB"
-	dbScaleBottomLabelText         = "-15 dB"
+	dbScaleColumnWidth float32 = 56
+	// the scale column is centred against the sliders and the frequency
+	// labels under them, so label positions are pulled up by dbScaleLift and
+	// spread over the band height less dbScaleInset
+	dbScaleLift  float32 = 7
+	dbScaleInset float32 = 21
 )
 
+// dbMark is one label of the dB scale: its text and where it sits, from 0 at
+// the top of the sliders to 1 at the bottom.
+type dbMark struct {
+	text string
+	frac float32
+}
+
+// dbScaleMarks labels the top, the bottom and the 0 dB line of a slider
+// range, or its midpoint when 0 dB is not inside it.
+func dbScaleMarks(min, max float64) []dbMark {
+	if max <= min {
+		return []dbMark{{dbText(max), 0.5}}
+	}
+	mid := 0.0
+	if mid <= min || mid >= max {
+		mid = (min + max) / 2
+	}
+	return []dbMark{
+		{dbText(max), 0},
+		{dbText(mid), float32((max - mid) / (max - min))},
+		{dbText(min), 1},
+	}
+}
+
+// dbText formats a scale value as "+20 dB", "0 dB" or "-7.5 dB".
+func dbText(v float64) string {
+	if v > 0 {
+		return fmt.Sprintf("+%g dB", v)
+	}
+	return fmt.Sprintf("%g dB", v)
+}
+
 // centerXInColumn computes the x-offset needed to center a label in a column.
 func centerXInColumn(columnWidth float32, label *widget.Label) float32 {
 	labelSize := label.MinSize()
 	return (columnWidth - labelSize.Width) / 2
 }
 
-// nonNegativePosition clamps coordinates to stay within the column bounds.
-func nonNegativePosition(position float32) float32 {
-	if position < 0 {
-		return 0
-	}
-	return position
-}
-
 // makeDBScaleColumnFixed renders a dB scale column of fixed visual height so it
-// aligns with the vertical band sliders. Labels are placed proportionally:
-// +15 dB at the top edge, 0 dB at mid-height (aligned with slider knob row),
-// -15 dB at the bottom edge. The column width is compact.
-func makeDBScaleColumnFixed(height float32) fyne.CanvasObject {
-	top := widget.NewLabel(dbScaleTopLabelText)
-	mid := widget.NewLabel(dbScaleMidLabelText)
-	bot := widget.NewLabel(dbScaleBottomLabelText)
-
+// aligns with the vertical band sliders, labelled from the sliders' actual
+// range min..max: the top and bottom values at the ends and 0 dB at its true
+// height, which is off-centre when the range is not symmetric.
+func makeDBScaleColumnFixed(height float32, min, max float64) fyne.CanvasObject {
 	// place inside a without-layout container so we can position labels manually
 	bg := canvas.NewRectangle(color.NRGBA{0, 0, 0, 0})
 	bg.SetMinSize(fyne.NewSize(dbScaleColumnWidth, height))
-	lay := container.NewWithoutLayout(bg, top, mid, bot)
-
-	// compute positions centered horizontally; use each label's min size
-	topSize := top.MinSize()
-	midSize := mid.MinSize()
-	botSize := bot.MinSize()
-
-	xTop := centerXInColumn(dbScaleColumnWidth, top)
-	xMid := centerXInColumn(dbScaleColumnWidth, mid)
-	xBot := centerXInColumn(dbScaleColumnWidth, bot)
-
-	// vertical coords and corrections for label height
-	yTop := float32(0) - 25
-	yMid := nonNegativePosition(height/2 - midSize.Height/2 - 20)
-	yBot := nonNegativePosition(height - botSize.Height - 10)
-
-	top.Move(fyne.NewPos(xTop, yTop))
-	top.Resize(topSize)
-
-	mid.Move(fyne.NewPos(xMid, yMid))
-	mid.Resize(midSize)
-
-	bot.Move(fyne.NewPos(xBot, yBot))
-	bot.Resize(botSize)
+	lay := container.NewWithoutLayout(bg)
+	for _, m := range dbScaleMarks(min, max) {
+		lbl := widget.NewLabel(m.text)
+		size := lbl.MinSize()
+		y := m.frac*(height-dbScaleInset) - size.Height/2 - dbScaleLift
+		lbl.Move(fyne.NewPos(centerXInColumn(dbScaleColumnWidth, lbl), y))
+		lbl.Resize(size)
+		lay.Add(lbl)
+	}
 
 	// Wrap into a fixed-size grid cell so surrounding layout keeps our height
 	wrap := container.New(layout.NewGridWrapLayout(fyne.NewSize(dbScaleColumnWidth, height)), lay)
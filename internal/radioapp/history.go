@@ -0,0 +1,122 @@
+package radioapp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	config "github.com/edward-ap/miniradio/internal/config"
+	playerpkg "github.com/edward-ap/miniradio/internal/player"
+)
+
+// BuildHistoryDrawer lists recently played titles, newest first, each with a
+// button that copies it to the clipboard.
+func BuildHistoryDrawer(a *App) (fyne.CanvasObject, float32) {
+	var entries []playerpkg.TrackEntry
+	if a.player != nil {
+		entries = a.player.History()
+	}
+
+	var body fyne.CanvasObject
+	if len(entries) == 0 {
+		body = container.NewCenter(widget.NewLabel("No titles yet. Played tracks will appear here."))
+	} else {
+		list := widget.NewList(
+			func() int { return len(entries) },
+			func() fyne.CanvasObject {
+				when := widget.NewLabel("00:00")
+				title := widget.NewLabel("")
+				title.Truncation = fyne.TextTruncateEllipsis
+				copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), nil)
+				copyBtn.Importance = widget.LowImportance
+				return container.NewBorder(nil, nil, when, copyBtn, title)
+			},
+			func(id widget.ListItemID, obj fyne.CanvasObject) {
+				if id < 0 || id >= len(entries) {
+					return
+				}
+				e := entries[id]
+				row := obj.(*fyne.Container)
+				text := e.Title
+				if e.Station != "" {
+					text += " — " + e.Station
+				}
+				row.Objects[0].(*widget.Label).SetText(text)
+				row.Objects[1].(*widget.Label).SetText(e.At.Format("15:04"))
+				row.Objects[2].(*widget.Button).OnTapped = func() {
+					defer a.ensureShortcutFocus()
+					a.w.Clipboard().SetContent(e.Title)
+					a.UpdateTicker("Copied: " + e.Title)
+				}
+			},
+		)
+		list.OnSelected = func(widget.ListItemID) {
+			list.UnselectAll()
+			a.ensureShortcutFocus()
+		}
+		body = list
+	}
+
+	clear := widget.NewButton("Clear", func() {
+		defer a.ensureShortcutFocus()
+		if a.player != nil {
+			a.player.ClearHistory()
+		}
+		a.saveHistory()
+		// rebuild the drawer so the emptied list shows
+		a.setDrawerTarget("")
+		a.setDrawerTarget("history")
+	})
+	clear.Importance = widget.LowImportance
+	footer := container.NewHBox(layout.NewSpacer(), clear)
+
+	bg := a.newDrawerBackground()
+	content := container.NewMax(bg, container.NewBorder(nil, footer, nil, nil, body))
+	return content, 220
+}
+
+// loadHistory seeds the player with history.json when SaveHistory is on.
+func (a *App) loadHistory() {
+	if a.player == nil || a.config == nil || !a.config.SaveHistory {
+		return
+	}
+	path, err := config.HistoryPath()
+	if err != nil {
+		return
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var entries []playerpkg.TrackEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return
+	}
+	a.player.RestoreHistory(entries)
+}
+
+// saveHistory writes the player's history to history.json when SaveHistory
+// is on.
+func (a *App) saveHistory() {
+	if a.player == nil || a.config == nil || !a.config.SaveHistory {
+		return
+	}
+	path, err := config.HistoryPath()
+	if err != nil {
+		return
+	}
+	b, err := json.MarshalIndent(a.player.History(), "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,131 @@
+package radioapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edward-ap/miniradio/internal/config"
+	playerpkg "github.com/edward-ap/miniradio/internal/player"
+)
+
+// previewTickerPrefix marks ticker text that comes from a preview, so it is
+// never mistaken for what is playing.
+const previewTickerPrefix = "Preview (not playing) · "
+
+// previewPreset shows preset idx's now-playing titles on the ticker without
+// streaming its audio, to help decide whether to tune in. Play then starts the
+// previewed station; previewing it again, or another station, ends or moves
+// the preview.
+func (a *App) previewPreset(idx int) {
+	defer a.ensureShortcutFocus()
+	if a.player == nil || idx < 0 || idx >= len(a.config.Presets) {
+		return
+	}
+	if a.player.IsPreviewing() && a.previewIdx == idx {
+		a.stopPreview()
+		a.UpdateTicker("Preview ended")
+		return
+	}
+	p := a.config.Presets[idx]
+	stream := strings.TrimSpace(p.URL)
+	if !isStreamURL(stream) {
+		a.ShowToast("Enter an http(s) stream URL first")
+		return
+	}
+	if a.player.IsPlaying() {
+		a.ShowToast("Stop playback to preview another station")
+		return
+	}
+	metaType, metaURL := p.MetadataHint()
+	a.player.SetMetadataHint(metaType, metaURL, func(t, u string) {
+		a.handleMetadataDiscovered(idx, t, u)
+	})
+	a.applyRequestHeaders(idx)
+	a.applyTitleRules(idx)
+	a.resolveThen(stream, func() { a.startPreview(idx, stream) })
+}
+
+// startPreview starts previewing preset idx at stream once resolveThen has
+// looked it up.
+func (a *App) startPreview(idx int, stream string) {
+	defer a.ensureShortcutFocus()
+	if idx >= len(a.config.Presets) {
+		return
+	}
+	p := a.config.Presets[idx]
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := a.player.Preview(ctx, stream); err != nil {
+		if errors.Is(err, playerpkg.ErrPreviewWhilePlaying) {
+			a.ShowToast("Stop playback to preview another station")
+			return
+		}
+		a.ShowToast("Preview failed: " + err.Error())
+		return
+	}
+	a.previewIdx = idx
+	a.UpdateTicker(previewTickerText(p, ""))
+}
+
+// stopPreview ends a running preview and leaves the ticker to the caller.
+func (a *App) stopPreview() {
+	if a.player != nil {
+		a.player.StopPreview()
+	}
+}
+
+// showPreviewTitle puts a title reported by the preview on the ticker. It is
+// not handed to the OS media overlay, which shows only what plays.
+func (a *App) showPreviewTitle(title string) {
+	if a.ticker == nil || !a.player.IsPreviewing() || a.previewIdx < 0 || a.previewIdx >= len(a.config.Presets) {
+		return
+	}
+	a.ticker.SetText(previewTickerText(a.config.Presets[a.previewIdx], title))
+}
+
+// handleMetadataUnavailable ends a rescan that found nothing, or else shows
+// the neutral no-titles text.
+func (a *App) handleMetadataUnavailable() {
+	if a.rescanning {
+		a.rescanning = false
+		a.UpdateTicker("Rescan found no metadata")
+		return
+	}
+	a.showMetadataUnavailable()
+}
+
+// showMetadataUnavailable replaces "Streaming…" or a preview's "waiting for a
+// title" once the player gives up looking for the station's titles.
+func (a *App) showMetadataUnavailable() {
+	if a.ticker == nil || a.player == nil || a.player.CurrentTitle() != "" {
+		return
+	}
+	if a.player.IsPreviewing() {
+		if a.previewIdx >= 0 && a.previewIdx < len(a.config.Presets) {
+			p := a.config.Presets[a.previewIdx]
+			a.ticker.SetText(previewTickerPrefix + nonEmpty(strings.TrimSpace(p.Name), strings.TrimSpace(p.URL)) + " – no track info")
+		}
+		return
+	}
+	if !a.player.IsPlaying() {
+		return
+	}
+	name, _, _, _, _ := a.player.StationDetails()
+	if idx := a.currentPresetIndex(); name == "" && idx >= 0 && idx < len(a.config.Presets) {
+		name = strings.TrimSpace(a.config.Presets[idx].Name)
+	}
+	a.ticker.SetText(nonEmpty(name, "Streaming") + " · no track info")
+}
+
+// previewTickerText is the ticker text for a preview of p that has reached
+// title, or is still waiting for one when title is "".
+func previewTickerText(p config.Preset, title string) string {
+	name := nonEmpty(strings.TrimSpace(p.Name), strings.TrimSpace(p.URL))
+	if title = strings.TrimSpace(title); title == "" {
+		return fmt.Sprintf("%s%s – waiting for a title…", previewTickerPrefix, name)
+	}
+	return fmt.Sprintf("%s%s: %s", previewTickerPrefix, name, title)
+}
@@ -0,0 +1,238 @@
+package radioapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/edward-ap/miniradio/internal/config"
+	ui "github.com/edward-ap/miniradio/internal/ui"
+)
+
+// remoteMainTimeout bounds how long a request waits for the UI thread.
+const remoteMainTimeout = 5 * time.Second
+
+// remoteStatus is the JSON body of GET /status.
+type remoteStatus struct {
+	CurrentURL string `json:"currentUrl"`
+	IsPlaying  bool   `json:"isPlaying"`
+	Volume     int    `json:"volume"`
+	IsMuted    bool   `json:"isMuted"`
+	CurrentEQ  string `json:"currentEq"`
+	Title      string `json:"title"`
+	// Album and ArtworkURL describe the track when the stream or a cover
+	// art lookup provided them.
+	Album      string `json:"album,omitempty"`
+	ArtworkURL string `json:"artworkUrl,omitempty"`
+}
+
+// remoteAddr is the listen address for the control API.
+func remoteAddr(cfg *config.Config) string {
+	host := strings.TrimSpace(cfg.RemoteControlHost)
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := cfg.RemoteControlPort
+	if port == 0 {
+		port = config.DefaultRemoteControlPort
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// startRemoteControl serves the HTTP control API when enabled in config.
+// Every command runs through the same App methods as the UI, on the UI thread.
+func (a *App) startRemoteControl() {
+	if !a.config.RemoteControl {
+		return
+	}
+	addr := remoteAddr(a.config)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Printf("remote control: listen on %s: %v", addr, err)
+		return
+	}
+	a.remote = &http.Server{Handler: remoteGuard(a.remoteMux(), a.config.RemoteControlHost), ReadHeaderTimeout: 5 * time.Second}
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("remote control: %v", err)
+		}
+	}(a.remote)
+	log.Printf("remote control listening on %s", addr)
+}
+
+// stopRemoteControl shuts the control API down, if it was started.
+func (a *App) stopRemoteControl() {
+	if a.remote == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = a.remote.Shutdown(ctx)
+	a.remote = nil
+}
+
+// remoteGuard refuses requests a web page could have made: any with an
+// Origin header, which browsers add to cross-site POSTs and which scripts
+// never need, and any whose Host is a domain name other than localhost or
+// bindHost, as a DNS-rebinding page would send. IP literals pass, so other
+// devices can still reach a server bound to 0.0.0.0.
+func remoteGuard(next http.Handler, bindHost string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" || !remoteHostAllowed(r.Host, bindHost) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// remoteHostAllowed reports whether a request's Host header names this
+// machine rather than some other site's domain.
+func remoteHostAllowed(host, bindHost string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if net.ParseIP(host) != nil || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	bindHost = strings.TrimSpace(bindHost)
+	return bindHost != "" && strings.EqualFold(host, bindHost)
+}
+
+func (a *App) remoteMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /play", func(w http.ResponseWriter, r *http.Request) {
+		a.remoteDo(w, func() error {
+			if !a.player.IsPlaying() {
+				a.togglePlay()
+			}
+			return nil
+		})
+	})
+	mux.HandleFunc("POST /stop", func(w http.ResponseWriter, r *http.Request) {
+		a.remoteDo(w, func() error {
+			if a.player.IsPlaying() {
+				a.togglePlay()
+			}
+			a.cancelPendingPlay()
+			return nil
+		})
+	})
+	mux.HandleFunc("POST /volume", func(w http.ResponseWriter, r *http.Request) {
+		level, err := strconv.Atoi(r.URL.Query().Get("level"))
+		if err != nil || level < 0 || level > 100 {
+			http.Error(w, "level must be an integer between 0 and 100", http.StatusBadRequest)
+			return
+		}
+		a.remoteDo(w, func() error {
+			a.setVolume(level)
+			return nil
+		})
+	})
+	mux.HandleFunc("POST /preset", func(w http.ResponseWriter, r *http.Request) {
+		i, err := strconv.Atoi(r.URL.Query().Get("i"))
+		if err != nil {
+			http.Error(w, "i must be a 1-based preset number", http.StatusBadRequest)
+			return
+		}
+		a.remoteDo(w, func() error {
+			if err := (StartOptions{Preset: i}).validate(a.config); err != nil {
+				return err
+			}
+			a.activatePreset(i - 1)
+			return nil
+		})
+	})
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		var st remoteStatus
+		if !a.onMainWait(func() { st = a.remoteStatus() }) {
+			http.Error(w, "ui busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(st)
+	})
+	return mux
+}
+
+// remoteDo runs fn on the UI thread and answers with the resulting status,
+// or with 400 when fn rejects the request.
+func (a *App) remoteDo(w http.ResponseWriter, fn func() error) {
+	var (
+		err error
+		st  remoteStatus
+	)
+	if !a.onMainWait(func() {
+		if err = fn(); err == nil {
+			st = a.remoteStatus()
+		}
+	}) {
+		http.Error(w, "ui busy", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(st)
+}
+
+// onMainWait runs fn via ui.CallOnMain and waits for it to finish. It reports
+// false when the UI thread did not get to it within remoteMainTimeout.
+func (a *App) onMainWait(fn func()) bool {
+	done := make(chan struct{})
+	ui.CallOnMain(func() {
+		defer close(done)
+		fn()
+	})
+	select {
+	case <-done:
+		return true
+	case <-time.After(remoteMainTimeout):
+		return false
+	}
+}
+
+// remoteStatus refreshes PlayerState from the live player and config and
+// returns it with the current title. Must run on the UI thread.
+func (a *App) remoteStatus() remoteStatus {
+	a.playerState.CurrentURL = a.config.CurrentURL
+	a.playerState.IsPlaying = a.player.IsPlaying()
+	a.playerState.Volume = a.effectiveVolume()
+	a.playerState.IsMuted = a.config.Muted
+	return remoteStatus{
+		CurrentURL: a.playerState.CurrentURL,
+		IsPlaying:  a.playerState.IsPlaying,
+		Volume:     a.playerState.Volume,
+		IsMuted:    a.playerState.IsMuted,
+		CurrentEQ:  a.playerState.CurrentEQ,
+		Title:      a.player.CurrentTitle(),
+		Album:      a.player.CurrentAlbum(),
+		ArtworkURL: a.player.CurrentArtwork(),
+	}
+}
+
+// setVolume moves the player, slider, and stored level to v, unmuting first
+// like a slider drag.
+func (a *App) setVolume(v int) {
+	a.ensureVolumeUnmuted()
+	if a.player != nil {
+		_ = a.player.SetVolume(v)
+	}
+	a.storeVolume(v)
+	a.playerState.Volume = v
+	if a.volSlider != nil {
+		a.volSlider.Value = float64(v)
+		a.volSlider.Refresh()
+	}
+	a.updateVolumeIcon()
+	_ = a.config.Save()
+}
@@ -1,47 +1,448 @@
 package radioapp
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"image/color"
+	"html"
+	"math"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	config "github.com/edward-ap/miniradio/internal/config"
+	metadata "github.com/edward-ap/miniradio/internal/metadata"
+	playerpkg "github.com/edward-ap/miniradio/internal/player"
+	ui "github.com/edward-ap/miniradio/internal/ui"
 )
 
 // BuildSettingsDrawer constructs the preset editor drawer, including station
 // selection, URL entries, and EQ dropdowns. The returned CanvasObject is sized
-// for two columns of presets, plus the preferred height.
+// for two columns of presets, plus the preferred height. With more than ten
+// presets the columns scroll inside the same drawer height.
 func BuildSettingsDrawer(a *App) (fyne.CanvasObject, float32) {
 	a.resetSettingsControls()
 
-	left := a.buildSettingsColumn(0, 4)
-	right := a.buildSettingsColumn(5, 9)
-	grid := container.NewGridWithColumns(2, left, right)
+	n := len(a.config.Presets)
+	half := (n + 1) / 2
+	left := a.buildSettingsColumn(0, half-1)
+	right := a.buildSettingsColumn(half, n-1)
+	var grid fyne.CanvasObject = container.NewGridWithColumns(2, left, right)
+	if n > config.MinPresets {
+		grid = container.NewVScroll(grid)
+	}
+	body := container.NewBorder(nil, a.buildSettingsFooter(), nil, nil, grid)
+
+	bg := a.newDrawerBackground()
+	content := container.NewMax(bg, body)
+	return content, 356
+}
+
+// buildSettingsFooter holds window-level actions below the preset columns.
+func (a *App) buildSettingsFooter() fyne.CanvasObject {
+	reset := widget.NewButton("Reset window", func() {
+		defer a.ensureShortcutFocus()
+		a.resetWindowPlacement()
+	})
+	reset.Importance = widget.LowImportance
+	export := widget.NewButton("Export…", a.exportConfig)
+	export.Importance = widget.LowImportance
+	imp := widget.NewButton("Import…", a.importConfig)
+	imp.Importance = widget.LowImportance
+	resetAll := widget.NewButton("Reset all…", a.confirmResetConfig)
+	resetAll.Importance = widget.LowImportance
+	info := widget.NewLabel(a.streamInfoSummary())
+	info.Truncation = fyne.TextTruncateEllipsis
+	actions := container.NewBorder(nil, nil, container.NewHBox(export, imp, resetAll), container.NewHBox(a.buildThemeSelect(), a.buildSizeModeSelect(), reset), info)
+	normalize := widget.NewCheck("Normalize volume", a.setNormalize)
+	normalize.SetChecked(a.config.Normalize)
+	mono := widget.NewCheck("Mono", a.setMono)
+	mono.SetChecked(a.config.Mono)
+	output := container.NewBorder(nil, nil, widget.NewLabel("Output"), container.NewHBox(mono, normalize), a.buildAudioDeviceSelect())
+	balance := container.NewBorder(nil, nil, widget.NewLabel("Balance  L"), widget.NewLabel("R"), a.buildBalanceSlider())
+	return container.NewVBox(output, balance, actions)
+}
+
+// sizeModeOptions are the SizeMode choices in the order the Select lists them.
+var sizeModeOptions = []struct{ label, mode string }{
+	{"Compact", config.SizeCompact},
+	{"Normal", ""},
+	{"Large", config.SizeLarge},
+}
+
+// buildSizeModeSelect picks the strip size. The strip is laid out once at
+// start-up, so a new choice is saved and applies after a restart.
+func (a *App) buildSizeModeSelect() *widget.Select {
+	labels := make([]string, len(sizeModeOptions))
+	for i, o := range sizeModeOptions {
+		labels[i] = o.label
+	}
+	sel := widget.NewSelect(labels, nil)
+	for i, o := range sizeModeOptions {
+		if o.mode == a.config.SizeMode {
+			sel.SetSelectedIndex(i)
+		}
+	}
+	sel.OnChanged = func(string) {
+		defer a.ensureShortcutFocus()
+		i := sel.SelectedIndex()
+		if i < 0 || sizeModeOptions[i].mode == a.config.SizeMode {
+			return
+		}
+		a.config.SizeMode = sizeModeOptions[i].mode
+		_ = a.config.Save()
+		dialog.ShowInformation("Window size", "The new size applies the next time MiniRadio starts.", a.w)
+	}
+	return sel
+}
+
+// balanceDetent is the distance from centre (in slider percent) that snaps
+// the balance back to 0, so centring by hand is easy.
+const balanceDetent = 8
+
+// buildBalanceSlider edits the stereo balance as -100…100 percent. With the
+// stereo_pan filter a new balance reloads the playing stream, so it is
+// applied once the slider has rested for a moment rather than on every step
+// of a drag.
+func (a *App) buildBalanceSlider() *ui.MiniThumbSlider {
+	s := ui.NewMiniThumbSlider(-100, 100)
+	s.OnUnhandledKey = a.handleShortcutKey
+	s.Value = math.Round(a.config.Balance * 100)
+	var applyTimer *time.Timer
+	s.OnChanged = func(v float64) {
+		if snapped := snapBalance(v); snapped != v {
+			s.SetValue(snapped)
+			return
+		}
+		if applyTimer != nil {
+			applyTimer.Stop()
+		}
+		applyTimer = time.AfterFunc(400*time.Millisecond, func() {
+			ui.CallOnMain(func() { a.setBalance(v / 100) })
+		})
+	}
+	return s
+}
 
-	bg := canvas.NewRectangle(color.NRGBA{0x20, 0x20, 0x20, 0xFF})
-	content := container.NewMax(bg, grid)
-	return content, 240
+// snapBalance applies the centre detent to a slider value.
+func snapBalance(v float64) float64 {
+	if math.Abs(v) <= balanceDetent {
+		return 0
+	}
+	return v
+}
+
+// setBalance stores pan and applies it to the player, reloading a playing
+// stream when the pan filter needs it.
+func (a *App) setBalance(pan float64) {
+	if a.config.Balance == pan {
+		return
+	}
+	a.config.Balance = pan
+	_ = a.config.Save()
+	a.applyAudioFilters()
+}
+
+// setNormalize toggles loudness normalization; a playing stream is reloaded
+// to apply it.
+func (a *App) setNormalize(on bool) {
+	defer a.ensureShortcutFocus()
+	if a.config.Normalize == on {
+		return
+	}
+	a.config.Normalize = on
+	_ = a.config.Save()
+	a.applyAudioFilters()
+}
+
+// setMono toggles the mono downmix; like normalization it reloads a playing
+// stream.
+func (a *App) setMono(on bool) {
+	defer a.ensureShortcutFocus()
+	if a.config.Mono == on {
+		return
+	}
+	a.config.Mono = on
+	_ = a.config.Save()
+	a.applyAudioFilters()
+}
+
+// applyAudioFilters hands the configured filters and balance to the player
+// and, when they changed, restarts a playing stream so they take effect. The restart
+// goes through resolveThen like any play request, so the lookup runs off the
+// UI thread and a stop or station switch meanwhile wins.
+func (a *App) applyAudioFilters() {
+	if a.player == nil {
+		return
+	}
+	changed := a.player.SetAudioFilters(audioFilters(a.config))
+	if a.player.SetBalance(a.config.Balance) {
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	if !a.player.IsPlaying() || a.player.IsPreviewing() {
+		return
+	}
+	url := a.config.CurrentURL
+	a.resolveThen(url, func() { a.startStream(url) })
+}
+
+// audioDeviceDefault is the Select label for the system default output.
+const audioDeviceDefault = "System default"
+
+// buildAudioDeviceSelect lists the output devices known right now; the drawer
+// is rebuilt on every open, so newly connected devices show up then. A saved
+// device that is currently missing stays listed so it is not silently lost.
+func (a *App) buildAudioDeviceSelect() *widget.Select {
+	var devices []playerpkg.AudioDevice
+	if a.player != nil {
+		devices, _ = a.player.AudioDevices()
+	}
+	saved := a.config.AudioDevice
+	if saved != "" {
+		found := false
+		for _, d := range devices {
+			if d.ID == saved {
+				found = true
+				break
+			}
+		}
+		if !found {
+			devices = append(devices, playerpkg.AudioDevice{ID: saved, Name: saved + " (unavailable)"})
+		}
+	}
+	options := []string{audioDeviceDefault}
+	ids := []string{""}
+	for _, d := range devices {
+		options = append(options, d.Name)
+		ids = append(ids, d.ID)
+	}
+	sel := widget.NewSelect(options, nil)
+	for i, id := range ids {
+		if id == saved {
+			sel.SetSelectedIndex(i)
+			break
+		}
+	}
+	sel.OnChanged = func(string) {
+		defer a.ensureShortcutFocus()
+		i := sel.SelectedIndex()
+		if i < 0 || i >= len(ids) || ids[i] == a.config.AudioDevice {
+			return
+		}
+		a.config.AudioDevice = ids[i]
+		_ = a.config.Save()
+		if a.player != nil {
+			if err := a.player.SetAudioDevice(ids[i]); err != nil {
+				dialog.ShowError(err, a.w)
+			}
+		}
+	}
+	if a.player == nil {
+		sel.Disable()
+	}
+	return sel
+}
+
+// streamInfoSummary describes the playing stream for the settings footer.
+func (a *App) streamInfoSummary() string {
+	if a.player == nil || !a.player.IsPlaying() {
+		return ""
+	}
+	codec, kbps, rate, channels, ok := a.player.StreamInfo()
+	genre := a.player.Genre()
+	if !ok && genre == "" {
+		return ""
+	}
+	return formatStreamInfo(codec, kbps, rate, channels, genre)
+}
+
+// formatStreamInfo joins the known stream details, e.g.
+// "AAC · 128 kbps · 44.1 kHz · stereo · Jazz". Unknown values are skipped.
+func formatStreamInfo(codec string, kbps, rate, channels int, genre string) string {
+	var parts []string
+	if codec != "" {
+		parts = append(parts, codec)
+	}
+	if kbps > 0 {
+		parts = append(parts, fmt.Sprintf("%d kbps", kbps))
+	}
+	if rate > 0 {
+		parts = append(parts, strings.TrimSuffix(fmt.Sprintf("%.1f", float64(rate)/1000), ".0")+" kHz")
+	}
+	switch {
+	case channels == 1:
+		parts = append(parts, "mono")
+	case channels == 2:
+		parts = append(parts, "stereo")
+	case channels > 2:
+		parts = append(parts, fmt.Sprintf("%d ch", channels))
+	}
+	if genre != "" {
+		parts = append(parts, genre)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// fileDialogSize is the minimum window size needed to show a file dialog; the
+// regular strip plus drawer is far too short for one.
+var fileDialogSize = fyne.NewSize(config.MinWindowWidth, 480)
+
+// showFileDialog grows the window while d is visible and restores the drawer
+// height once it closes.
+func (a *App) showFileDialog(d *dialog.FileDialog) {
+	sz := a.w.Canvas().Size()
+	a.w.Resize(fyne.NewSize(fyne.Max(sz.Width, fileDialogSize.Width), fyne.Max(sz.Height, fileDialogSize.Height)))
+	d.SetOnClosed(func() {
+		a.resizeWindowForDrawer(a.drawerHeight)
+		a.ensureShortcutFocus()
+	})
+	d.Resize(fileDialogSize)
+	d.Show()
+}
+
+// exportConfig writes the whole configuration to a user-chosen JSON file.
+func (a *App) exportConfig() {
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.w)
+			return
+		}
+		if w == nil {
+			return
+		}
+		path := w.URI().Path()
+		_ = w.Close()
+		if err := a.config.ExportTo(path); err != nil {
+			dialog.ShowError(err, a.w)
+		}
+	}, a.w)
+	d.SetFileName("miniradio-config.json")
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	a.showFileDialog(d)
+}
+
+// importConfig replaces presets, EQ presets and preferences with a file
+// exported earlier, keeping this machine's window placement.
+func (a *App) importConfig() {
+	d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.w)
+			return
+		}
+		if r == nil {
+			return
+		}
+		path := r.URI().Path()
+		_ = r.Close()
+		if err := a.config.ImportFrom(path); err != nil {
+			dialog.ShowError(err, a.w)
+			return
+		}
+		a.applyImportedConfig()
+	}, a.w)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	a.showFileDialog(d)
+}
+
+// applyImportedConfig pushes a freshly imported config into the player and UI.
+func (a *App) applyImportedConfig() {
+	_ = a.config.Save()
+	a.applyReplacedConfig()
+	// rebuild the drawer so rows reflect the imported presets
+	a.setDrawerTarget("")
+	a.setDrawerTarget("settings")
+}
+
+// applyReplacedConfig brings the player and strip in line with a config
+// that was replaced wholesale; drawers are left to the caller.
+func (a *App) applyReplacedConfig() {
+	a.rebuildCustomEQIndex()
+	if a.player != nil {
+		a.player.ConfigureMetadata(metadataOptions(a.config))
+		a.player.SetIPVersion(ipVersion(a.config))
+		a.player.SetCoverArtLookup(a.config.CoverArtLookup)
+		a.player.SetFadeDurations(fadeDurations(a.config))
+		a.player.SetHistorySize(a.config.HistorySize)
+		a.player.SetSilenceTimeout(time.Duration(a.config.SilenceTimeoutMs) * time.Millisecond)
+		a.player.SetStaleTitleTimeout(staleTitleTimeout(a.config))
+		_ = a.player.SetMute(a.config.Muted)
+		a.applyAudioFilters()
+	}
+	a.applyPresetVolume()
+	a.applyTickerPrefs()
+	a.applyTheme()
+	a.setWindowTitleForCurrentPreset()
+}
+
+// confirmResetConfig asks before replacing every station, custom EQ preset
+// and preference with the defaults, offering to keep the window where it is.
+func (a *App) confirmResetConfig() {
+	keep := widget.NewCheck("Keep window size and position", nil)
+	keep.SetChecked(true)
+	msg := widget.NewLabel("Replace all stations, custom EQ presets and preferences\nwith the defaults? This cannot be undone; Export… first to keep a copy.")
+	dialog.ShowCustomConfirm("Reset all settings", "Reset", "Cancel", container.NewVBox(msg, keep), func(ok bool) {
+		defer a.ensureShortcutFocus()
+		if ok {
+			a.resetConfig(keep.Checked)
+		}
+	}, a.w)
+}
+
+// resetConfig restores the default config and brings the running app in line
+// with it: playback stops, no preset is active and the drawers are rebuilt,
+// so no restart is needed.
+func (a *App) resetConfig(keepWindow bool) {
+	if a.player != nil && a.player.IsPlaying() {
+		a.togglePlay()
+	}
+	a.cancelPendingPlay()
+	a.stopPreview()
+	a.endEQUndo()
+	a.eqManual = false
+	a.config.ResetDefaults(keepWindow)
+	a.uiState.SelectedStation = a.config.LastPreset
+	a.uiState.SelectedEQPreset = EQNameOff
+	a.playerState.CurrentEQ = EQNameOff
+	if a.eq != nil && a.player != nil {
+		_ = a.eq.ApplyPresetName(a.player, EQNameOff, nil)
+	}
+	a.applyImportedConfig()
+	if !keepWindow {
+		a.resetWindowPlacement()
+	}
+	a.UpdateTicker("Settings reset to defaults")
 }
 
 // resetSettingsControls clears per-row widget references so the drawer can be
 // rebuilt without reusing stale pointers.
 func (a *App) resetSettingsControls() {
-	for i := range a.settingsRadios {
-		a.settingsRadios[i] = nil
-	}
-	for i := range a.eqPresetSelects {
-		a.eqPresetSelects[i] = nil
+	n := len(a.config.Presets)
+	a.settingsRadios = make([]*widget.Check, n)
+	a.eqPresetSelects = make([]*widget.Select, n)
+}
+
+// presetEQSelect returns the Settings EQ dropdown for preset idx, or nil when
+// the drawer has not built one.
+func (a *App) presetEQSelect(idx int) *widget.Select {
+	if idx < 0 || idx >= len(a.eqPresetSelects) {
+		return nil
 	}
+	return a.eqPresetSelects[idx]
 }
 
-// buildSettingsColumn yields one half of the drawer (5 rows) including header.
+// buildSettingsColumn yields one half of the drawer including header.
 func (a *App) buildSettingsColumn(from, to int) fyne.CanvasObject {
 	rows := []fyne.CanvasObject{buildSettingsHeader()}
 	for i := from; i <= to; i++ {
@@ -50,34 +451,79 @@ func (a *App) buildSettingsColumn(from, to int) fyne.CanvasObject {
 	return container.NewVBox(rows...)
 }
 
-// buildSettingsHeader renders the "Key / Stream URL / EQ Preset" header row.
+// settingsNameWidth is the fixed width of the preset name column;
+// settingsStarWidth that of the favorite toggle before it and
+// settingsMoreWidth that of each of the test, preview, rescan and metadata buttons
+// after the EQ select.
+const (
+	settingsNameWidth = 120
+	settingsStarWidth = 32
+	settingsMoreWidth = 32
+)
+
+// buildSettingsHeader renders the "Key / Name / Stream URL / EQ Preset" header row.
 func buildSettingsHeader() fyne.CanvasObject {
 	keyHdr := widget.NewLabelWithStyle("Key", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	nameHdr := widget.NewLabelWithStyle("Name", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
 	urlHdr := widget.NewLabelWithStyle("Stream URL", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
 	eqHdr := widget.NewLabelWithStyle("EQ Preset", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-	left := container.New(layout.NewGridWrapLayout(fyne.NewSize(46, keyHdr.MinSize().Height)), keyHdr)
-	right := container.New(layout.NewGridWrapLayout(fyne.NewSize(100, eqHdr.MinSize().Height)), eqHdr)
+	left := container.NewHBox(
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(46, keyHdr.MinSize().Height)), keyHdr),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsStarWidth, keyHdr.MinSize().Height)), layout.NewSpacer()),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsNameWidth, nameHdr.MinSize().Height)), nameHdr),
+	)
+	right := container.NewHBox(
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(100, eqHdr.MinSize().Height)), eqHdr),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(4*settingsMoreWidth, eqHdr.MinSize().Height)), layout.NewSpacer()),
+	)
 	return container.NewBorder(nil, nil, left, right, urlHdr)
 }
 
-// buildSettingsRow assembles a single preset row with radio, entry, and select.
+// buildSettingsRow assembles a single preset row with radio, name and URL
+// entries, and select.
 func (a *App) buildSettingsRow(i int) fyne.CanvasObject {
 	p := &a.config.Presets[i]
 	radio := a.buildPresetRadio(i)
 
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Station name")
+	nameEntry.SetText(p.Name)
+	var nameTimer *time.Timer
+	nameEntry.OnChanged = func(s string) {
+		if s == p.Name {
+			return
+		}
+		p.Name = s
+		if a.currentPresetIndex() == i {
+			a.setWindowTitleForName(strings.TrimSpace(s))
+		}
+		if nameTimer != nil {
+			nameTimer.Stop()
+		}
+		nameTimer = time.AfterFunc(400*time.Millisecond, func() { _ = a.config.Save() })
+	}
+
 	urlEntry := widget.NewEntry()
 	urlEntry.SetText(p.URL)
+	urlEntry.Validator = streamURLProblem
 	var saveTimer *time.Timer
 	urlEntry.OnChanged = func(s string) {
+		// tidy pastes, not keystrokes: a half-typed host must not grow a scheme
+		if len(s)-len(p.URL) > 1 {
+			if tidy := normalizeStreamURL(s); tidy != s {
+				urlEntry.SetText(tidy)
+				return
+			}
+		}
 		oldTrim := strings.TrimSpace(p.URL)
 		newTrim := strings.TrimSpace(s)
 		if oldTrim != newTrim {
-			p.MetadataType = ""
-			p.MetadataURL = ""
+			p.ForgetMetadata()
 		}
 		p.URL = s
 		if newTrim == "" && strings.TrimSpace(p.Name) != "" {
 			p.Name = ""
+			nameEntry.SetText("")
 			if a.currentPresetIndex() == i {
 				a.setWindowTitleForName("")
 			}
@@ -89,12 +535,351 @@ func (a *App) buildSettingsRow(i int) fyne.CanvasObject {
 	}
 
 	eqSelect := a.buildEQSelect(i, p)
+	star := a.buildFavoriteToggle(p)
 
-	left := container.New(layout.NewGridWrapLayout(fyne.NewSize(46, radio.MinSize().Height)), radio)
-	right := container.New(layout.NewGridWrapLayout(fyne.NewSize(100, eqSelect.MinSize().Height)), eqSelect)
+	left := container.NewHBox(
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(46, radio.MinSize().Height)), radio),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsStarWidth, star.MinSize().Height)), star),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsNameWidth, nameEntry.MinSize().Height)), nameEntry),
+	)
+	test := a.buildTestButton(p)
+	preview := a.buildPreviewButton(i)
+	rescan := a.buildRescanButton(p)
+	more := a.buildMetadataButton(p)
+	right := container.NewHBox(
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(100, eqSelect.MinSize().Height)), eqSelect),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsMoreWidth, test.MinSize().Height)), test),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsMoreWidth, preview.MinSize().Height)), preview),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsMoreWidth, rescan.MinSize().Height)), rescan),
+		container.New(layout.NewGridWrapLayout(fyne.NewSize(settingsMoreWidth, more.MinSize().Height)), more),
+	)
 	return container.NewBorder(nil, nil, left, right, urlEntry)
 }
 
+// normalizeStreamURL tidies a pasted stream address: whitespace, quotes or
+// angle brackets around it and punctuation picked up at the end of the
+// selection go, and a bare host name gets https:// in front.
+func normalizeStreamURL(s string) string {
+	s = strings.TrimLeft(strings.TrimSpace(s), "\"'<")
+	s = strings.TrimSpace(strings.TrimRight(s, "\"'>.,;?&#"))
+	if s == "" || strings.Contains(s, "://") {
+		return s
+	}
+	host := s
+	if i := strings.IndexAny(host, ":/"); i >= 0 {
+		host = host[:i]
+	}
+	if strings.Contains(host, ".") && !strings.ContainsAny(host, " \t") {
+		s = "https://" + s
+	}
+	return s
+}
+
+// streamURLProblem is the settings URL validator. It only warns: whatever is
+// typed is saved, and an empty entry is fine.
+func streamURLProblem(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if !strings.Contains(s, "://") {
+		return errors.New("missing http:// or https://")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return errors.New("not a valid URL")
+	}
+	switch scheme := strings.ToLower(u.Scheme); {
+	case scheme == "http" || scheme == "https":
+	case squeeze(scheme) == "htp":
+		return errors.New("did you mean http://?")
+	case squeeze(scheme) == "htps":
+		return errors.New("did you mean https://?")
+	default:
+		return fmt.Errorf("%s:// is not an http(s) stream", scheme)
+	}
+	if u.Host == "" {
+		return errors.New("missing host name")
+	}
+	return nil
+}
+
+// squeeze collapses runs of the same letter, so "htttps" and "htps" compare
+// alike.
+func squeeze(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i == 0 || r != rune(s[i-1]) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// streamTestTimeout bounds the request behind a row's Test button.
+const streamTestTimeout = 3 * time.Second
+
+// buildTestButton checks whether p's URL answers like a stream.
+func (a *App) buildTestButton(p *config.Preset) *widget.Button {
+	btn := widget.NewButtonWithIcon("", theme.SearchIcon(), func() {
+		a.testStream(p)
+	})
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+// buildPreviewButton shows preset i's titles without playing it; a second
+// tap ends the preview.
+func (a *App) buildPreviewButton(i int) *widget.Button {
+	btn := widget.NewButtonWithIcon("", theme.VisibilityIcon(), func() {
+		a.previewPreset(i)
+	})
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+// testStream sends one request to p's URL, with the station's headers and
+// the metadata client settings, and shows what answered. Playback is never
+// touched, so a URL can be checked before it is played.
+func (a *App) testStream(p *config.Preset) {
+	defer a.ensureShortcutFocus()
+	stream := strings.TrimSpace(p.URL)
+	if !isStreamURL(stream) {
+		a.ShowToast("Enter an http(s) stream URL first")
+		return
+	}
+	opts := metadataOptions(a.config)
+	opts.UserAgent, opts.Referer = presetHeaders(a.config, p)
+	opts.RequireTLS = p.RequireTLS
+	if len(p.CertPins) > 0 {
+		verify, err := metadata.PinSHA256(p.CertPins)
+		if err != nil {
+			dialog.ShowInformation("Stream test", "Certificate pin: "+err.Error(), a.w)
+			return
+		}
+		opts.VerifyConnection = verify
+	}
+	client := metadata.NewHTTPClient(opts)
+	a.ShowToast("Testing stream…")
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), streamTestTimeout)
+		defer cancel()
+		res, err := metadata.Probe(ctx, client, stream)
+		report := streamTestReport(res, err)
+		ui.CallOnMain(func() {
+			dialog.ShowInformation("Stream test", report, a.w)
+		})
+	}()
+}
+
+// streamTestReport describes a probe result, one fact per line.
+func streamTestReport(res metadata.ProbeResult, err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("No answer within %s.", streamTestTimeout)
+	}
+	if err != nil {
+		return "Not reachable: " + err.Error()
+	}
+	lines := []string{fmt.Sprintf("HTTP %d %s", res.Status, http.StatusText(res.Status))}
+	if res.ContentType != "" {
+		lines = append(lines, "Content type: "+res.ContentType)
+	} else {
+		lines = append(lines, "Content type: not given")
+	}
+	if res.Redirected {
+		lines = append(lines, "Redirected to: "+res.FinalURL)
+	} else {
+		lines = append(lines, "No redirect")
+	}
+	if res.MetaInt != "" {
+		lines = append(lines, "ICY metadata: yes (icy-metaint "+res.MetaInt+")")
+	} else {
+		lines = append(lines, "ICY metadata: no")
+	}
+	if res.Station != "" {
+		lines = append(lines, "Station: "+html.UnescapeString(res.Station))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// metadataTypeOptions are the choices of the metadata dialog; "" is Auto,
+// which leaves the source to discovery.
+var metadataTypeOptions = []struct{ label, typ string }{
+	{"Auto", ""},
+	{"ICY", metadata.MetadataTypeICY},
+	{"JSON", metadata.MetadataTypeJSON},
+	{"Shoutcast", metadata.MetadataTypeShoutcast},
+	{"HLS", metadata.MetadataTypeHLS},
+	{"JSON API", metadata.MetadataTypeAPI},
+	{"WebSocket", metadata.MetadataTypeWS},
+	{"SSE", metadata.MetadataTypeSSE},
+}
+
+// usesJSONFields reports whether metadata source typ reads JSON through a
+// preset's MetadataFields.
+func usesJSONFields(typ string) bool {
+	switch typ {
+	case metadata.MetadataTypeAPI, metadata.MetadataTypeWS, metadata.MetadataTypeSSE:
+		return true
+	}
+	return false
+}
+
+// buildRescanButton forgets p's metadata source and discovers it again.
+func (a *App) buildRescanButton(p *config.Preset) *widget.Button {
+	btn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+		a.rescanMetadata(p)
+	})
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+// buildMetadataButton opens the advanced metadata settings of p.
+func (a *App) buildMetadataButton(p *config.Preset) *widget.Button {
+	btn := widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), func() {
+		a.showMetadataDialog(p)
+	})
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+// showMetadataDialog lets the user force where p's now-playing titles come
+// from, or rescan them. Any choice but Auto is marked manual, so discovery no
+// longer replaces it; it takes effect the next time the station starts.
+func (a *App) showMetadataDialog(p *config.Preset) {
+	typeSelect := widget.NewSelect(nil, nil)
+	for _, o := range metadataTypeOptions {
+		typeSelect.Options = append(typeSelect.Options, o.label)
+	}
+	typeSelect.SetSelectedIndex(0)
+	if p.MetadataManual {
+		for i, o := range metadataTypeOptions {
+			if strings.EqualFold(o.typ, strings.TrimSpace(p.MetadataType)) {
+				typeSelect.SetSelectedIndex(i)
+			}
+		}
+	}
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("Metadata URL (blank = stream URL)")
+	if p.MetadataManual {
+		urlEntry.SetText(p.MetadataURL)
+	}
+	urlEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" || isStreamURL(s) {
+			return nil
+		}
+		if i := typeSelect.SelectedIndex(); i >= 0 && metadataTypeOptions[i].typ == metadata.MetadataTypeWS {
+			if isWebSocketURL(s) {
+				return nil
+			}
+			return fmt.Errorf("not a ws(s) or http(s) URL")
+		}
+		return fmt.Errorf("not an http(s) URL")
+	}
+	var fields config.MetadataFields
+	if p.MetadataFields != nil {
+		fields = *p.MetadataFields
+	}
+	titlePath, artistPath := widget.NewEntry(), widget.NewEntry()
+	titlePath.SetPlaceHolder("e.g. data.current.title (blank = title)")
+	artistPath.SetPlaceHolder("e.g. data.current.artist (blank = artist)")
+	titlePath.SetText(fields.Title)
+	artistPath.SetText(fields.Artist)
+	titlePath.Validator, artistPath.Validator = metadata.ValidateJSONPath, metadata.ValidateJSONPath
+	typeSelect.OnChanged = func(string) {
+		if typeSelect.SelectedIndex() == 0 {
+			urlEntry.Disable()
+		} else {
+			urlEntry.Enable()
+		}
+		_ = urlEntry.Validate()
+		if usesJSONFields(metadataTypeOptions[typeSelect.SelectedIndex()].typ) {
+			titlePath.Enable()
+			artistPath.Enable()
+		} else {
+			titlePath.Disable()
+			artistPath.Disable()
+		}
+	}
+	typeSelect.OnChanged(typeSelect.Selected)
+
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		name = "station"
+	}
+	var d dialog.Dialog
+	rescan := widget.NewButtonWithIcon("Rescan now", theme.ViewRefreshIcon(), func() {
+		d.Hide()
+		a.rescanMetadata(p)
+	})
+	form := []*widget.FormItem{
+		widget.NewFormItem("Source", typeSelect),
+		widget.NewFormItem("URL", urlEntry),
+		widget.NewFormItem("Title path", titlePath),
+		widget.NewFormItem("Artist path", artistPath),
+		widget.NewFormItem("", rescan),
+	}
+	d = dialog.NewForm("Metadata for "+name, "Save", "Cancel", form, func(ok bool) {
+		defer a.ensureShortcutFocus()
+		if !ok {
+			return
+		}
+		typ := metadataTypeOptions[typeSelect.SelectedIndex()].typ
+		p.ForgetMetadata()
+		if typ != "" {
+			p.MetadataType, p.MetadataURL, p.MetadataManual = typ, strings.TrimSpace(urlEntry.Text), true
+		}
+		if usesJSONFields(typ) {
+			fields.Title, fields.Artist = strings.TrimSpace(titlePath.Text), strings.TrimSpace(artistPath.Text)
+			p.MetadataFields = nil
+			if fields != (config.MetadataFields{}) {
+				p.MetadataFields = &fields
+			}
+		}
+		_ = a.config.Save()
+	}, a.w)
+	d.Resize(fyne.NewSize(420, d.MinSize().Height))
+	d.Show()
+}
+
+// rescanMetadata forgets the metadata source stored for p and, when p is the
+// station playing, rediscovers it right away without interrupting audio;
+// otherwise discovery runs the next time p plays.
+func (a *App) rescanMetadata(p *config.Preset) {
+	defer a.ensureShortcutFocus()
+	p.ForgetMetadata()
+	_ = a.config.Save()
+	idx := a.currentPresetIndex()
+	if a.player == nil || !a.player.IsPlaying() || idx < 0 || &a.config.Presets[idx] != p {
+		a.ShowToast("Metadata will be rediscovered on next play")
+		return
+	}
+	a.rescanning = true
+	a.player.RescanMetadata()
+	a.UpdateTicker("Rescanning metadata…")
+}
+
+// buildFavoriteToggle stars or unstars p.
+func (a *App) buildFavoriteToggle(p *config.Preset) *widget.Button {
+	btn := widget.NewButtonWithIcon("", starOutlineIcon, nil)
+	btn.Importance = widget.LowImportance
+	show := func() {
+		if p.Favorite {
+			btn.SetIcon(starIcon)
+		} else {
+			btn.SetIcon(starOutlineIcon)
+		}
+	}
+	show()
+	btn.OnTapped = func() {
+		defer a.ensureShortcutFocus()
+		p.Favorite = !p.Favorite
+		show()
+		_ = a.config.Save()
+	}
+	return btn
+}
+
 // buildPresetRadio returns the single-select control used to activate stations.
 func (a *App) buildPresetRadio(i int) *widget.Check {
 	label := settingsPresetLabel(i)
@@ -164,7 +949,7 @@ func settingsPresetLabel(i int) string {
 	case 9:
 		return "0"
 	default:
-		return fmt.Sprintf("%d", i)
+		return fmt.Sprintf("%d", i+1)
 	}
 }
 
@@ -182,11 +967,7 @@ func (a *App) buildEQSelect(idx int, preset *config.Preset) *widget.Select {
 		if a.eq == nil || activeIdx != idx {
 			return
 		}
-		custom := make(map[string]config.EQPresetData)
-		for _, ce := range a.config.CustomEQPresets {
-			custom[strings.TrimSpace(ce.Name)] = ce
-		}
-		_ = a.eq.ApplyPresetName(a.player, s, custom)
+		_ = a.eq.ApplyPresetName(a.player, s, a.stationEQCurves(idx))
 
 		if a.drawerMode == "equalizer" && a.eqDrawerPreset != nil {
 			a.silentUpdating = true
@@ -208,13 +989,16 @@ func (a *App) buildEQSelect(idx int, preset *config.Preset) *widget.Select {
 }
 
 // settingsEQOptions lists Off + Flat + built-ins + custom presets for settings.
+// Without an equalizer (safe mode, or libVLC's equalizer failed) libVLC's own
+// preset names are unknown, so only the bundled ones are offered; a station's
+// choice is plain config and takes effect once the equalizer works.
 func (a *App) settingsEQOptions() []string {
-	if a.eq == nil {
-		return []string{EQNameOff}
-	}
 	custom := make([]string, 0, len(a.config.CustomEQPresets))
 	for _, ce := range a.config.CustomEQPresets {
 		custom = append(custom, ce.Name)
 	}
+	if a.eq == nil {
+		return (&Equalizer{}).PresetNamesForSettings(custom)
+	}
 	return a.eq.PresetNamesForSettings(custom)
 }
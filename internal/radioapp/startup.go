@@ -0,0 +1,114 @@
+package radioapp
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	config "github.com/edward-ap/miniradio/internal/config"
+	ui "github.com/edward-ap/miniradio/internal/ui"
+)
+
+// StartOptions selects a station to play as soon as VLC is ready. The zero
+// value keeps the normal behaviour of waiting for the user.
+type StartOptions struct {
+	// URL overrides the configured stream URL.
+	URL string
+	// Preset is the 1-based preset slot to activate; 0 means none.
+	Preset int
+}
+
+// validate checks the options against the loaded presets so a bad shortcut
+// fails before any window appears.
+func (o StartOptions) validate(cfg *config.Config) error {
+	if strings.TrimSpace(o.URL) != "" && o.Preset != 0 {
+		return fmt.Errorf("-url and -preset cannot be combined")
+	}
+	if o.Preset == 0 {
+		return nil
+	}
+	n := len(cfg.Presets)
+	if o.Preset < 1 || o.Preset > n {
+		return fmt.Errorf("preset %d is out of range: choose a preset between 1 and %d", o.Preset, n)
+	}
+	if strings.TrimSpace(cfg.Presets[o.Preset-1].URL) == "" {
+		return fmt.Errorf("preset %d has no stream URL; set one in Settings first", o.Preset)
+	}
+	return nil
+}
+
+// applyStartURL points the configuration at the -url stream. The remembered
+// preset is kept only when it refers to the same stream, so metadata hints of
+// an unrelated station are not applied.
+func applyStartURL(cfg *config.Config, url string) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return
+	}
+	cfg.CurrentURL = url
+	idx := cfg.LastPreset
+	if idx >= 0 && idx < len(cfg.Presets) && strings.TrimSpace(cfg.Presets[idx].URL) == url {
+		return
+	}
+	cfg.LastPreset = -1
+}
+
+// autoStart runs the startup station once VLC has initialised. It is called
+// from the init goroutine and hops to the UI thread itself.
+func (a *App) autoStart() {
+	opts := a.start
+	switch {
+	case opts.Preset > 0:
+		ui.CallOnMain(func() { a.activatePreset(opts.Preset - 1) })
+	case strings.TrimSpace(opts.URL) != "":
+		ui.CallOnMain(func() {
+			if !a.player.IsPlaying() {
+				a.togglePlay()
+			}
+		})
+	}
+}
+
+// enterSafeMode keeps the app running after libVLC failed to start: playback
+// and the equalizer are switched off, but stations and their EQ presets are
+// plain config and can still be set in Settings.
+func (a *App) enterSafeMode(err error) {
+	a.vlcErr = err
+	a.eqErr = err
+	if a.playBtn != nil {
+		a.playBtn.Disable()
+	}
+	if a.ticker != nil {
+		a.ticker.SetText("Safe mode: VLC did not start, playback is off")
+	}
+}
+
+// buildOnboardingHint is the first-run hint just left of the Settings button.
+// It stays until its close button is tapped or Settings is opened.
+func (a *App) buildOnboardingHint() fyne.CanvasObject {
+	lbl := widget.NewLabel("Try keys 1–3, or add stations →")
+	closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), a.dismissOnboarding)
+	closeBtn.Importance = widget.LowImportance
+	a.onboardingHint = container.NewHBox(closeBtn, lbl)
+	if !a.config.ShowOnboarding {
+		a.onboardingHint.Hide()
+	}
+	return a.onboardingHint
+}
+
+// dismissOnboarding hides the first-run hint and saves that, so it does not
+// come back on the next launch.
+func (a *App) dismissOnboarding() {
+	if !a.config.ShowOnboarding {
+		return
+	}
+	a.config.ShowOnboarding = false
+	_ = a.config.Save()
+	if a.onboardingHint != nil {
+		a.onboardingHint.Hide()
+	}
+}
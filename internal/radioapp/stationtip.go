@@ -0,0 +1,51 @@
+package radioapp
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// showStationTip shows the playing station's details beside the stream
+// indicator ind: name, genre, bitrate and, from Icecast status-json
+// servers, the listener count and public stream URL.
+func (a *App) showStationTip(ind fyne.CanvasObject) {
+	if a.player == nil || !a.player.IsPlaying() {
+		return
+	}
+	text := formatStationDetails(a.player.StationDetails())
+	if text == "" {
+		text = "No station details yet"
+	}
+	d := fyne.CurrentApp().Driver()
+	at := d.AbsolutePositionForObject(ind).
+		Subtract(d.AbsolutePositionForObject(a.stationTip.CanvasObject())).
+		Add(fyne.NewPos(ind.Size().Width, ind.Size().Height/2))
+	a.stationTip.ShowAt(text, at)
+}
+
+// formatStationDetails joins the known station details, e.g.
+// "KEXP · Eclectic · 128 kbps · 42 listeners · https://kexp.example/live".
+// Unknown values are skipped.
+func formatStationDetails(name, genre string, kbps, listeners int, listenURL string) string {
+	var parts []string
+	for _, s := range []string{name, genre} {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if kbps > 0 {
+		parts = append(parts, fmt.Sprintf("%d kbps", kbps))
+	}
+	switch {
+	case listeners == 1:
+		parts = append(parts, "1 listener")
+	case listeners > 1:
+		parts = append(parts, fmt.Sprintf("%d listeners", listeners))
+	}
+	if listenURL != "" {
+		parts = append(parts, listenURL)
+	}
+	return strings.Join(parts, " · ")
+}
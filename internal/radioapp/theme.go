@@ -0,0 +1,161 @@
+package radioapp
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	config "github.com/edward-ap/miniradio/internal/config"
+	ui "github.com/edward-ap/miniradio/internal/ui"
+)
+
+// palette holds the colours painted outside fyne widgets, which the theme
+// does not recolour on its own: the blocks behind the play button and the
+// volume controls, the ticker tint and its flash, drawer backgrounds and the
+// stopped stream indicator.
+type palette struct {
+	strip  color.NRGBA
+	tint   color.NRGBA
+	flash  color.NRGBA
+	drawer color.NRGBA
+	idle   color.NRGBA
+}
+
+var (
+	darkPalette = palette{
+		strip:  color.NRGBA{0x1a, 0x1a, 0x1a, 0xFF},
+		tint:   color.NRGBA{0x00, 0x99, 0xFF, 0x40},
+		flash:  color.NRGBA{0x00, 0xCC, 0xFF, 0x60},
+		drawer: color.NRGBA{0x20, 0x20, 0x20, 0xFF},
+		idle:   color.NRGBA{0x80, 0x80, 0x80, 0xFF},
+	}
+	lightPalette = palette{
+		strip:  color.NRGBA{0xe6, 0xe6, 0xe6, 0xFF},
+		tint:   color.NRGBA{0x00, 0x77, 0xDD, 0x30},
+		flash:  color.NRGBA{0x00, 0x99, 0xFF, 0x50},
+		drawer: color.NRGBA{0xf0, 0xf0, 0xf0, 0xFF},
+		idle:   color.NRGBA{0xa8, 0xa8, 0xa8, 0xFF},
+	}
+)
+
+// themeOptions are the Theme choices in the order the Select lists them.
+var themeOptions = []struct{ label, mode string }{
+	{"Dark", ""},
+	{"Light", config.ThemeLight},
+	{"System", config.ThemeSystem},
+}
+
+// baseTheme is the fyne theme for a Config.Theme value.
+func baseTheme(mode string) fyne.Theme {
+	switch mode {
+	case config.ThemeLight:
+		return theme.LightTheme()
+	case config.ThemeSystem:
+		return theme.DefaultTheme()
+	}
+	return theme.DarkTheme()
+}
+
+// themeVariant is the variant currently shown: forced by the light and dark
+// modes, the OS setting otherwise.
+func (a *App) themeVariant() fyne.ThemeVariant {
+	switch a.config.Theme {
+	case config.ThemeLight:
+		return theme.VariantLight
+	case config.ThemeSystem:
+		return a.fa.Settings().ThemeVariant()
+	}
+	return theme.VariantDark
+}
+
+// colors returns the custom palette for the current variant.
+func (a *App) colors() palette {
+	if a.fa != nil && a.themeVariant() == theme.VariantLight {
+		return lightPalette
+	}
+	return darkPalette
+}
+
+// setTheme switches the theme live and saves it.
+func (a *App) setTheme(mode string) {
+	if a.config.Theme == mode {
+		return
+	}
+	a.config.Theme = mode
+	_ = a.config.Save()
+	a.applyTheme()
+}
+
+// applyTheme installs the configured theme at the size the strip was built
+// with. The settings listener registered by watchTheme repaints the custom
+// colours once fyne has switched.
+func (a *App) applyTheme() {
+	a.fa.Settings().SetTheme(ui.ScaledTheme(baseTheme(a.config.Theme), a.drawerScale()))
+}
+
+// watchTheme repaints the custom colours whenever the settings change, which
+// covers both setTheme and the OS switching variant in "system" mode.
+func (a *App) watchTheme() {
+	ch := make(chan fyne.Settings, 1)
+	a.fa.Settings().AddChangeListener(ch)
+	go func() {
+		for range ch {
+			ui.CallOnMain(a.repaintTheme)
+		}
+	}()
+}
+
+// repaintTheme refills the rectangles that carry palette colours.
+func (a *App) repaintTheme() {
+	p := a.colors()
+	for _, r := range []struct {
+		rect *canvas.Rectangle
+		fill color.NRGBA
+	}{
+		{a.playBg, p.strip},
+		{a.rightBg, p.strip},
+		{a.tickerBg, p.tint},
+		{a.drawerPaint, p.drawer},
+	} {
+		if r.rect == nil {
+			continue
+		}
+		r.rect.FillColor = r.fill
+		r.rect.Refresh()
+	}
+	if a.ind != nil {
+		a.ind.SetIdleColor(p.idle)
+	}
+}
+
+// newDrawerBackground creates the opaque background of a drawer and keeps it
+// for repaintTheme.
+func (a *App) newDrawerBackground() *canvas.Rectangle {
+	a.drawerPaint = canvas.NewRectangle(a.colors().drawer)
+	return a.drawerPaint
+}
+
+// buildThemeSelect picks dark, light or system-following colours; the change
+// applies immediately.
+func (a *App) buildThemeSelect() *widget.Select {
+	labels := make([]string, len(themeOptions))
+	for i, o := range themeOptions {
+		labels[i] = o.label
+	}
+	sel := widget.NewSelect(labels, nil)
+	for i, o := range themeOptions {
+		if o.mode == a.config.Theme {
+			sel.SetSelectedIndex(i)
+		}
+	}
+	sel.OnChanged = func(string) {
+		defer a.ensureShortcutFocus()
+		if i := sel.SelectedIndex(); i >= 0 {
+			a.setTheme(themeOptions[i].mode)
+		}
+	}
+	return sel
+}
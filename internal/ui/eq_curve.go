@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// eqCurveSteps is how many line segments join neighbouring bands.
+const eqCurveSteps = 8
+
+// EQCurve sketches equalizer band gains as a smooth line above the band
+// sliders, with a faint 0 dB line behind it. Band i sits at the centre of the
+// i-th of len(gains) equal columns, so the curve lines up with a slider grid
+// of the same width. It is a picture of the settings, not a computed filter
+// response.
+type EQCurve struct {
+	widget.BaseWidget
+	min, max float64
+	gains    []float64
+}
+
+// NewEQCurve creates a curve for bands gains plotted between min and max dB.
+func NewEQCurve(bands int, min, max float64) *EQCurve {
+	c := &EQCurve{min: min, max: max, gains: make([]float64, bands)}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetGains replaces the plotted gains; extra values are ignored and missing
+// ones read as 0 dB. Call it on the UI thread.
+func (c *EQCurve) SetGains(gains []float64) {
+	for i := range c.gains {
+		c.gains[i] = 0
+		if i < len(gains) {
+			c.gains[i] = gains[i]
+		}
+	}
+	c.Refresh()
+}
+
+// MinSize keeps the curve a low strip.
+func (c *EQCurve) MinSize() fyne.Size { return fyne.NewSize(60, 36) }
+
+// CreateRenderer implements fyne.Widget.
+func (c *EQCurve) CreateRenderer() fyne.WidgetRenderer {
+	r := &eqCurveRenderer{c: c, zero: canvas.NewLine(theme.DisabledColor())}
+	r.objs = []fyne.CanvasObject{r.zero}
+	if n := len(c.gains); n > 1 {
+		r.segs = make([]*canvas.Line, (n-1)*eqCurveSteps)
+		for i := range r.segs {
+			r.segs[i] = canvas.NewLine(theme.PrimaryColor())
+			r.segs[i].StrokeWidth = 1.5
+			r.objs = append(r.objs, r.segs[i])
+		}
+	}
+	return r
+}
+
+type eqCurveRenderer struct {
+	c    *EQCurve
+	zero *canvas.Line
+	segs []*canvas.Line
+	objs []fyne.CanvasObject
+}
+
+func (r *eqCurveRenderer) Layout(sz fyne.Size) {
+	n := len(r.c.gains)
+	if n == 0 {
+		return
+	}
+	colW := sz.Width / float32(n)
+	y := func(db float64) float32 {
+		span := r.c.max - r.c.min
+		if span <= 0 {
+			return sz.Height / 2
+		}
+		db = math.Max(r.c.min, math.Min(r.c.max, db))
+		return float32((r.c.max-db)/span) * sz.Height
+	}
+	r.zero.Position1 = fyne.NewPos(colW/2, y(0))
+	r.zero.Position2 = fyne.NewPos(sz.Width-colW/2, y(0))
+
+	pts := curveSamples(r.c.gains, eqCurveSteps)
+	x := func(k int) float32 { return colW/2 + float32(k)*colW/eqCurveSteps }
+	for i, seg := range r.segs {
+		seg.Position1 = fyne.NewPos(x(i), y(pts[i]))
+		seg.Position2 = fyne.NewPos(x(i+1), y(pts[i+1]))
+	}
+}
+
+func (r *eqCurveRenderer) MinSize() fyne.Size { return r.c.MinSize() }
+
+func (r *eqCurveRenderer) Refresh() {
+	r.zero.StrokeColor = theme.DisabledColor()
+	for _, seg := range r.segs {
+		seg.StrokeColor = theme.PrimaryColor()
+	}
+	r.Layout(r.c.Size())
+	for _, o := range r.objs {
+		canvas.Refresh(o)
+	}
+}
+
+func (r *eqCurveRenderer) Destroy() {}
+
+func (r *eqCurveRenderer) Objects() []fyne.CanvasObject { return r.objs }
+
+// curveSamples runs a Catmull-Rom spline through gains, taken as equally
+// spaced points, and returns steps samples per gap plus the last point:
+// (len(gains)-1)*steps+1 values that pass through every gain.
+func curveSamples(gains []float64, steps int) []float64 {
+	n := len(gains)
+	if n < 2 || steps < 1 {
+		return append([]float64(nil), gains...)
+	}
+	at := func(i int) float64 { return gains[max(0, min(n-1, i))] }
+	out := make([]float64, 0, (n-1)*steps+1)
+	for i := 0; i < n-1; i++ {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+		for s := 0; s < steps; s++ {
+			t := float64(s) / float64(steps)
+			out = append(out, 0.5*(2*p1+
+				(p2-p0)*t+
+				(2*p0-5*p1+4*p2-p3)*t*t+
+				(3*p1-p0-3*p2+p3)*t*t*t))
+		}
+	}
+	return append(out, gains[n-1])
+}
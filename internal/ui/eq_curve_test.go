@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCurveSamples(t *testing.T) {
+	gains := []float64{0, 6, -3, 0}
+	got := curveSamples(gains, 4)
+	if len(got) != 13 {
+		t.Fatalf("got %d samples, want 13", len(got))
+	}
+	for i, g := range gains {
+		if math.Abs(got[i*4]-g) > 1e-9 {
+			t.Errorf("sample %d = %v, want band value %v", i*4, got[i*4], g)
+		}
+	}
+	// a flat curve stays flat between the bands
+	for i, v := range curveSamples([]float64{2, 2, 2}, 5) {
+		if math.Abs(v-2) > 1e-9 {
+			t.Fatalf("flat sample %d = %v", i, v)
+		}
+	}
+	if got := curveSamples([]float64{4}, 8); len(got) != 1 || got[0] != 4 {
+		t.Fatalf("single band = %v", got)
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// HoverArea wraps content and reports when the mouse pointer enters or
+// leaves it. It does not consume taps, so content keeps its own behaviour.
+type HoverArea struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+
+	OnMouseIn  func()
+	OnMouseOut func()
+}
+
+var _ desktop.Hoverable = (*HoverArea)(nil)
+
+// NewHoverArea wraps content with hover callbacks; either may be nil.
+func NewHoverArea(content fyne.CanvasObject, onIn, onOut func()) *HoverArea {
+	h := &HoverArea{content: content, OnMouseIn: onIn, OnMouseOut: onOut}
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+// CreateRenderer implements fyne.Widget.
+func (h *HoverArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(h.content)
+}
+
+// MouseIn implements desktop.Hoverable.
+func (h *HoverArea) MouseIn(*desktop.MouseEvent) {
+	if h.OnMouseIn != nil {
+		h.OnMouseIn()
+	}
+}
+
+// MouseMoved implements desktop.Hoverable.
+func (h *HoverArea) MouseMoved(*desktop.MouseEvent) {}
+
+// MouseOut implements desktop.Hoverable.
+func (h *HoverArea) MouseOut() {
+	if h.OnMouseOut != nil {
+		h.OnMouseOut()
+	}
+}
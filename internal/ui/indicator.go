@@ -3,6 +3,7 @@ package ui
 import (
 	"image/color"
 	"math"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -12,23 +13,48 @@ import (
 	"fyne.io/fyne/v2/layout"
 )
 
+// Indicator defaults: gray when stopped, a full hue cycle every 45 steps of
+// 90ms while streaming.
+var defaultIdleColor = color.NRGBA{0x80, 0x80, 0x80, 0xFF}
+
+const (
+	defaultAnimationInterval = 90 * time.Millisecond
+	hueStep                  = 8
+)
+
 // StreamIndicator is a tiny circular indicator that breathes through green hues
-// while streaming is active.
+// while streaming is active, pulses amber while the stream buffers, and dims
+// while muted.
 type StreamIndicator struct {
-	wrap   *fyne.Container
-	circle *canvas.Circle
-	on     atomic.Bool
-	hue    float64 // 0..360
+	wrap      *fyne.Container
+	circle    *canvas.Circle
+	on        atomic.Bool
+	buffering atomic.Bool
+	muted     atomic.Bool
+
+	// mu guards the look, which may change while the animation runs
+	mu       sync.Mutex
+	idle     color.Color
+	hueLo    float64 // active hue range, degrees
+	hueHi    float64
+	interval time.Duration
+	hue      float64 // current hue within [hueLo, hueHi)
 }
 
 // NewStreamIndicator constructs a StreamIndicator with the given diameter.
 func NewStreamIndicator(diameter float32) *StreamIndicator {
-	c := canvas.NewCircle(color.NRGBA{0x80, 0x80, 0x80, 0xFF}) // gray by default (stopped)
+	c := canvas.NewCircle(defaultIdleColor) // gray by default (stopped)
 	c.StrokeColor = color.NRGBA{0, 0, 0, 0}
 	// Only the circle centered inside a fixed-size holder; no local background here
 	inner := container.New(layout.NewGridWrapLayout(fyne.NewSize(diameter, diameter)), c)
 	wrap := container.NewCenter(inner)
-	return &StreamIndicator{wrap: wrap, circle: c}
+	return &StreamIndicator{
+		wrap:     wrap,
+		circle:   c,
+		idle:     defaultIdleColor,
+		hueHi:    360,
+		interval: defaultAnimationInterval,
+	}
 }
 
 // CanvasObject returns the fyne object suitable for embedding in layouts.
@@ -41,26 +67,89 @@ func (s *StreamIndicator) SetActive(on bool) {
 	if on && !prev {
 		go s.animate()
 	} else if !on {
-		// reset hue and return to dark gray on main thread
-		s.hue = 0
+		s.buffering.Store(false)
+		// reset hue and return to the idle color on main thread
+		s.mu.Lock()
+		s.hue = s.hueLo
+		idle := s.idle
+		s.mu.Unlock()
+		CallOnMain(func() {
+			s.circle.FillColor = idle
+			s.circle.Refresh()
+		})
+	}
+}
+
+// SetBuffering switches the active indicator to the amber pulse, or back to
+// the green breathing once the stream has buffered.
+func (s *StreamIndicator) SetBuffering(on bool) {
+	s.buffering.Store(on)
+}
+
+// SetMuted dims the active indicator while playback is muted.
+func (s *StreamIndicator) SetMuted(on bool) {
+	s.muted.Store(on)
+}
+
+// SetIdleColor sets the color shown while stopped (gray by default).
+func (s *StreamIndicator) SetIdleColor(c color.Color) {
+	s.mu.Lock()
+	s.idle = c
+	s.mu.Unlock()
+	if !s.on.Load() {
 		CallOnMain(func() {
-			s.circle.FillColor = color.NRGBA{0x80, 0x80, 0x80, 0xFF}
+			s.circle.FillColor = c
 			s.circle.Refresh()
 		})
 	}
 }
 
+// SetActiveHueRange limits the hues cycled through while streaming to
+// [from, to) degrees; the default is the whole wheel, 0 to 360. Ranges may
+// wrap past 360, e.g. 330 to 390 for reds. An empty range holds from.
+func (s *StreamIndicator) SetActiveHueRange(from, to float64) {
+	if to < from {
+		to = from
+	}
+	s.mu.Lock()
+	s.hueLo, s.hueHi = from, to
+	s.hue = from
+	s.mu.Unlock()
+}
+
+// SetAnimationInterval sets the time between animation steps (90ms by
+// default); a shorter interval animates faster. It applies from the next
+// time the indicator becomes active.
+func (s *StreamIndicator) SetAnimationInterval(d time.Duration) {
+	if d <= 0 {
+		d = defaultAnimationInterval
+	}
+	s.mu.Lock()
+	s.interval = d
+	s.mu.Unlock()
+}
+
 func (s *StreamIndicator) animate() {
-	t := time.NewTicker(90 * time.Millisecond)
+	s.mu.Lock()
+	interval := s.interval
+	s.mu.Unlock()
+	t := time.NewTicker(interval)
 	defer t.Stop()
 	for s.on.Load() {
 		<-t.C
-		// cycle through greenish hues for a subtle breathing effect
-		s.hue += 8
-		if s.hue >= 360 {
-			s.hue = 0
+		// cycle through the active hues for a subtle breathing effect
+		s.mu.Lock()
+		s.hue = nextHue(s.hue, s.hueLo, s.hueHi)
+		hue := s.hue
+		s.mu.Unlock()
+		col := hsvToNRGBA(math.Mod(math.Mod(hue, 360)+360, 360), 0.65, 0.95)
+		if s.buffering.Load() {
+			// amber, pulsing in brightness
+			col = hsvToNRGBA(38, 0.9, 0.6+0.35*math.Abs(math.Sin(hue*math.Pi/180)))
+		}
+		if s.muted.Load() {
+			col.A = 0x50
 		}
-		col := hsvToNRGBA(s.hue, 0.65, 0.95)
 		CallOnMain(func() {
 			s.circle.FillColor = col
 			s.circle.Refresh()
@@ -68,6 +157,18 @@ func (s *StreamIndicator) animate() {
 	}
 }
 
+// nextHue advances h by one step within [lo, hi), wrapping to lo.
+func nextHue(h, lo, hi float64) float64 {
+	if hi <= lo {
+		return lo
+	}
+	h += hueStep
+	if h >= hi || h < lo {
+		return lo
+	}
+	return h
+}
+
 // hsvToNRGBA converts HSV (0..360, 0..1, 0..1) to color.NRGBA.
 func hsvToNRGBA(h, s, v float64) color.NRGBA {
 	c := v * s
@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+func TestNextHue(t *testing.T) {
+	tests := []struct {
+		h, lo, hi float64
+		want      float64
+	}{
+		{h: 0, lo: 0, hi: 360, want: 8},
+		{h: 352, lo: 0, hi: 360, want: 0},
+		{h: 100, lo: 90, hi: 150, want: 108},
+		{h: 146, lo: 90, hi: 150, want: 90},
+		{h: 10, lo: 90, hi: 150, want: 90}, // range changed under the animation
+		{h: 380, lo: 330, hi: 390, want: 388},
+		{h: 388, lo: 330, hi: 390, want: 330},
+		{h: 120, lo: 120, hi: 120, want: 120},
+	}
+	for _, tt := range tests {
+		if got := nextHue(tt.h, tt.lo, tt.hi); got != tt.want {
+			t.Errorf("nextHue(%v, %v, %v) = %v, want %v", tt.h, tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"image/color"
 	"math"
 
 	"fyne.io/fyne/v2"
@@ -18,6 +19,11 @@ type MiniThumbSlider struct {
 	Step      float64
 	Value     float64
 	OnChanged func(float64)
+	// OnUnhandledKey receives keys the slider does not use while focused, so
+	// window shortcuts keep working.
+	OnUnhandledKey func(*fyne.KeyEvent)
+
+	focused bool
 }
 
 // NewMiniThumbSlider creates a horizontal slider constrained to [min, max].
@@ -33,11 +39,90 @@ func (s *MiniThumbSlider) CreateRenderer() fyne.WidgetRenderer {
 		track: canvas.NewRectangle(theme.ShadowColor()),
 		fill:  canvas.NewRectangle(theme.PrimaryColor()),
 		thumb: canvas.NewCircle(theme.ForegroundColor()),
+		ring:  newFocusRing(),
 	}
-	r.objs = []fyne.CanvasObject{r.track, r.fill, r.thumb}
+	r.objs = []fyne.CanvasObject{r.ring, r.track, r.fill, r.thumb}
 	return r
 }
 
+// FocusGained implements fyne.Focusable and shows the focus ring.
+func (s *MiniThumbSlider) FocusGained() {
+	s.focused = true
+	s.Refresh()
+}
+
+// FocusLost implements fyne.Focusable.
+func (s *MiniThumbSlider) FocusLost() {
+	s.focused = false
+	s.Refresh()
+}
+
+// TypedKey moves the thumb with Left/Right by Step, PageUp/PageDown by a
+// tenth of the range, and Home/End to the ends.
+func (s *MiniThumbSlider) TypedKey(ev *fyne.KeyEvent) {
+	if ev == nil {
+		return
+	}
+	v, ok := sliderKeyValue(ev.Name, s.Value, s.Min, s.Max, s.Step, fyne.KeyRight, fyne.KeyLeft)
+	if !ok {
+		if s.OnUnhandledKey != nil {
+			s.OnUnhandledKey(ev)
+		}
+		return
+	}
+	s.SetValue(v)
+}
+
+// TypedRune implements fyne.Focusable; text input is ignored.
+func (s *MiniThumbSlider) TypedRune(rune) {}
+
+// sliderKeyValue maps a navigation key to the new slider value: up/down keys
+// move by step, PageUp/PageDown by a tenth of the range (at least one step),
+// Home/End jump to min/max. ok is false for keys a slider does not handle.
+func sliderKeyValue(key fyne.KeyName, value, min, max, step float64, up, down fyne.KeyName) (float64, bool) {
+	if step <= 0 {
+		step = 1
+	}
+	page := math.Max(step, math.Round((max-min)/10/step)*step)
+	switch key {
+	case up:
+		return value + step, true
+	case down:
+		return value - step, true
+	case fyne.KeyPageUp:
+		return value + page, true
+	case fyne.KeyPageDown:
+		return value - page, true
+	case fyne.KeyHome:
+		return min, true
+	case fyne.KeyEnd:
+		return max, true
+	}
+	return value, false
+}
+
+// newFocusRing builds the outline drawn around a focused slider.
+func newFocusRing() *canvas.Rectangle {
+	ring := canvas.NewRectangle(color.Transparent)
+	ring.StrokeColor = theme.FocusColor()
+	ring.StrokeWidth = 1
+	ring.CornerRadius = 3
+	ring.Hide()
+	return ring
+}
+
+// layoutFocusRing sizes ring to the widget and shows it while focused.
+func layoutFocusRing(ring *canvas.Rectangle, sz fyne.Size, focused bool) {
+	ring.Move(fyne.NewPos(0, 0))
+	ring.Resize(sz)
+	ring.StrokeColor = theme.FocusColor()
+	if focused {
+		ring.Show()
+	} else {
+		ring.Hide()
+	}
+}
+
 // SetValue sets the slider value and triggers refresh and callback.
 func (s *MiniThumbSlider) SetValue(v float64) {
 	if s.Max <= s.Min {
@@ -135,6 +220,14 @@ type VerticalSlider struct {
 	Step      float64
 	Value     float64
 	OnChanged func(float64)
+	// OnUnhandledKey receives keys the slider does not use while focused, so
+	// window shortcuts keep working.
+	OnUnhandledKey func(*fyne.KeyEvent)
+	// OnDrag is called after each drag step moves the value, and once more
+	// with done set when the drag ends, e.g. to show the value by the thumb.
+	OnDrag func(done bool)
+
+	focused bool
 }
 
 // NewVerticalSlider creates a vertical slider with a default 0.5 step.
@@ -150,11 +243,43 @@ func (s *VerticalSlider) CreateRenderer() fyne.WidgetRenderer {
 		track: canvas.NewRectangle(theme.ShadowColor()),
 		fill:  canvas.NewRectangle(theme.PrimaryColor()),
 		thumb: canvas.NewCircle(theme.ForegroundColor()),
+		ring:  newFocusRing(),
 	}
-	r.objs = []fyne.CanvasObject{r.track, r.fill, r.thumb}
+	r.objs = []fyne.CanvasObject{r.ring, r.track, r.fill, r.thumb}
 	return r
 }
 
+// FocusGained implements fyne.Focusable and shows the focus ring.
+func (s *VerticalSlider) FocusGained() {
+	s.focused = true
+	s.Refresh()
+}
+
+// FocusLost implements fyne.Focusable.
+func (s *VerticalSlider) FocusLost() {
+	s.focused = false
+	s.Refresh()
+}
+
+// TypedKey nudges the value with Up/Down by Step, PageUp/PageDown by a tenth
+// of the range, and Home/End to min/max.
+func (s *VerticalSlider) TypedKey(ev *fyne.KeyEvent) {
+	if ev == nil {
+		return
+	}
+	v, ok := sliderKeyValue(ev.Name, s.Value, s.Min, s.Max, s.Step, fyne.KeyUp, fyne.KeyDown)
+	if !ok {
+		if s.OnUnhandledKey != nil {
+			s.OnUnhandledKey(ev)
+		}
+		return
+	}
+	s.SetValue(v)
+}
+
+// TypedRune implements fyne.Focusable; text input is ignored.
+func (s *VerticalSlider) TypedRune(rune) {}
+
 func (s *VerticalSlider) clampStep(v float64) float64 {
 	if s.Max <= s.Min {
 		return s.Min
@@ -194,13 +319,74 @@ func (s *VerticalSlider) SetValue(v float64) {
 // Dragged updates value based on Y position (top=max, bottom=min).
 func (s *VerticalSlider) Dragged(e *fyne.DragEvent) {
 	s.updateFromPos(e.Position.Y, s.Size().Height)
+	if s.OnDrag != nil {
+		s.OnDrag(false)
+	}
+}
+
+// DragEnd reports the end of a drag to OnDrag.
+func (s *VerticalSlider) DragEnd() {
+	if s.OnDrag != nil {
+		s.OnDrag(true)
+	}
+}
+
+// ThumbPosition is the centre of the thumb for the current value, relative to
+// the slider's top-left corner.
+func (s *VerticalSlider) ThumbPosition() fyne.Position {
+	sz := s.Size()
+	return fyne.NewPos(sz.Width/2, verticalThumbY(s.valueFraction(), sz.Height))
 }
 
-func (s *VerticalSlider) DragEnd() {}
+// valueFraction is where Value sits in the range, 0 at Min and 1 at Max.
+func (s *VerticalSlider) valueFraction() float32 {
+	span := s.Max - s.Min
+	frac := float32(0)
+	if span > 0 {
+		frac = float32((s.Value - s.Min) / span)
+	}
+	if frac < 0 {
+		frac = 0
+	}
+	if frac > 1 {
+		frac = 1
+	}
+	return frac
+}
+
+// verticalThumbY is the thumb centre for frac of a track h tall, kept a thumb
+// radius inside both ends.
+func verticalThumbY(frac, h float32) float32 {
+	thumbR := theme.IconInlineSize() / 4
+	cy := h - h*frac
+	if cy < thumbR {
+		cy = thumbR
+	}
+	if cy > h-thumbR {
+		cy = h - thumbR
+	}
+	return cy
+}
 
 // Tapped moves the thumb to the tapped position.
 func (s *VerticalSlider) Tapped(e *fyne.PointEvent) { s.updateFromPos(e.Position.Y, s.Size().Height) }
 
+// DoubleTapped snaps the slider back to its neutral value.
+func (s *VerticalSlider) DoubleTapped(*fyne.PointEvent) { s.SetValue(s.neutral()) }
+
+// TappedSecondary snaps the slider back to its neutral value, like a double
+// tap.
+func (s *VerticalSlider) TappedSecondary(*fyne.PointEvent) { s.SetValue(s.neutral()) }
+
+// neutral is 0 when the range spans it (flat for dB sliders), otherwise the
+// middle of the range.
+func (s *VerticalSlider) neutral() float64 {
+	if s.Min <= 0 && s.Max >= 0 {
+		return 0
+	}
+	return (s.Min + s.Max) / 2
+}
+
 func (s *VerticalSlider) updateFromPos(py float32, h float32) {
 	if h <= 0 || s.Max <= s.Min {
 		return
@@ -234,10 +420,13 @@ type verticalSliderRenderer struct {
 	track *canvas.Rectangle
 	fill  *canvas.Rectangle
 	thumb *canvas.Circle
+	ring  *canvas.Rectangle
 	objs  []fyne.CanvasObject
 }
 
 func (r *verticalSliderRenderer) Layout(sz fyne.Size) {
+	layoutFocusRing(r.ring, sz, r.s.focused)
+
 	// track centered horizontally
 	trackW := float32(4)
 	x := (sz.Width - trackW) / 2
@@ -245,30 +434,14 @@ func (r *verticalSliderRenderer) Layout(sz fyne.Size) {
 	r.track.Resize(fyne.NewSize(trackW, sz.Height))
 
 	// fill height proportionate to value (from bottom up)
-	span := r.s.Max - r.s.Min
-	frac := float32(0)
-	if span > 0 {
-		frac = float32((r.s.Value - r.s.Min) / span)
-	}
-	if frac < 0 {
-		frac = 0
-	}
-	if frac > 1 {
-		frac = 1
-	}
+	frac := r.s.valueFraction()
 	fillH := sz.Height * frac
 	r.fill.Move(fyne.NewPos(x, sz.Height-fillH))
 	r.fill.Resize(fyne.NewSize(trackW, fillH))
 
 	// small thumb circle
 	thumbR := theme.IconInlineSize() / 4
-	cy := sz.Height - fillH
-	if cy < float32(thumbR) {
-		cy = float32(thumbR)
-	}
-	if cy > sz.Height-float32(thumbR) {
-		cy = sz.Height - float32(thumbR)
-	}
+	cy := verticalThumbY(frac, sz.Height)
 	cx := sz.Width / 2
 	r.thumb.Resize(fyne.NewSize(thumbR*2, thumbR*2))
 	r.thumb.Move(fyne.NewPos(cx-float32(thumbR), cy-float32(thumbR)))
@@ -278,6 +451,7 @@ func (r *verticalSliderRenderer) MinSize() fyne.Size { return r.s.MinSize() }
 
 func (r *verticalSliderRenderer) Refresh() {
 	r.Layout(r.s.Size())
+	canvas.Refresh(r.ring)
 	canvas.Refresh(r.track)
 	canvas.Refresh(r.fill)
 	canvas.Refresh(r.thumb)
@@ -294,10 +468,13 @@ type miniSliderRenderer struct {
 	track *canvas.Rectangle
 	fill  *canvas.Rectangle
 	thumb *canvas.Circle
+	ring  *canvas.Rectangle
 	objs  []fyne.CanvasObject
 }
 
 func (r *miniSliderRenderer) Layout(sz fyne.Size) {
+	layoutFocusRing(r.ring, sz, r.s.focused)
+
 	// track centered vertically
 	trackH := float32(4)
 	y := (sz.Height - trackH) / 2
@@ -338,6 +515,7 @@ func (r *miniSliderRenderer) MinSize() fyne.Size { return r.s.MinSize() }
 
 func (r *miniSliderRenderer) Refresh() {
 	r.Layout(r.s.Size())
+	canvas.Refresh(r.ring)
 	canvas.Refresh(r.track)
 	canvas.Refresh(r.fill)
 	canvas.Refresh(r.thumb)
@@ -3,6 +3,9 @@ package ui
 import (
 	"math"
 	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
 )
 
 func TestNormalizeSliderValueClamp(t *testing.T) {
@@ -50,3 +53,109 @@ func TestNormalizeSliderValueStep(t *testing.T) {
 		})
 	}
 }
+
+func TestSliderKeyValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    fyne.KeyName
+		value  float64
+		want   float64
+		wantOK bool
+	}{
+		{name: "up", key: fyne.KeyUp, value: 0, want: 0.5, wantOK: true},
+		{name: "down", key: fyne.KeyDown, value: 0, want: -0.5, wantOK: true},
+		{name: "page up", key: fyne.KeyPageUp, value: 0, want: 4, wantOK: true},
+		{name: "page down", key: fyne.KeyPageDown, value: 0, want: -4, wantOK: true},
+		{name: "home", key: fyne.KeyHome, value: 3, want: -20, wantOK: true},
+		{name: "end", key: fyne.KeyEnd, value: 3, want: 20, wantOK: true},
+		{name: "other", key: fyne.KeySpace, value: 3, want: 3, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sliderKeyValue(tt.key, tt.value, -20, 20, 0.5, fyne.KeyUp, fyne.KeyDown)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("sliderKeyValue(%s) = %v, %v; want %v, %v", tt.key, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSliderTypedKeyFiresOnce(t *testing.T) {
+	test.NewApp()
+	v := NewVerticalSlider(-20, 20)
+	changes := 0
+	v.OnChanged = func(float64) { changes++ }
+	var forwarded fyne.KeyName
+	v.OnUnhandledKey = func(ev *fyne.KeyEvent) { forwarded = ev.Name }
+
+	v.TypedKey(&fyne.KeyEvent{Name: fyne.KeyUp})
+	if changes != 1 || v.Value != 0.5 {
+		t.Fatalf("after Up: changes=%d value=%v", changes, v.Value)
+	}
+	v.TypedKey(&fyne.KeyEvent{Name: fyne.KeyEnd})
+	v.TypedKey(&fyne.KeyEvent{Name: fyne.KeyEnd}) // already at max: no callback
+	if changes != 2 || v.Value != 20 {
+		t.Fatalf("after End: changes=%d value=%v", changes, v.Value)
+	}
+	v.TypedKey(&fyne.KeyEvent{Name: fyne.KeySpace})
+	if forwarded != fyne.KeySpace || changes != 2 {
+		t.Fatalf("Space forwarded=%q changes=%d", forwarded, changes)
+	}
+
+	m := NewMiniThumbSlider(0, 100)
+	m.Value = 50
+	m.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	m.TypedKey(&fyne.KeyEvent{Name: fyne.KeyPageUp})
+	if m.Value != 59 {
+		t.Fatalf("mini slider value = %v, want 59", m.Value)
+	}
+}
+
+func TestVerticalSliderResetsToNeutral(t *testing.T) {
+	test.NewApp()
+	v := NewVerticalSlider(-20, 20)
+	v.Value = 7.5
+	changes := 0
+	v.OnChanged = func(float64) { changes++ }
+	v.DoubleTapped(&fyne.PointEvent{})
+	if v.Value != 0 || changes != 1 {
+		t.Fatalf("double tap: value=%v changes=%d", v.Value, changes)
+	}
+	v.Value = -3
+	v.TappedSecondary(&fyne.PointEvent{})
+	if v.Value != 0 || changes != 2 {
+		t.Fatalf("secondary tap: value=%v changes=%d", v.Value, changes)
+	}
+
+	off := NewVerticalSlider(10, 20)
+	off.Value = 20
+	off.DoubleTapped(&fyne.PointEvent{})
+	if off.Value != 15 {
+		t.Fatalf("range without zero resets to %v, want 15", off.Value)
+	}
+}
+
+func TestVerticalSliderDragReportsThumb(t *testing.T) {
+	test.NewApp()
+	v := NewVerticalSlider(-10, 10)
+	v.Step = 0
+	v.Resize(fyne.NewSize(20, 200))
+	var ends []bool
+	var thumbs []fyne.Position
+	v.OnDrag = func(done bool) {
+		ends = append(ends, done)
+		thumbs = append(thumbs, v.ThumbPosition())
+	}
+	v.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(10, 50)}})
+	v.DragEnd()
+	if len(ends) != 2 || ends[0] || !ends[1] {
+		t.Fatalf("OnDrag calls = %v, want [false true]", ends)
+	}
+	if v.Value != 5 || thumbs[0] != fyne.NewPos(10, 50) {
+		t.Fatalf("value=%v thumb=%v, want 5 at (10,50)", v.Value, thumbs[0])
+	}
+	v.Value = 10
+	if y := v.ThumbPosition().Y; y <= 0 || y > 10 {
+		t.Fatalf("thumb at max sits at y=%v, want just inside the top", y)
+	}
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ResizeGrip is a thin, invisible strip that shows a horizontal resize cursor
+// and reports how far it is dragged sideways. It lets a fixed-size window be
+// widened without unlocking its height or the maximize button.
+type ResizeGrip struct {
+	widget.BaseWidget
+	width float32
+
+	OnDragged func(dx float32)
+}
+
+var (
+	_ fyne.Draggable     = (*ResizeGrip)(nil)
+	_ desktop.Cursorable = (*ResizeGrip)(nil)
+)
+
+// NewResizeGrip returns a grip width wide; onDragged gets each sideways move.
+func NewResizeGrip(width float32, onDragged func(dx float32)) *ResizeGrip {
+	g := &ResizeGrip{width: width, OnDragged: onDragged}
+	g.ExtendBaseWidget(g)
+	return g
+}
+
+// CreateRenderer implements fyne.Widget.
+func (g *ResizeGrip) CreateRenderer() fyne.WidgetRenderer {
+	r := canvas.NewRectangle(color.NRGBA{0, 0, 0, 0})
+	r.SetMinSize(fyne.NewSize(g.width, 1))
+	return widget.NewSimpleRenderer(r)
+}
+
+// Cursor implements desktop.Cursorable.
+func (g *ResizeGrip) Cursor() desktop.Cursor {
+	return desktop.HResizeCursor
+}
+
+// Dragged implements fyne.Draggable.
+func (g *ResizeGrip) Dragged(ev *fyne.DragEvent) {
+	if g.OnDragged != nil && ev != nil && ev.Dragged.DX != 0 {
+		g.OnDragged(ev.Dragged.DX)
+	}
+}
+
+// DragEnd implements fyne.Draggable.
+func (g *ResizeGrip) DragEnd() {}
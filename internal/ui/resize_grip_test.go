@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+)
+
+func TestResizeGripReportsSidewaysMoves(t *testing.T) {
+	var got []float32
+	g := NewResizeGrip(4, func(dx float32) { got = append(got, dx) })
+	g.Dragged(&fyne.DragEvent{Dragged: fyne.NewDelta(12, 3)})
+	g.Dragged(&fyne.DragEvent{Dragged: fyne.NewDelta(0, 5)})
+	g.Dragged(&fyne.DragEvent{Dragged: fyne.NewDelta(-7, 0)})
+	g.DragEnd()
+	if len(got) != 2 || got[0] != 12 || got[1] != -7 {
+		t.Fatalf("got %v, want [12 -7]", got)
+	}
+}
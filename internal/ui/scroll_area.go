@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ScrollArea wraps content and reports mouse wheel events over it. Scrollable
+// content (a slider, a scroll container) still receives its own events, since
+// fyne delivers them to the innermost scrollable object under the pointer.
+type ScrollArea struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+
+	OnScrolled func(*fyne.ScrollEvent)
+}
+
+var _ fyne.Scrollable = (*ScrollArea)(nil)
+
+// NewScrollArea wraps content with a wheel callback.
+func NewScrollArea(content fyne.CanvasObject, onScrolled func(*fyne.ScrollEvent)) *ScrollArea {
+	s := &ScrollArea{content: content, OnScrolled: onScrolled}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// CreateRenderer implements fyne.Widget.
+func (s *ScrollArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(s.content)
+}
+
+// Scrolled implements fyne.Scrollable.
+func (s *ScrollArea) Scrolled(ev *fyne.ScrollEvent) {
+	if s.OnScrolled != nil && ev != nil {
+		s.OnScrolled(ev)
+	}
+}
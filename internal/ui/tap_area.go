@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TapArea wraps content and reports taps on it. Hover and scroll events still
+// reach content, since fyne delivers each kind to the innermost object that
+// handles it.
+type TapArea struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+
+	OnTapped func()
+}
+
+var _ fyne.Tappable = (*TapArea)(nil)
+
+// NewTapArea wraps content with a tap callback, which may be nil.
+func NewTapArea(content fyne.CanvasObject, onTapped func()) *TapArea {
+	t := &TapArea{content: content, OnTapped: onTapped}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+// CreateRenderer implements fyne.Widget.
+func (t *TapArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(t.content)
+}
+
+// Tapped implements fyne.Tappable.
+func (t *TapArea) Tapped(*fyne.PointEvent) {
+	if t.OnTapped != nil {
+		t.OnTapped()
+	}
+}
@@ -0,0 +1,23 @@
+package ui
+
+import "fyne.io/fyne/v2"
+
+// scaledTheme is a theme wrapper that multiplies every size (text, icons,
+// padding), so the controls grow or shrink together with the strip.
+type scaledTheme struct {
+	fyne.Theme
+	scale float32
+}
+
+func (t scaledTheme) Size(n fyne.ThemeSizeName) float32 {
+	return t.Theme.Size(n) * t.scale
+}
+
+// ScaledTheme wraps base so all its sizes are multiplied by scale; a scale
+// of 1 (or less than 0) returns base unchanged.
+func ScaledTheme(base fyne.Theme, scale float32) fyne.Theme {
+	if scale <= 0 || scale == 1 {
+		return base
+	}
+	return scaledTheme{Theme: base, scale: scale}
+}
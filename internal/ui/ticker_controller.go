@@ -18,14 +18,31 @@ type TickerController struct {
 	mu       sync.Mutex
 	cancel   context.CancelFunc
 	lastText string
+	// paused holds the scroll at its current offset without stopping the
+	// goroutine, so Resume continues where it left off
+	paused bool
 
 	bind binding.String // thread-safe string binding to update label from any goroutine
 
 	// tuning
-	speed   time.Duration // interval between steps
-	padding string        // spaces padding for cyclic scroll
+	speed     time.Duration   // interval between steps
+	padding   string          // spaces padding for cyclic scroll
+	direction ScrollDirection // which way the text moves
 }
 
+// ScrollDirection selects which way overflowing ticker text moves.
+type ScrollDirection int
+
+const (
+	// ScrollLeft moves text right-to-left, the classic marquee.
+	ScrollLeft ScrollDirection = iota
+	// ScrollRight moves text left-to-right.
+	ScrollRight
+)
+
+// DefaultTickerSpeed is the default interval between scroll steps.
+const DefaultTickerSpeed = 120 * time.Millisecond
+
 // NewTickerController creates a controller for the given label and measuring parent.
 func NewTickerController(lbl *widget.Label, parent fyne.CanvasObject) *TickerController {
 	b := binding.NewString()
@@ -35,11 +52,76 @@ func NewTickerController(lbl *widget.Label, parent fyne.CanvasObject) *TickerCon
 		lbl:     lbl,
 		parent:  parent,
 		bind:    b,
-		speed:   120 * time.Millisecond,
+		speed:   DefaultTickerSpeed,
 		padding: "   ",
 	}
 }
 
+// SetSpeed sets the interval between scroll steps; larger values scroll
+// slower. Non-positive values restore DefaultTickerSpeed. A running scroll
+// restarts with the new interval.
+func (tc *TickerController) SetSpeed(d time.Duration) {
+	if d <= 0 {
+		d = DefaultTickerSpeed
+	}
+	tc.mu.Lock()
+	changed := tc.speed != d
+	tc.speed = d
+	tc.mu.Unlock()
+	if changed {
+		tc.restart()
+	}
+}
+
+// SetPadding sets the gap inserted between the end of the text and its next
+// repetition while scrolling.
+func (tc *TickerController) SetPadding(p string) {
+	tc.mu.Lock()
+	changed := tc.padding != p
+	tc.padding = p
+	tc.mu.Unlock()
+	if changed {
+		tc.restart()
+	}
+}
+
+// SetDirection sets which way overflowing text scrolls.
+func (tc *TickerController) SetDirection(d ScrollDirection) {
+	tc.mu.Lock()
+	changed := tc.direction != d
+	tc.direction = d
+	tc.mu.Unlock()
+	if changed {
+		tc.restart()
+	}
+}
+
+// Pause freezes an active scroll at its current position, e.g. while the
+// pointer hovers the ticker so a long title can be read.
+func (tc *TickerController) Pause() {
+	tc.mu.Lock()
+	tc.paused = true
+	tc.mu.Unlock()
+}
+
+// Resume continues a paused scroll from where it stopped.
+func (tc *TickerController) Resume() {
+	tc.mu.Lock()
+	tc.paused = false
+	tc.mu.Unlock()
+}
+
+// restart re-renders the current text so an active scroll picks up new
+// tuning; the previous goroutine is cancelled by SetText.
+func (tc *TickerController) restart() {
+	tc.mu.Lock()
+	text, scrolling := tc.lastText, tc.cancel != nil
+	tc.mu.Unlock()
+	if scrolling {
+		tc.SetText(text)
+	}
+}
+
 // Close stops any scrolling goroutine.
 func (tc *TickerController) Close() {
 	tc.mu.Lock()
@@ -67,6 +149,7 @@ func (tc *TickerController) SetText(text string) {
 	parent := tc.parent
 	speed := tc.speed
 	padding := tc.padding
+	direction := tc.direction
 	b := tc.bind
 	tc.mu.Unlock()
 
@@ -103,28 +186,41 @@ func (tc *TickerController) SetText(text string) {
 				tc.mu.Lock()
 				stopped := tc.cancel == nil
 				current := tc.lastText
+				paused := tc.paused
 				tc.mu.Unlock()
 				if stopped || current != orig {
 					return
 				}
+				if paused {
+					continue
+				}
 				if !tickerNeedsScroll(neededW, parent.Size().Width) { // now fits -> stop
 					return
 				}
 
-				// rotate left by one rune
 				offset = (offset + 1) % len(workRunes)
-				var display string
-				if offset == 0 {
-					display = string(workRunes)
-				} else {
-					display = string(workRunes[offset:]) + string(workRunes[:offset])
-				}
-				_ = b.Set(display)
+				_ = b.Set(tickerFrame(workRunes, offset, direction))
 			}
 		}
 	}()
 }
 
+// tickerFrame renders work rotated by step runes in the scroll direction.
+func tickerFrame(work []rune, step int, dir ScrollDirection) string {
+	n := len(work)
+	if n == 0 {
+		return ""
+	}
+	offset := step % n
+	if dir == ScrollRight {
+		offset = (n - offset) % n
+	}
+	if offset == 0 {
+		return string(work)
+	}
+	return string(work[offset:]) + string(work[:offset])
+}
+
 // measureLabelTextWidth estimates the width the label would need for the text.
 func measureLabelTextWidth(lbl *widget.Label, text string) float32 {
 	if lbl == nil {
@@ -24,3 +24,28 @@ func TestTickerNeedsScroll(t *testing.T) {
 		})
 	}
 }
+
+func TestTickerFrame(t *testing.T) {
+	work := []rune("abcd")
+	tests := []struct {
+		step int
+		dir  ScrollDirection
+		want string
+	}{
+		{step: 0, dir: ScrollLeft, want: "abcd"},
+		{step: 1, dir: ScrollLeft, want: "bcda"},
+		{step: 3, dir: ScrollLeft, want: "dabc"},
+		{step: 4, dir: ScrollLeft, want: "abcd"},
+		{step: 1, dir: ScrollRight, want: "dabc"},
+		{step: 2, dir: ScrollRight, want: "cdab"},
+		{step: 4, dir: ScrollRight, want: "abcd"},
+	}
+	for _, tt := range tests {
+		if got := tickerFrame(work, tt.step, tt.dir); got != tt.want {
+			t.Fatalf("tickerFrame(%d, %v) = %q, want %q", tt.step, tt.dir, got, tt.want)
+		}
+	}
+	if got := tickerFrame(nil, 1, ScrollLeft); got != "" {
+		t.Fatalf("empty frame = %q", got)
+	}
+}
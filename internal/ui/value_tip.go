@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+)
+
+// valueTipGap is the space between the tip and the point it labels.
+const valueTipGap = 10
+
+// ValueTip is a small floating label, such as the value under a slider thumb
+// while it is dragged. Stack its CanvasObject over the controls it labels; it
+// holds no widgets that take input, so taps and drags pass through to them.
+type ValueTip struct {
+	box  *fyne.Container
+	bg   *canvas.Rectangle
+	text *canvas.Text
+}
+
+// NewValueTip creates a hidden tip.
+func NewValueTip() *ValueTip {
+	t := &ValueTip{
+		bg:   canvas.NewRectangle(theme.OverlayBackgroundColor()),
+		text: canvas.NewText("", theme.ForegroundColor()),
+	}
+	t.bg.CornerRadius = 4
+	t.bg.StrokeColor = theme.ShadowColor()
+	t.bg.StrokeWidth = 1
+	t.text.TextSize = theme.CaptionTextSize()
+	t.box = container.NewWithoutLayout(t.bg, t.text)
+	t.bg.Hide()
+	t.text.Hide()
+	return t
+}
+
+// CanvasObject returns the overlay to stack above the labelled controls.
+func (t *ValueTip) CanvasObject() fyne.CanvasObject { return t.box }
+
+// ShowAt shows text beside at, given in the overlay's coordinates: to the
+// right of it, or to the left where the right would run off the overlay.
+func (t *ValueTip) ShowAt(text string, at fyne.Position) {
+	t.text.Text = text
+	t.text.Color = theme.ForegroundColor()
+	t.bg.FillColor = theme.OverlayBackgroundColor()
+	pad := theme.InnerPadding() / 2
+	ts := t.text.MinSize()
+	size := fyne.NewSize(ts.Width+2*pad, ts.Height+pad)
+	t.bg.Resize(size)
+	t.bg.Move(valueTipPosition(at, size, t.box.Size()))
+	t.text.Resize(ts)
+	t.text.Move(t.bg.Position().Add(fyne.NewPos(pad, pad/2)))
+	t.bg.Show()
+	t.text.Show()
+	t.bg.Refresh()
+	t.text.Refresh()
+}
+
+// Hide removes the tip until the next ShowAt.
+func (t *ValueTip) Hide() {
+	t.bg.Hide()
+	t.text.Hide()
+}
+
+// valueTipPosition places a tip of size beside at, centred on it vertically,
+// on whichever side fits inside area, and clamped to its top and bottom.
+func valueTipPosition(at fyne.Position, size, area fyne.Size) fyne.Position {
+	x := at.X + valueTipGap
+	if x+size.Width > area.Width && at.X-valueTipGap-size.Width >= 0 {
+		x = at.X - valueTipGap - size.Width
+	}
+	y := at.Y - size.Height/2
+	if y+size.Height > area.Height {
+		y = area.Height - size.Height
+	}
+	if y < 0 {
+		y = 0
+	}
+	return fyne.NewPos(x, y)
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+)
+
+func TestValueTipPosition(t *testing.T) {
+	area := fyne.NewSize(200, 100)
+	size := fyne.NewSize(40, 20)
+	tests := []struct {
+		name string
+		at   fyne.Position
+		want fyne.Position
+	}{
+		{name: "right of the point", at: fyne.NewPos(50, 50), want: fyne.NewPos(60, 40)},
+		{name: "left near the right edge", at: fyne.NewPos(180, 50), want: fyne.NewPos(130, 40)},
+		{name: "clamped to the top", at: fyne.NewPos(50, 2), want: fyne.NewPos(60, 0)},
+		{name: "clamped to the bottom", at: fyne.NewPos(50, 98), want: fyne.NewPos(60, 80)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := valueTipPosition(tt.at, size, area); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}